@@ -0,0 +1,43 @@
+// Command stafftoken issues the bearer token staff send to the admin API
+// (/api/admin and /api/whatsapp/admin). It signs with JWT_SECRET from the
+// server's environment, so run it where the server's .env is:
+//
+//	go run ./cmd/stafftoken -name reception
+//
+// The token is valid for JWT_EXPIRATION_HOURS unless -hours is given.
+package main
+
+import (
+    "flag"
+    "fmt"
+    "log"
+    "time"
+    
+    "clinic-chatbot-backend/config"
+    "clinic-chatbot-backend/middleware"
+)
+
+func main() {
+    name := flag.String("name", "", "staff member or desk the token is for")
+    hours := flag.Int("hours", 0, "hours the token is valid (default JWT_EXPIRATION_HOURS)")
+    flag.Parse()
+    
+    if *name == "" {
+        log.Fatal("-name is required")
+    }
+    if err := config.Load(); err != nil {
+        log.Fatalf("Failed to load configuration: %v", err)
+    }
+    cfg := config.Get()
+    
+    ttl := time.Duration(cfg.JWT.ExpirationHours) * time.Hour
+    if *hours > 0 {
+        ttl = time.Duration(*hours) * time.Hour
+    }
+    
+    token, err := middleware.IssueToken(*name, cfg.JWT.Secret, ttl, time.Now())
+    if err != nil {
+        log.Fatalf("Failed to issue token: %v", err)
+    }
+    fmt.Println(token)
+}
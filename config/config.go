@@ -16,6 +16,12 @@ type Config struct {
     Port        string
     Environment string
     
+    // Default ISO country for phone numbers written without a country code
+    Region string
+    
+    // Clinic time zone from CLINIC_TIMEZONE, loaded once here
+    Location *time.Location
+    
     // Database
     Database DatabaseConfig
     
@@ -31,6 +37,12 @@ type Config struct {
     // SMS Service
     SMS SMSConfig
     
+    // Emergency triage
+    Emergency EmergencyConfig
+    
+    // Appointment booking
+    Booking BookingConfig
+    
     // File Storage
     Storage StorageConfig
     
@@ -69,21 +81,50 @@ type JWTConfig struct {
 }
 
 type EmailConfig struct {
-    Provider     string // "smtp", "sendgrid", "ses"
-    SMTPHost     string
-    SMTPPort     int
-    Username     string
-    Password     string
-    FromEmail    string
-    FromName     string
-    APIKey       string // For services like SendGrid
+    Provider      string   // "smtp", "sendgrid", "ses"
+    SMTPHost      string
+    SMTPPort      int
+    Username      string
+    Password      string
+    FromEmail     string
+    FromName      string
+    APIKey        string   // For services like SendGrid
+    BookingNotify []string // Front desk addresses sent appointment confirmations
 }
 
 type SMSConfig struct {
-    Provider    string // "twilio", "nexmo"
-    AccountSID  string
-    AuthToken   string
-    FromNumber  string
+    Provider      string        // "twilio", "nexmo"
+    AccountSID    string
+    AuthToken     string
+    FromNumber    string
+    WebhookURL    string        // Public inbound webhook URL, used to verify Twilio signatures
+    FallbackAfter time.Duration // Resend undelivered critical WhatsApp messages by SMS after this
+}
+
+type EmergencyConfig struct {
+    Region         string   // ISO country code selecting the public emergency number
+    Number         string   // Overrides the region's public emergency number
+    ClinicLine     string   // Clinic's own emergency line
+    StaffWhatsApp  []string // On-call staff alerted on WhatsApp
+    StaffTemplate  string   // Approved WhatsApp template for staff alerts: {{1}} subject, {{2}} details
+    StaffSMS       []string // On-call staff alerted by SMS
+    StaffEmails    []string // On-call staff alerted by email
+    AckTimeout     time.Duration
+    MaxEscalations int
+}
+
+type BookingConfig struct {
+    SearchDays        int           // Days ahead the earliest-available search looks
+    SearchConcurrency int           // Parallel HMS requests per search
+    SearchCacheTTL    time.Duration // How long doctor lists and free slots are reused
+    HoldTTL           time.Duration // How long slots shown to a patient are kept from others
+    SlotDuration      time.Duration // Slot length when HMS does not give one
+    SlotBuffer        time.Duration // Gap after each slot
+    SlotBreaks        []string      // Daily breaks without slots, e.g. "13:00-14:00"
+    WaitlistDays      int           // Days a waitlist entry covers
+    WaitlistPoll      time.Duration // How often HMS is checked for slots for waitlisted patients
+    WaitlistOfferTTL  time.Duration // How long an opened slot is held for the patient offered it
+    WaitlistTemplate  string        // Approved template for offers outside the service window: {{1}} doctor, {{2}} date, {{3}} time, {{4}} minutes held
 }
 
 type StorageConfig struct {
@@ -102,6 +143,7 @@ type SecurityConfig struct {
     RateLimitPerMin  int
     AllowedOrigins   []string
     TrustedProxies   []string
+    HMSWebhookSecret string // Shared secret HMS signs its webhook calls with
 }
 
 var cfg *Config
@@ -116,6 +158,7 @@ func Load() error {
     cfg = &Config{
         Port:        getEnv("PORT", "8080"),
         Environment: getEnv("ENVIRONMENT", "development"),
+        Region:      getEnv("DEFAULT_REGION", "IN"),
         
         Database: DatabaseConfig{
             Type:     getEnv("DB_TYPE", "mongodb"),
@@ -147,21 +190,50 @@ func Load() error {
         },
         
         Email: EmailConfig{
-            Provider:  getEnv("EMAIL_PROVIDER", "smtp"),
-            SMTPHost:  getEnv("SMTP_HOST", "smtp.gmail.com"),
-            SMTPPort:  getEnvAsInt("SMTP_PORT", 587),
-            Username:  getEnv("SMTP_USERNAME", ""),
-            Password:  getEnv("SMTP_PASSWORD", ""),
-            FromEmail: getEnv("EMAIL_FROM", "noreply@clinic.com"),
-            FromName:  getEnv("EMAIL_FROM_NAME", "HealthCare Clinic"),
-            APIKey:    getEnv("EMAIL_API_KEY", ""),
+            Provider:      getEnv("EMAIL_PROVIDER", "smtp"),
+            SMTPHost:      getEnv("SMTP_HOST", ""),
+            SMTPPort:      getEnvAsInt("SMTP_PORT", 587),
+            Username:      getEnv("SMTP_USERNAME", ""),
+            Password:      getEnv("SMTP_PASSWORD", ""),
+            FromEmail:     getEnv("EMAIL_FROM", "noreply@clinic.com"),
+            FromName:      getEnv("EMAIL_FROM_NAME", "HealthCare Clinic"),
+            APIKey:        getEnv("EMAIL_API_KEY", ""),
+            BookingNotify: getEnvAsSlice("BOOKING_NOTIFY_EMAILS", []string{}),
         },
         
         SMS: SMSConfig{
-            Provider:   getEnv("SMS_PROVIDER", "twilio"),
-            AccountSID: getEnv("TWILIO_ACCOUNT_SID", ""),
-            AuthToken:  getEnv("TWILIO_AUTH_TOKEN", ""),
-            FromNumber: getEnv("TWILIO_FROM_NUMBER", ""),
+            Provider:      getEnv("SMS_PROVIDER", "twilio"),
+            AccountSID:    getEnv("TWILIO_ACCOUNT_SID", ""),
+            AuthToken:     getEnv("TWILIO_AUTH_TOKEN", ""),
+            FromNumber:    getEnv("TWILIO_FROM_NUMBER", ""),
+            WebhookURL:    getEnv("TWILIO_WEBHOOK_URL", ""),
+            FallbackAfter: getEnvAsDuration("SMS_FALLBACK_AFTER", "10m"),
+        },
+        
+        Emergency: EmergencyConfig{
+            Region:         getEnv("EMERGENCY_REGION", getEnv("DEFAULT_REGION", "IN")),
+            Number:         getEnv("EMERGENCY_NUMBER", ""),
+            ClinicLine:     getEnv("CLINIC_EMERGENCY_LINE", ""),
+            StaffWhatsApp:  getEnvAsSlice("ONCALL_WHATSAPP", []string{}),
+            StaffTemplate:  getEnv("ONCALL_WHATSAPP_TEMPLATE", ""),
+            StaffSMS:       getEnvAsSlice("ONCALL_SMS", []string{}),
+            StaffEmails:    getEnvAsSlice("ONCALL_EMAILS", []string{}),
+            AckTimeout:     getEnvAsDuration("EMERGENCY_ACK_TIMEOUT", "5m"),
+            MaxEscalations: getEnvAsInt("EMERGENCY_MAX_ESCALATIONS", 3),
+        },
+        
+        Booking: BookingConfig{
+            SearchDays:        getEnvAsInt("SLOT_SEARCH_DAYS", 7),
+            SearchConcurrency: getEnvAsInt("SLOT_SEARCH_CONCURRENCY", 4),
+            SearchCacheTTL:    getEnvAsDuration("SLOT_SEARCH_CACHE_TTL", "2m"),
+            HoldTTL:           getEnvAsDuration("SLOT_HOLD_TTL", "5m"),
+            SlotDuration:      getEnvAsDuration("SLOT_DURATION", "15m"),
+            SlotBuffer:        getEnvAsDuration("SLOT_BUFFER", "0m"),
+            SlotBreaks:        getEnvAsSlice("SLOT_BREAKS", []string{}),
+            WaitlistDays:      getEnvAsInt("WAITLIST_DAYS", 7),
+            WaitlistPoll:      getEnvAsDuration("WAITLIST_POLL_INTERVAL", "5m"),
+            WaitlistOfferTTL:  getEnvAsDuration("WAITLIST_OFFER_TTL", "15m"),
+            WaitlistTemplate:  getEnv("WAITLIST_OFFER_TEMPLATE", ""),
         },
         
         Storage: StorageConfig{
@@ -174,13 +246,21 @@ func Load() error {
         },
         
         Security: SecurityConfig{
-            BcryptCost:      getEnvAsInt("BCRYPT_COST", 10),
-            RateLimitPerMin: getEnvAsInt("RATE_LIMIT_PER_MIN", 60),
-            AllowedOrigins:  getEnvAsSlice("ALLOWED_ORIGINS", []string{"http://localhost:3000", "http://localhost:5173"}),
-            TrustedProxies:  getEnvAsSlice("TRUSTED_PROXIES", []string{}),
+            BcryptCost:       getEnvAsInt("BCRYPT_COST", 10),
+            RateLimitPerMin:  getEnvAsInt("RATE_LIMIT_PER_MIN", 60),
+            AllowedOrigins:   getEnvAsSlice("ALLOWED_ORIGINS", []string{"http://localhost:3000", "http://localhost:5173"}),
+            TrustedProxies:   getEnvAsSlice("TRUSTED_PROXIES", []string{}),
+            HMSWebhookSecret: getEnv("HMS_WEBHOOK_SECRET", ""),
         },
     }
     
+    // A wrong time zone would shift every slot and opening hour, so refuse it
+    location, err := time.LoadLocation(getEnv("CLINIC_TIMEZONE", "Asia/Kolkata"))
+    if err != nil {
+        return fmt.Errorf("invalid CLINIC_TIMEZONE: %w", err)
+    }
+    cfg.Location = location
+    
     // Validate configuration
     if err := validate(); err != nil {
         return fmt.Errorf("configuration validation failed: %w", err)
@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CampaignController struct {
+	campaignService *services.CampaignService
+	whatsappCtrl    *WhatsAppController
+}
+
+func NewCampaignController(campaignService *services.CampaignService, whatsappCtrl *WhatsAppController) *CampaignController {
+	return &CampaignController{
+		campaignService: campaignService,
+		whatsappCtrl:    whatsappCtrl,
+	}
+}
+
+// CreateCampaign starts a campaign from a JSON recipient list or an HMS patient filter
+func (cc *CampaignController) CreateCampaign(c *gin.Context) {
+	var req models.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	source := "manual"
+	recipients := req.Recipients
+	if req.HMSFilter != nil {
+		patients, err := cc.findHMSRecipients(*req.HMSFilter)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch patients from HMS", "details": err.Error()})
+			return
+		}
+		source = "hms"
+		recipients = append(recipients, patients...)
+	}
+
+	campaign := models.Campaign{
+		Name:      req.Name,
+		Template:  req.Template,
+		Source:    source,
+		CreatedBy: req.CreatedBy,
+	}
+	cc.create(c, campaign, recipients)
+}
+
+// UploadCampaign starts a campaign from a CSV file.
+// Columns: phone, name (optional), then per-recipient template params (optional).
+func (cc *CampaignController) UploadCampaign(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "CSV file is required"})
+		return
+	}
+
+	var template models.TemplateMessage
+	if err := json.Unmarshal([]byte(c.PostForm("template")), &template); err != nil || template.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "template must be a JSON template message"})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read CSV file", "details": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	recipients, err := parseRecipientsCSV(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CSV file", "details": err.Error()})
+		return
+	}
+
+	campaign := models.Campaign{
+		Name:      c.PostForm("name"),
+		Template:  template,
+		Source:    "csv",
+		CreatedBy: c.PostForm("created_by"),
+	}
+	if campaign.Name == "" {
+		campaign.Name = file.Filename
+	}
+	cc.create(c, campaign, recipients)
+}
+
+// ListCampaigns lists recent campaigns
+func (cc *CampaignController) ListCampaigns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	campaigns, err := cc.campaignService.List(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve campaigns", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"campaigns": campaigns})
+}
+
+// GetCampaign reports the progress of a campaign
+func (cc *CampaignController) GetCampaign(c *gin.Context) {
+	campaign, err := cc.campaignService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		cc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"campaign": campaign,
+		"progress": gin.H{
+			"total":      campaign.Total,
+			"sent":       campaign.Sent,
+			"failed":     campaign.Failed,
+			"suppressed": campaign.Suppressed,
+			"pending":    campaign.Pending(),
+		},
+	})
+}
+
+// ListRecipients returns per-recipient status of a campaign
+func (cc *CampaignController) ListRecipients(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	recipients, err := cc.campaignService.ListRecipients(c.Request.Context(), c.Param("id"),
+		models.RecipientStatus(c.Query("status")), page, limit)
+	if err != nil {
+		cc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recipients": recipients, "page": page, "limit": limit})
+}
+
+// CancelCampaign stops a running campaign
+func (cc *CampaignController) CancelCampaign(c *gin.Context) {
+	campaign, err := cc.campaignService.Cancel(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		cc.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, campaign)
+}
+
+func (cc *CampaignController) create(c *gin.Context, campaign models.Campaign, recipients []models.CampaignRecipient) {
+	created, err := cc.campaignService.Create(c.Request.Context(), campaign, recipients)
+
+	var validationErr *services.TemplateValidationError
+	if errors.Is(err, services.ErrTemplateNotFound) || errors.As(err, &validationErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template message", "details": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to create campaign", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, created)
+}
+
+func (cc *CampaignController) respondError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrCampaignNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process campaign", "details": err.Error()})
+}
+
+// findHMSRecipients searches HMS patients and applies the age filter
+func (cc *CampaignController) findHMSRecipients(filter models.HMSPatientFilter) ([]models.CampaignRecipient, error) {
+	patients, err := cc.whatsappCtrl.verifyPatientCode(filter.Search)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := []models.CampaignRecipient{}
+	for _, p := range patients {
+		if filter.MinAge > 0 || filter.MaxAge > 0 {
+			age, ok := ageFromDOB(p.DateOfBirth)
+			if !ok || (filter.MinAge > 0 && age < filter.MinAge) || (filter.MaxAge > 0 && age > filter.MaxAge) {
+				continue
+			}
+		}
+		recipients = append(recipients, models.CampaignRecipient{
+			Phone: p.MobileNumber,
+			Name:  strings.TrimSpace(fmt.Sprintf("%s %s", p.FirstName, p.LastName)),
+		})
+	}
+	return recipients, nil
+}
+
+// ageFromDOB computes age in years from an HMS date of birth
+func ageFromDOB(dob string) (int, bool) {
+	var born time.Time
+	var err error
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02"} {
+		if born, err = time.Parse(layout, dob); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return 0, false
+	}
+
+	now := time.Now()
+	age := now.Year() - born.Year()
+	if now.YearDay() < born.YearDay() {
+		age--
+	}
+	return age, true
+}
+
+// parseRecipientsCSV reads phone,name,params... rows, skipping an optional header row
+func parseRecipientsCSV(r io.Reader) ([]models.CampaignRecipient, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := []models.CampaignRecipient{}
+	for i, row := range rows {
+		if len(row) == 0 || strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		if i == 0 && strings.EqualFold(strings.TrimSpace(row[0]), "phone") {
+			continue
+		}
+
+		recipient := models.CampaignRecipient{Phone: row[0]}
+		if len(row) > 1 {
+			recipient.Name = row[1]
+		}
+		if len(row) > 2 {
+			recipient.Params = row[2:]
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no recipients found")
+	}
+	return recipients, nil
+}
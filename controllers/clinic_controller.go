@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClinicController struct {
+	clinicService *services.ClinicService
+}
+
+func NewClinicController(clinicService *services.ClinicService) *ClinicController {
+	return &ClinicController{
+		clinicService: clinicService,
+	}
+}
+
+// GetProfile returns the stored clinic profile
+func (cc *ClinicController) GetProfile(c *gin.Context) {
+	profile, err := cc.clinicService.Get(c.Request.Context())
+	cc.respond(c, http.StatusOK, profile, err)
+}
+
+// CreateProfile stores the clinic profile; only one may exist
+func (cc *ClinicController) CreateProfile(c *gin.Context) {
+	var req models.ClinicProfile
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	profile, err := cc.clinicService.Create(c.Request.Context(), req)
+	cc.respond(c, http.StatusCreated, profile, err)
+}
+
+// UpdateProfile replaces the stored clinic profile
+func (cc *ClinicController) UpdateProfile(c *gin.Context) {
+	var req models.ClinicProfile
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	profile, err := cc.clinicService.Update(c.Request.Context(), req)
+	cc.respond(c, http.StatusOK, profile, err)
+}
+
+// DeleteProfile removes the clinic profile; the chatbot reverts to defaults
+func (cc *ClinicController) DeleteProfile(c *gin.Context) {
+	err := cc.clinicService.Delete(c.Request.Context())
+	cc.respond(c, http.StatusOK, gin.H{"deleted": true}, err)
+}
+
+// respond writes the result or the matching error response
+func (cc *ClinicController) respond(c *gin.Context, status int, obj interface{}, err error) {
+	var validationErr *services.ClinicValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clinic profile", "details": validationErr.Reason})
+	case errors.Is(err, services.ErrClinicProfileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Clinic profile not found"})
+	case errors.Is(err, services.ErrClinicProfileExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "Clinic profile already exists"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Clinic profile request failed",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(status, obj)
+	}
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"net/http"
+
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ContactController struct {
+	contactService  *services.ContactService
+	whatsappService *services.WhatsAppService
+}
+
+func NewContactController(contactService *services.ContactService, whatsappService *services.WhatsAppService) *ContactController {
+	return &ContactController{
+		contactService:  contactService,
+		whatsappService: whatsappService,
+	}
+}
+
+// GetContact returns a contact with its consent status and history
+func (cc *ContactController) GetContact(c *gin.Context) {
+	phone := cc.whatsappService.NormalizePhone(c.Param("phone"))
+
+	contact, err := cc.contactService.Get(c.Request.Context(), phone)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve contact",
+			"details": err.Error(),
+		})
+		return
+	}
+	if contact == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, contact)
+}
+
+// UpdateConsent records an opt-in or opt-out on behalf of a contact
+func (cc *ContactController) UpdateConsent(c *gin.Context) {
+	var req models.UpdateConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if req.Source == "" {
+		req.Source = models.OptInSourceAdmin
+	}
+
+	phone := cc.whatsappService.NormalizePhone(c.Param("phone"))
+
+	var contact *models.Contact
+	var err error
+	if req.Status == "opted_in" {
+		contact, err = cc.contactService.OptIn(c.Request.Context(), phone, req.Source)
+	} else {
+		contact, err = cc.contactService.OptOut(c.Request.Context(), phone, req.Source)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update consent",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, contact)
+}
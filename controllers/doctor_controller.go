@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DoctorController struct {
+	doctorService *services.DoctorService
+}
+
+func NewDoctorController(doctorService *services.DoctorService) *DoctorController {
+	return &DoctorController{
+		doctorService: doctorService,
+	}
+}
+
+// ListDoctors returns the doctor directory. Query: department_id.
+func (dc *DoctorController) ListDoctors(c *gin.Context) {
+	dc.list(c, false)
+}
+
+// ListAllDoctors returns every profile for staff, hidden ones included.
+// Query: department_id.
+func (dc *DoctorController) ListAllDoctors(c *gin.Context) {
+	dc.list(c, true)
+}
+
+func (dc *DoctorController) list(c *gin.Context, includeHidden bool) {
+	departmentID, _ := strconv.Atoi(c.Query("department_id"))
+
+	doctors, err := dc.doctorService.List(c.Request.Context(), departmentID, includeHidden)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list doctors",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"doctors": doctors})
+}
+
+// ListDepartments returns the departments that have doctors in the directory
+func (dc *DoctorController) ListDepartments(c *gin.Context) {
+	departments, err := dc.doctorService.Departments(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list departments",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"departments": departments})
+}
+
+// GetDoctor returns a directory profile by HMS employee ID
+func (dc *DoctorController) GetDoctor(c *gin.Context) {
+	dc.get(c, false)
+}
+
+// GetAnyDoctor returns a profile for staff, even when hidden
+func (dc *DoctorController) GetAnyDoctor(c *gin.Context) {
+	dc.get(c, true)
+}
+
+func (dc *DoctorController) get(c *gin.Context, includeHidden bool) {
+	employeeID, ok := dc.employeeID(c)
+	if !ok {
+		return
+	}
+
+	doctor, err := dc.doctorService.Get(c.Request.Context(), employeeID)
+	if err == nil && doctor.Hidden && !includeHidden {
+		err = services.ErrDoctorNotFound
+	}
+	dc.respond(c, http.StatusOK, doctor, err)
+}
+
+// CreateDoctor adds a doctor to the directory
+func (dc *DoctorController) CreateDoctor(c *gin.Context) {
+	var req models.DoctorProfile
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	doctor, err := dc.doctorService.Create(c.Request.Context(), req)
+	dc.respond(c, http.StatusCreated, doctor, err)
+}
+
+// UpdateDoctor replaces a doctor's profile
+func (dc *DoctorController) UpdateDoctor(c *gin.Context) {
+	employeeID, ok := dc.employeeID(c)
+	if !ok {
+		return
+	}
+
+	var req models.DoctorProfile
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	doctor, err := dc.doctorService.Update(c.Request.Context(), employeeID, req)
+	dc.respond(c, http.StatusOK, doctor, err)
+}
+
+// DeleteDoctor removes a doctor from the directory
+func (dc *DoctorController) DeleteDoctor(c *gin.Context) {
+	employeeID, ok := dc.employeeID(c)
+	if !ok {
+		return
+	}
+
+	err := dc.doctorService.Delete(c.Request.Context(), employeeID)
+	dc.respond(c, http.StatusOK, gin.H{"deleted": true}, err)
+}
+
+func (dc *DoctorController) employeeID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "id must be the HMS employee ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// respond writes the result or the matching error response
+func (dc *DoctorController) respond(c *gin.Context, status int, obj interface{}, err error) {
+	var validationErr *services.DoctorValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor profile", "details": validationErr.Reason})
+	case errors.Is(err, services.ErrDoctorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Doctor not found"})
+	case errors.Is(err, services.ErrDoctorExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "Doctor profile already exists"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Doctor profile request failed",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(status, obj)
+	}
+}
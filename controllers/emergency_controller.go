@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmergencyController struct {
+	emergencyService *services.EmergencyService
+}
+
+func NewEmergencyController(emergencyService *services.EmergencyService) *EmergencyController {
+	return &EmergencyController{
+		emergencyService: emergencyService,
+	}
+}
+
+// ListAlerts returns emergency alerts, newest first. Query: status, limit.
+func (ec *EmergencyController) ListAlerts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	status := models.AlertStatus(c.Query("status"))
+
+	alerts, err := ec.emergencyService.List(c.Request.Context(), status, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list emergency alerts",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"alerts": alerts})
+}
+
+// GetAlert returns a single alert with its delivery history
+func (ec *EmergencyController) GetAlert(c *gin.Context) {
+	alert, err := ec.emergencyService.Get(c.Request.Context(), c.Param("id"))
+	ec.respond(c, alert, err)
+}
+
+// AcknowledgeAlert stops escalation of an alert
+func (ec *EmergencyController) AcknowledgeAlert(c *gin.Context) {
+	var req models.AcknowledgeAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	alert, err := ec.emergencyService.Acknowledge(c.Request.Context(), c.Param("id"), req.Agent)
+	ec.respond(c, alert, err)
+}
+
+// respond writes an alert or the matching error response
+func (ec *EmergencyController) respond(c *gin.Context, alert *models.EmergencyAlert, err error) {
+	if errors.Is(err, services.ErrAlertNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Emergency alert not found"})
+		return
+	}
+	if errors.Is(err, services.ErrAlertAcknowledged) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Emergency alert already acknowledged"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update emergency alert",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, alert)
+}
@@ -0,0 +1,413 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"clinic-chatbot-backend/i18n"
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+)
+
+// Reply IDs of the household list: saved patients "member_<patient id>", a
+// row to find someone else in HMS and one to register a family member.
+// Relation list rows are "relation_<relation>", and the buttons confirming
+// a number's only patient "patient_yes" and "patient_other".
+const (
+	householdMemberPrefix = "member_"
+	householdOtherRow     = "member_other"
+	householdAddRow       = "member_add"
+	relationRowPrefix     = "relation_"
+	confirmPatientButton  = "patient_yes"
+	otherPatientButton    = "patient_other"
+)
+
+// Most saved patients listed; the other two of the list's 10 rows are fixed
+const householdListLimit = 8
+
+// offerHousehold asks which saved patient a booking is for, or picks the one
+// a request named ("book for my daughter"). False when the number has none.
+func (wc *WhatsAppController) offerHousehold(userID string, state *AppointmentData) bool {
+	household, err := wc.householdService.Get(context.Background(), userID)
+	if err != nil {
+		if !errors.Is(err, services.ErrHouseholdNotFound) {
+			log.Println("household error:", err)
+		}
+		return false
+	}
+	if len(household.Members) == 0 {
+		return false
+	}
+
+	if member, ok := matchMember(household.Members, state.ForRelation, state.PatientName); ok {
+		wc.bookForMember(userID, state, member)
+		return true
+	}
+	if len(household.Members) == 1 && state.ForRelation == "" && state.PatientName == "" {
+		wc.confirmMember(userID, state, household.Members[0])
+		return true
+	}
+	state.Step = "choose_household_member"
+	_ = wc.sendHouseholdList(userID, household.Members, state.ForRelation)
+	return true
+}
+
+// chooseHouseholdMember handles the answer to the household list
+func (wc *WhatsAppController) chooseHouseholdMember(userID string, state *AppointmentData, message models.WhatsAppMessage) {
+	household, err := wc.householdService.Get(context.Background(), userID)
+	if err != nil {
+		log.Println("household error:", err)
+		wc.askConsultedBefore(userID, state)
+		return
+	}
+
+	if message.Interactive != nil && message.Interactive.ListReply != nil {
+		id := message.Interactive.ListReply.ID
+		switch {
+		case id == householdAddRow:
+			wc.startAddMember(userID, state)
+			return
+		case id == householdOtherRow:
+			wc.askConsultedBefore(userID, state)
+			return
+		case strings.HasPrefix(id, householdMemberPrefix):
+			patientID, _ := strconv.Atoi(strings.TrimPrefix(id, householdMemberPrefix))
+			for _, member := range household.Members {
+				if member.PatientID == patientID {
+					wc.bookForMember(userID, state, member)
+					return
+				}
+			}
+		}
+	} else if message.Type == "text" && message.Text != nil {
+		relation, _ := i18n.FamilyRelation(message.Text.Body)
+		if member, ok := matchMember(household.Members, relation, message.Text.Body); ok {
+			wc.bookForMember(userID, state, member)
+			return
+		}
+	}
+	_ = wc.sendHouseholdList(userID, household.Members, state.ForRelation)
+}
+
+// matchMember finds the only saved patient with a relation or first name
+func matchMember(members []models.HouseholdMember, relation, name string) (models.HouseholdMember, bool) {
+	first := firstName(name)
+	var found []models.HouseholdMember
+	for _, member := range members {
+		if relation != "" && member.Relation == relation ||
+			first != "" && strings.EqualFold(firstName(member.Name), first) {
+			found = append(found, member)
+		}
+	}
+	if len(found) != 1 {
+		return models.HouseholdMember{}, false
+	}
+	return found[0], true
+}
+
+func firstName(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// bookForMember fills the booking with a saved patient and moves on
+func (wc *WhatsAppController) bookForMember(userID string, state *AppointmentData, member models.HouseholdMember) {
+	state.setPatient(member)
+	_ = wc.whatsappService.SendTextMessage(userID, i18n.T(wc.localeFor(userID), "family.booking_for", member.Name))
+	wc.continueBooking(userID, state)
+}
+
+// confirmMember asks whether the booking is for the number's only saved
+// patient. A text reply works too, for when the buttons are lost outside the
+// service window.
+func (wc *WhatsAppController) confirmMember(userID string, state *AppointmentData, member models.HouseholdMember) {
+	locale := wc.localeFor(userID)
+	state.setPatient(member)
+	state.Step = "confirm_patient"
+
+	interactive := &models.InteractiveMessage{
+		Type: "button",
+		Body: &models.InteractiveBody{
+			Text: i18n.T(locale, "family.confirm", member.Name),
+		},
+		Action: &models.InteractiveAction{
+			Buttons: []models.InteractiveButton{
+				{Type: "reply", Reply: &models.ButtonReply{ID: confirmPatientButton, Title: i18n.T(locale, "family.confirm_yes")}},
+				{Type: "reply", Reply: &models.ButtonReply{ID: otherPatientButton, Title: i18n.T(locale, "family.someone_else")}},
+			},
+		},
+	}
+	_ = wc.whatsappService.SendInteractiveMessage(userID, interactive)
+}
+
+// answerConfirmMember handles the answer to confirmMember
+func (wc *WhatsAppController) answerConfirmMember(userID string, state *AppointmentData, message models.WhatsAppMessage) {
+	answer := ""
+	if message.Interactive != nil && message.Interactive.ButtonReply != nil {
+		answer = message.Interactive.ButtonReply.ID
+	} else if message.Type == "text" && message.Text != nil {
+		switch {
+		case i18n.IsYes(message.Text.Body):
+			answer = confirmPatientButton
+		case i18n.IsNo(message.Text.Body):
+			answer = otherPatientButton
+		}
+	}
+
+	switch answer {
+	case confirmPatientButton:
+		wc.continueBooking(userID, state)
+	case otherPatientButton:
+		state.clearPatient()
+		wc.askConsultedBefore(userID, state)
+	default:
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(wc.localeFor(userID), "booking.reply_yes_no"))
+	}
+}
+
+// recognisePatient looks the sender's number up in HMS when starting a
+// booking. Patients registered with it are saved and offered; for a number HMS
+// does not know it asks whether the booking is for the sender or someone
+// else. False when HMS could not be asked.
+func (wc *WhatsAppController) recognisePatient(userID string, state *AppointmentData) bool {
+	own := wc.ownNumber(userID)
+	patients, err := wc.verifyPatientCode(own)
+	if err != nil {
+		return false
+	}
+
+	if len(patients) > 0 {
+		wc.rememberHousehold(userID, patients)
+		return wc.offerHousehold(userID, state)
+	}
+
+	locale := wc.localeFor(userID)
+	state.Step = "confirm_new_patient"
+	interactive := &models.InteractiveMessage{
+		Type: "button",
+		Body: &models.InteractiveBody{
+			Text: i18n.T(locale, "family.new_patient"),
+		},
+		Action: &models.InteractiveAction{
+			Buttons: []models.InteractiveButton{
+				{Type: "reply", Reply: &models.ButtonReply{ID: confirmPatientButton, Title: i18n.T(locale, "family.for_me")}},
+				{Type: "reply", Reply: &models.ButtonReply{ID: otherPatientButton, Title: i18n.T(locale, "family.someone_else")}},
+			},
+		},
+	}
+	_ = wc.whatsappService.SendInteractiveMessage(userID, interactive)
+	return true
+}
+
+// answerNewPatient handles the answer to recognisePatient's question. The
+// sender is registered with their own number; for someone else the usual
+// consulted-before and phone questions follow.
+func (wc *WhatsAppController) answerNewPatient(userID string, state *AppointmentData, message models.WhatsAppMessage) {
+	locale := wc.localeFor(userID)
+	answer := ""
+	if message.Interactive != nil && message.Interactive.ButtonReply != nil {
+		answer = message.Interactive.ButtonReply.ID
+	} else if message.Type == "text" && message.Text != nil {
+		switch {
+		case i18n.IsYes(message.Text.Body):
+			answer = confirmPatientButton
+		case i18n.IsNo(message.Text.Body):
+			answer = otherPatientButton
+		}
+	}
+
+	switch answer {
+	case confirmPatientButton:
+		state.PhoneNumber = wc.ownNumber(userID)
+		if state.PatientName != "" {
+			state.Step = "await_patient_address"
+			_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.ask_address"))
+			return
+		}
+		state.Step = "await_patient_name"
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.ask_name"))
+	case otherPatientButton:
+		state.clearPatient()
+		wc.askConsultedBefore(userID, state)
+	default:
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.reply_yes_no"))
+	}
+}
+
+// setPatient fills the booking with a saved patient
+func (d *AppointmentData) setPatient(member models.HouseholdMember) {
+	d.PatientID = member.PatientID
+	d.PatientCode = member.PatientCode
+	d.PatientName = member.Name
+	d.Address = member.Address
+	d.PhoneNumber = member.PhoneNumber
+	d.DateOfBirth = member.DateOfBirth
+}
+
+// clearPatient forgets the patient of a booking, e.g. to book for someone else
+func (d *AppointmentData) clearPatient() {
+	d.setPatient(models.HouseholdMember{})
+}
+
+// sendHouseholdList lists the saved patients of a number, those with the
+// relation asked for first
+func (wc *WhatsAppController) sendHouseholdList(userID string, members []models.HouseholdMember, relation string) error {
+	locale := wc.localeFor(userID)
+
+	ordered := make([]models.HouseholdMember, 0, len(members))
+	for _, member := range members {
+		if relation != "" && member.Relation == relation {
+			ordered = append(ordered, member)
+		}
+	}
+	for _, member := range members {
+		if relation == "" || member.Relation != relation {
+			ordered = append(ordered, member)
+		}
+	}
+	if len(ordered) > householdListLimit {
+		ordered = ordered[:householdListLimit]
+	}
+
+	rows := make([]models.ListItem, 0, len(ordered)+2)
+	for _, member := range ordered {
+		description := i18n.T(locale, "booking.patient_code", member.PatientCode)
+		if member.Relation != "" {
+			description = i18n.T(locale, "family.relation."+member.Relation) + " · " + description
+		}
+		rows = append(rows, models.ListItem{
+			ID:          fmt.Sprintf("%s%d", householdMemberPrefix, member.PatientID),
+			Title:       truncate(member.Name, 24),
+			Description: truncate(description, 72),
+		})
+	}
+	rows = append(rows,
+		models.ListItem{
+			ID:          householdOtherRow,
+			Title:       i18n.T(locale, "family.someone_else"),
+			Description: i18n.T(locale, "family.someone_else_desc"),
+		},
+		models.ListItem{
+			ID:          householdAddRow,
+			Title:       i18n.T(locale, "family.add"),
+			Description: i18n.T(locale, "family.add_desc"),
+		},
+	)
+
+	interactive := &models.InteractiveMessage{
+		Type: "list",
+		Body: &models.InteractiveBody{
+			Text: i18n.T(locale, "family.choose_body"),
+		},
+		Action: &models.InteractiveAction{
+			Button:   i18n.T(locale, "booking.patients_button"),
+			Sections: []models.Section{{Title: i18n.T(locale, "family.section"), Rows: rows}},
+		},
+	}
+	return wc.whatsappService.SendInteractiveMessage(userID, interactive)
+}
+
+// startAddMember registers a family member as a new HMS patient, asking for
+// their name, relation, date of birth and, when no saved patient has one,
+// their address. The phone number is the WhatsApp number's.
+func (wc *WhatsAppController) startAddMember(userID string, state *AppointmentData) {
+	state.clearPatient()
+	state.Step = "await_member_name"
+	_ = wc.whatsappService.SendTextMessage(userID, i18n.T(wc.localeFor(userID), "family.ask_name"))
+}
+
+func (wc *WhatsAppController) askMemberDateOfBirth(userID string, state *AppointmentData) {
+	state.Step = "await_member_dob"
+	_ = wc.whatsappService.SendTextMessage(userID, i18n.T(wc.localeFor(userID), "family.ask_dob", state.PatientName))
+}
+
+// sendRelationList asks how a new family member is related to the user
+func (wc *WhatsAppController) sendRelationList(userID, name string) error {
+	locale := wc.localeFor(userID)
+	rows := make([]models.ListItem, 0, len(i18n.Relations))
+	for _, relation := range i18n.Relations {
+		rows = append(rows, models.ListItem{
+			ID:    relationRowPrefix + relation,
+			Title: i18n.T(locale, "family.relation."+relation),
+		})
+	}
+
+	interactive := &models.InteractiveMessage{
+		Type: "list",
+		Body: &models.InteractiveBody{
+			Text: i18n.T(locale, "family.ask_relation", name),
+		},
+		Action: &models.InteractiveAction{
+			Button:   i18n.T(locale, "family.relation_button"),
+			Sections: []models.Section{{Rows: rows}},
+		},
+	}
+	return wc.whatsappService.SendInteractiveMessage(userID, interactive)
+}
+
+// registerMember registers the family member, with the WhatsApp number as
+// their phone
+func (wc *WhatsAppController) registerMember(ctx context.Context, userID string, state *AppointmentData) {
+	state.PhoneNumber = wc.ownNumber(userID)
+	wc.registerNewPatient(ctx, userID, state)
+}
+
+// rememberPatient saves a verified or registered patient to the household
+// of a WhatsApp number. Only patients registered with that number are kept:
+// a code or phone number typed in proves nothing about who is writing, so
+// those patients are booked once and not saved.
+func (wc *WhatsAppController) rememberPatient(userID string, patient Patient, relation string) {
+	if !wc.isOwnNumber(userID, patient.MobileNumber) {
+		return
+	}
+	err := wc.householdService.AddMember(context.Background(), userID, models.HouseholdMember{
+		PatientID:   patient.ID,
+		PatientCode: patient.PatientCode,
+		Name:        strings.TrimSpace(patient.FirstName + " " + patient.LastName),
+		Relation:    relation,
+		DateOfBirth: patient.DateOfBirth,
+		Address:     patient.Address,
+		PhoneNumber: patient.MobileNumber,
+	})
+	if err != nil {
+		log.Println("household error:", err)
+	}
+}
+
+// rememberHousehold saves the patients found in HMS that are registered with
+// the user's own WhatsApp number
+func (wc *WhatsAppController) rememberHousehold(userID string, patients []Patient) {
+	for _, patient := range patients {
+		wc.rememberPatient(userID, patient, "")
+	}
+}
+
+// householdAddress is the address of the first saved patient that has one
+func (wc *WhatsAppController) householdAddress(ctx context.Context, userID string) string {
+	household, err := wc.householdService.Get(ctx, userID)
+	if err != nil {
+		return ""
+	}
+	for _, member := range household.Members {
+		if member.Address != "" {
+			return member.Address
+		}
+	}
+	return ""
+}
+
+// ownNumber is a WhatsApp user's number the way HMS stores it
+func (wc *WhatsAppController) ownNumber(userID string) string {
+	number, err := wc.whatsappService.ParsePhone("+" + userID)
+	if err != nil {
+		return userID
+	}
+	return number.Format(wc.whatsappService.Region())
+}
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HouseholdController struct {
+	householdService *services.HouseholdService
+}
+
+func NewHouseholdController(householdService *services.HouseholdService) *HouseholdController {
+	return &HouseholdController{
+		householdService: householdService,
+	}
+}
+
+// GetHousehold returns the patients saved for a WhatsApp number
+func (hc *HouseholdController) GetHousehold(c *gin.Context) {
+	household, err := hc.householdService.Get(c.Request.Context(), c.Param("phone"))
+	if err != nil {
+		if errors.Is(err, services.ErrHouseholdNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Household not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch household", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, household)
+}
+
+// RemoveMember unlinks a patient from a WhatsApp number, e.g. one saved by
+// mistake. The patient stays in HMS.
+func (hc *HouseholdController) RemoveMember(c *gin.Context) {
+	patientID, err := strconv.Atoi(c.Param("patientId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patient ID", "details": err.Error()})
+		return
+	}
+
+	if err := hc.householdService.RemoveMember(c.Request.Context(), c.Param("phone"), patientID); err != nil {
+		if errors.Is(err, services.ErrMemberNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Household member not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove household member", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Household member removed"})
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InboxController struct {
+	inboxService *services.InboxService
+}
+
+func NewInboxController(inboxService *services.InboxService) *InboxController {
+	return &InboxController{
+		inboxService: inboxService,
+	}
+}
+
+// ListConversations lists conversations for reception staff
+func (ic *InboxController) ListConversations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	unread, _ := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+
+	filter := models.InboxFilter{
+		Status:        models.ConversationStatus(c.DefaultQuery("status", string(models.ConversationOpen))),
+		Channel:       models.MessageChannel(c.Query("channel")),
+		Intent:        models.MessageIntent(c.Query("intent")),
+		AssignedAgent: c.Query("assigned"),
+		Tag:           c.Query("tag"),
+		UnreadOnly:    unread,
+		Page:          page,
+		Limit:         limit,
+	}
+	if filter.Status == "all" {
+		filter.Status = ""
+	}
+
+	conversations, total, err := ic.inboxService.ListConversations(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve conversations",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"conversations": conversations,
+		"total":         total,
+		"page":          page,
+		"limit":         limit,
+	})
+}
+
+// GetConversation returns a single conversation
+func (ic *InboxController) GetConversation(c *gin.Context) {
+	conversation, err := ic.inboxService.GetConversation(c.Request.Context(), c.Param("id"))
+	ic.respond(c, conversation, err)
+}
+
+// AssignConversation assigns a conversation to an agent
+func (ic *InboxController) AssignConversation(c *gin.Context) {
+	var req models.AssignConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	conversation, err := ic.inboxService.Assign(c.Request.Context(), c.Param("id"), req.Agent)
+	ic.respond(c, conversation, err)
+}
+
+// AddNote adds an internal note to a conversation
+func (ic *InboxController) AddNote(c *gin.Context) {
+	var req models.AddNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	conversation, err := ic.inboxService.AddNote(c.Request.Context(), c.Param("id"), req.Author, req.Text)
+	ic.respond(c, conversation, err)
+}
+
+// AddTags tags a conversation
+func (ic *InboxController) AddTags(c *gin.Context) {
+	var req models.AddTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Tags) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "tags are required"})
+		return
+	}
+
+	conversation, err := ic.inboxService.AddTags(c.Request.Context(), c.Param("id"), req.Tags)
+	ic.respond(c, conversation, err)
+}
+
+// RemoveTag removes a tag from a conversation
+func (ic *InboxController) RemoveTag(c *gin.Context) {
+	conversation, err := ic.inboxService.RemoveTag(c.Request.Context(), c.Param("id"), c.Param("tag"))
+	ic.respond(c, conversation, err)
+}
+
+// MarkRead resets the unread counter of a conversation
+func (ic *InboxController) MarkRead(c *gin.Context) {
+	conversation, err := ic.inboxService.MarkRead(c.Request.Context(), c.Param("id"))
+	ic.respond(c, conversation, err)
+}
+
+// ResolveConversation marks a conversation resolved
+func (ic *InboxController) ResolveConversation(c *gin.Context) {
+	var req models.ResolveConversationRequest
+	_ = c.ShouldBindJSON(&req)
+
+	conversation, err := ic.inboxService.Resolve(c.Request.Context(), c.Param("id"), req.Agent)
+	ic.respond(c, conversation, err)
+}
+
+// respond writes a conversation or the matching error response
+func (ic *InboxController) respond(c *gin.Context, conversation *models.ConversationSession, err error) {
+	if errors.Is(err, services.ErrConversationNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Conversation not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update conversation",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, conversation)
+}
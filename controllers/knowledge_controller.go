@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Largest FAQ file accepted by UploadDocument
+const maxKnowledgeUpload = 1 << 20
+
+type KnowledgeController struct {
+	knowledgeService *services.KnowledgeService
+}
+
+func NewKnowledgeController(knowledgeService *services.KnowledgeService) *KnowledgeController {
+	return &KnowledgeController{
+		knowledgeService: knowledgeService,
+	}
+}
+
+// CreateDocument ingests a FAQ or policy document sent as JSON
+func (kc *KnowledgeController) CreateDocument(c *gin.Context) {
+	var req models.CreateKnowledgeDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	kc.ingest(c, req.Title, req.Source, req.Content)
+}
+
+// UploadDocument ingests a plain-text or Markdown file. Form fields: file,
+// title (optional, defaults to the file name).
+func (kc *KnowledgeController) UploadDocument(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "file is required"})
+		return
+	}
+
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".txt", ".md":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "only .txt and .md files are supported"})
+		return
+	}
+	if file.Size > maxKnowledgeUpload {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "file is larger than 1 MB"})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file", "details": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file", "details": err.Error()})
+		return
+	}
+	if !utf8.Valid(content) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "file must be UTF-8 text"})
+		return
+	}
+
+	title := c.PostForm("title")
+	if title == "" {
+		title = strings.TrimSuffix(file.Filename, filepath.Ext(file.Filename))
+	}
+	kc.ingest(c, title, file.Filename, string(content))
+}
+
+func (kc *KnowledgeController) ingest(c *gin.Context, title, source, content string) {
+	if strings.TrimSpace(content) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "document has no text"})
+		return
+	}
+
+	doc, err := kc.knowledgeService.Ingest(c.Request.Context(), title, source, content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to ingest document",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, doc)
+}
+
+// ListDocuments returns ingested documents without their content
+func (kc *KnowledgeController) ListDocuments(c *gin.Context) {
+	docs, err := kc.knowledgeService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list documents",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"documents": docs})
+}
+
+// GetDocument returns a document with its content
+func (kc *KnowledgeController) GetDocument(c *gin.Context) {
+	doc, err := kc.knowledgeService.Get(c.Request.Context(), c.Param("id"))
+	kc.respond(c, doc, err)
+}
+
+// DeleteDocument removes a document and its passages
+func (kc *KnowledgeController) DeleteDocument(c *gin.Context) {
+	err := kc.knowledgeService.Delete(c.Request.Context(), c.Param("id"))
+	kc.respond(c, gin.H{"deleted": true}, err)
+}
+
+// Reindex embeds passages stored before embeddings were available
+func (kc *KnowledgeController) Reindex(c *gin.Context) {
+	count, err := kc.knowledgeService.Reindex(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Failed to embed documents",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"embedded": count})
+}
+
+// Search shows which passages the chatbot would use for a question. Query: q.
+func (kc *KnowledgeController) Search(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "q is required"})
+		return
+	}
+
+	passages, err := kc.knowledgeService.Search(c.Request.Context(), query, 5)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to search documents",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"passages": passages})
+}
+
+// respond writes the result or the matching error response
+func (kc *KnowledgeController) respond(c *gin.Context, obj interface{}, err error) {
+	if errors.Is(err, services.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Knowledge document request failed",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, obj)
+}
@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"clinic-chatbot-backend/i18n"
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+	"clinic-chatbot-backend/utils"
+)
+
+// Reply IDs of patient registration: existing records that may be the new
+// patient "record_<patient id>", the row to register anyway, and the retry
+// buttons shown when HMS rejects a registration
+const (
+	existingRecordPrefix     = "record_"
+	newRecordRow             = "record_new"
+	registrationRetryButton  = "reg_retry"
+	registrationCancelButton = "reg_cancel"
+)
+
+// Registration attempts before booking goes ahead without a patient record
+const maxRegistrationAttempts = 3
+
+// Inbox tag for conversations that registered a patient HMS may already have
+const duplicatePatientTag = "possible-duplicate"
+
+// registerNewPatient registers the patient of a booking in HMS once their
+// details are in. Records with a similar name and the same date of birth on
+// the sender's own number are offered first, so a patient who forgot they
+// were registered is not registered twice. Matches on other numbers are
+// never shown: a name and date of birth must not be enough to take over
+// someone's record. The patient is registered anew and reception is asked
+// to check for a duplicate.
+func (wc *WhatsAppController) registerNewPatient(ctx context.Context, userID string, state *AppointmentData) {
+	if state.PhoneNumber == "" {
+		state.PhoneNumber = wc.ownNumber(userID)
+	}
+
+	state.ExistingRecords = nil
+	var elsewhere []Patient
+	for _, patient := range wc.findExistingRecords(state) {
+		if wc.isOwnNumber(userID, patient.MobileNumber) {
+			state.ExistingRecords = append(state.ExistingRecords, patient)
+		} else {
+			elsewhere = append(elsewhere, patient)
+		}
+	}
+	if len(state.ExistingRecords) == 0 {
+		if len(elsewhere) > 0 {
+			wc.flagPossibleDuplicate(ctx, userID, state, elsewhere)
+		}
+		wc.submitRegistration(ctx, userID, state)
+		return
+	}
+	state.Step = "choose_existing_record"
+	_ = wc.sendExistingRecords(userID, state)
+}
+
+// findExistingRecords searches HMS by the patient's name and phone for
+// records with a matching name and date of birth
+func (wc *WhatsAppController) findExistingRecords(state *AppointmentData) []Patient {
+	seen := make(map[int]bool)
+	var found []Patient
+	for _, query := range []string{state.PatientName, state.PhoneNumber} {
+		if strings.TrimSpace(query) == "" {
+			continue
+		}
+		patients, err := wc.verifyPatientCode(query)
+		if err != nil {
+			log.Println("duplicate patient search error:", err)
+			continue
+		}
+		for _, patient := range patients {
+			if seen[patient.ID] || !sameDate(patient.DateOfBirth, state.DateOfBirth) ||
+				!utils.SameName(patient.FirstName+" "+patient.LastName, state.PatientName) {
+				continue
+			}
+			seen[patient.ID] = true
+			found = append(found, patient)
+		}
+	}
+	if len(found) > 9 {
+		found = found[:9]
+	}
+	return found
+}
+
+// isOwnNumber reports whether an HMS mobile number is the WhatsApp number
+// the patient is writing from
+func (wc *WhatsAppController) isOwnNumber(userID, mobile string) bool {
+	if strings.TrimSpace(mobile) == "" {
+		return false
+	}
+	number, err := wc.whatsappService.ParsePhone(mobile)
+	if err != nil {
+		return false
+	}
+	own, err := wc.whatsappService.ParsePhone("+" + userID)
+	if err != nil {
+		return false
+	}
+	return number.E164() == own.E164()
+}
+
+// flagPossibleDuplicate tags the conversation for reception with the codes
+// of records that may already be the patient being registered
+func (wc *WhatsAppController) flagPossibleDuplicate(ctx context.Context, userID string, state *AppointmentData, records []Patient) {
+	codes := make([]string, 0, len(records))
+	for _, patient := range records {
+		codes = append(codes, patient.PatientCode)
+	}
+
+	sessionID := services.WhatsAppSessionID(userID)
+	if err := wc.inboxService.TagSession(ctx, sessionID, duplicatePatientTag); err != nil {
+		log.Println("duplicate patient tagging error:", err)
+	}
+	note := fmt.Sprintf("Registering %s (born %s) as a new patient. HMS has records with a similar name and the same date of birth on other numbers: %s",
+		state.PatientName, state.DateOfBirth, strings.Join(codes, ", "))
+	if err := wc.inboxService.NoteSession(ctx, sessionID, "chatbot", note); err != nil {
+		log.Println("duplicate patient note error:", err)
+	}
+}
+
+// sameDate compares YYYY-MM-DD dates, ignoring any time HMS adds
+func sameDate(a, b string) bool {
+	if len(a) < 10 || len(b) < 10 {
+		return false
+	}
+	return a[:10] == b[:10]
+}
+
+// sendExistingRecords asks whether one of the matching records is the patient
+func (wc *WhatsAppController) sendExistingRecords(userID string, state *AppointmentData) error {
+	locale := wc.localeFor(userID)
+	rows := make([]models.ListItem, 0, len(state.ExistingRecords)+1)
+	for _, patient := range state.ExistingRecords {
+		rows = append(rows, models.ListItem{
+			ID:          existingRecordPrefix + strconv.Itoa(patient.ID),
+			Title:       truncate(patient.FirstName+" "+patient.LastName, 24),
+			Description: truncate(i18n.T(locale, "booking.patient_code", patient.PatientCode)+" · "+patient.DateOfBirth[:10], 72),
+		})
+	}
+	rows = append(rows, models.ListItem{
+		ID:          newRecordRow,
+		Title:       i18n.T(locale, "registration.none"),
+		Description: i18n.T(locale, "registration.none_desc"),
+	})
+
+	interactive := &models.InteractiveMessage{
+		Type: "list",
+		Body: &models.InteractiveBody{
+			Text: i18n.T(locale, "registration.existing_body", state.PatientName),
+		},
+		Action: &models.InteractiveAction{
+			Button:   i18n.T(locale, "registration.existing_button"),
+			Sections: []models.Section{{Title: i18n.T(locale, "registration.existing_section"), Rows: rows}},
+		},
+	}
+	return wc.whatsappService.SendInteractiveMessage(userID, interactive)
+}
+
+// chooseExistingRecord handles the answer to sendExistingRecords
+func (wc *WhatsAppController) chooseExistingRecord(ctx context.Context, userID string, state *AppointmentData, message models.WhatsAppMessage) {
+	if message.Interactive == nil || message.Interactive.ListReply == nil {
+		_ = wc.sendExistingRecords(userID, state)
+		return
+	}
+
+	id := message.Interactive.ListReply.ID
+	if id == newRecordRow {
+		wc.submitRegistration(ctx, userID, state)
+		return
+	}
+	patientID, _ := strconv.Atoi(strings.TrimPrefix(id, existingRecordPrefix))
+	for _, patient := range state.ExistingRecords {
+		if patient.ID == patientID {
+			wc.finishRegistration(userID, state, patient, "registration.found")
+			return
+		}
+	}
+	_ = wc.sendExistingRecords(userID, state)
+}
+
+// submitRegistration creates the patient in HMS. A failed attempt offers a
+// retry; after the last one the booking goes ahead with the details given,
+// as it did before patients were registered, and the front desk completes
+// the record.
+func (wc *WhatsAppController) submitRegistration(ctx context.Context, userID string, state *AppointmentData) {
+	locale := wc.localeFor(userID)
+	first, last, _ := strings.Cut(strings.TrimSpace(state.PatientName), " ")
+
+	patient, err := wc.registerPatient(ctx, newPatientRequest{
+		FirstName:    first,
+		LastName:     strings.TrimSpace(last),
+		DateOfBirth:  state.DateOfBirth,
+		MobileNumber: state.PhoneNumber,
+		Address:      state.Address,
+	})
+	if err == nil {
+		wc.finishRegistration(userID, state, patient, "registration.done")
+		return
+	}
+
+	log.Println("patient registration error:", err)
+	state.RegistrationAttempts++
+	if state.RegistrationAttempts < maxRegistrationAttempts {
+		state.Step = "retry_registration"
+		_ = wc.sendRegistrationRetry(userID)
+		return
+	}
+
+	if state.RegisterOnly {
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "registration.failed_later"))
+		delete(appointmentState, userID)
+		_ = wc.sendMainMenu(userID)
+		return
+	}
+	_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "registration.failed"))
+	wc.continueBooking(userID, state)
+}
+
+// sendRegistrationRetry offers another attempt. The cause is only logged:
+// HMS errors carry internal addresses patients must not see.
+func (wc *WhatsAppController) sendRegistrationRetry(userID string) error {
+	locale := wc.localeFor(userID)
+	interactive := &models.InteractiveMessage{
+		Type: "button",
+		Body: &models.InteractiveBody{
+			Text: i18n.T(locale, "registration.retry"),
+		},
+		Action: &models.InteractiveAction{
+			Buttons: []models.InteractiveButton{
+				{Type: "reply", Reply: &models.ButtonReply{ID: registrationRetryButton, Title: i18n.T(locale, "registration.retry_button")}},
+				{Type: "reply", Reply: &models.ButtonReply{ID: registrationCancelButton, Title: i18n.T(locale, "registration.cancel_button")}},
+			},
+		},
+	}
+	return wc.whatsappService.SendInteractiveMessage(userID, interactive)
+}
+
+// answerRegistrationRetry handles the answer to sendRegistrationRetry; a
+// text yes or no works too
+func (wc *WhatsAppController) answerRegistrationRetry(ctx context.Context, userID string, state *AppointmentData, message models.WhatsAppMessage) {
+	answer := ""
+	if message.Interactive != nil && message.Interactive.ButtonReply != nil {
+		answer = message.Interactive.ButtonReply.ID
+	} else if message.Type == "text" && message.Text != nil {
+		switch {
+		case i18n.IsYes(message.Text.Body):
+			answer = registrationRetryButton
+		case i18n.IsNo(message.Text.Body):
+			answer = registrationCancelButton
+		}
+	}
+
+	switch answer {
+	case registrationRetryButton:
+		wc.submitRegistration(ctx, userID, state)
+	case registrationCancelButton:
+		delete(appointmentState, userID)
+		_ = wc.sendMainMenu(userID)
+	default:
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(wc.localeFor(userID), "booking.reply_yes_no"))
+	}
+}
+
+// finishRegistration books for the registered or found patient, saving them
+// to the number's household when registered on it, and echoes their patient code
+func (wc *WhatsAppController) finishRegistration(userID string, state *AppointmentData, patient Patient, messageKey string) {
+	wc.rememberPatient(userID, patient, state.ForRelation)
+	state.PatientID = patient.ID
+	state.PatientCode = patient.PatientCode
+	state.PatientName = strings.TrimSpace(fmt.Sprintf("%s %s", patient.FirstName, patient.LastName))
+	if patient.MobileNumber != "" {
+		state.PhoneNumber = patient.MobileNumber
+	}
+	if patient.Address != "" {
+		state.Address = patient.Address
+	}
+	state.DateOfBirth = patient.DateOfBirth
+	state.ExistingRecords = nil
+	_ = wc.whatsappService.SendTextMessage(userID,
+		i18n.T(wc.localeFor(userID), messageKey, state.PatientName, patient.PatientCode))
+
+	if state.RegisterOnly {
+		delete(appointmentState, userID)
+		_ = wc.sendMainMenu(userID)
+		return
+	}
+	wc.continueBooking(userID, state)
+}
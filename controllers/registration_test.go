@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRegistrationCompletesAfterRetry(t *testing.T) {
+	const user = "919800000002"
+
+	backend := newFakeBackend()
+	backend.handle("/api/patient/search", func([]byte) string {
+		return `{"status":true,"data":[]}`
+	})
+	var attempts atomic.Int32
+	requests := make(chan newPatientRequest, 2)
+	backend.handle("/api/patient/create", func(body []byte) string {
+		var req newPatientRequest
+		_ = json.Unmarshal(body, &req)
+		requests <- req
+		if attempts.Add(1) == 1 {
+			return `{"status":false,"message":"HMS busy at 10.0.0.5"}`
+		}
+		return `{"status":true,"data":{"patientId":9,"patientCode":"PC-9"}}`
+	})
+	wc := newTestController(t, backend)
+
+	state := &AppointmentData{
+		PatientName: "Ravi Kumar",
+		Address:     "4 Temple Street",
+		PhoneNumber: "9800000002",
+		Step:        "await_patient_dateOfBirth",
+	}
+	appointmentState[user] = state
+
+	postText(t, wc, user, "1985-11-30")
+	retry := backend.waitForSend(t, "try again")
+	if strings.Contains(retry, "10.0.0.5") {
+		t.Errorf("retry prompt shows the HMS error: %s", retry)
+	}
+
+	postText(t, wc, user, "yes")
+	backend.waitForSend(t, "PC-9")
+
+	if state.PatientID != 9 || state.PatientCode != "PC-9" {
+		t.Errorf("booking has patient %d %q, want 9 \"PC-9\"", state.PatientID, state.PatientCode)
+	}
+	for i := 0; i < 2; i++ {
+		req := <-requests
+		if req.FirstName != "Ravi" || req.LastName != "Kumar" || req.DateOfBirth != "1985-11-30" || req.MobileNumber != "9800000002" {
+			t.Errorf("attempt %d sent %+v", i+1, req)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"clinic-chatbot-backend/i18n"
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppointmentReminder is called by HMS to remind a patient of an appointment.
+// Reminders are critical messages, so they fall back to SMS when WhatsApp
+// cannot deliver them. Patients who replied STOP are not reminded.
+func (wc *WhatsAppController) AppointmentReminder(c *gin.Context) {
+	var req models.AppointmentReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be a YYYY-MM-DD date", "details": err.Error()})
+		return
+	}
+
+	// HMS stores numbers as they were typed at the desk
+	to := wc.whatsappService.NormalizePhone(req.MobileNumber)
+	ctx := c.Request.Context()
+
+	err = wc.contactService.CheckConsent(ctx, to)
+	switch {
+	case errors.Is(err, services.ErrContactOptedOut):
+		c.JSON(http.StatusOK, gin.H{"message": "Contact opted out; reminder not sent", "sent": false})
+		return
+	case err != nil && !errors.Is(err, services.ErrNoOptIn):
+		// Reminders are about the patient's own booking and need no opt-in,
+		// but an opt-out that can't be read must not be ignored
+		log.Println("reminder consent lookup error:", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Consent lookup failed"})
+		return
+	}
+
+	locale := wc.localeFor(to)
+	text := i18n.T(locale, "booking.reminder", req.DoctorName, date.Format("Mon 02 Jan"), req.Time)
+	if err := wc.fallbackService.SendCritical(ctx, models.CriticalReminder, to, text); err != nil {
+		log.Println("appointment reminder error:", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Reminder could not be sent"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Reminder sent", "sent": true})
+}
@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAppointmentReminderNotSentWithoutConsentLookup(t *testing.T) {
+	backend := newFakeBackend()
+	wc := newTestController(t, backend)
+
+	router := gin.New()
+	router.POST("/reminder", wc.AppointmentReminder)
+	body := `{"mobileNumber":"98000 00003","doctorName":"Dr. Mehta","date":"2026-10-20","time":"10:30 AM"}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/reminder", strings.NewReader(body)))
+
+	// Mongo is unreachable, so whether the patient opted out is unknown
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("reminder answered %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	select {
+	case sent := <-backend.sent:
+		t.Errorf("reminder sent without a consent check: %s", sent)
+	default:
+	}
+}
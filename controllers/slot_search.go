@@ -0,0 +1,428 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"clinic-chatbot-backend/config"
+	"clinic-chatbot-backend/i18n"
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Most slots offered by one earliest-available search; a WhatsApp list holds 10 rows
+const earliestSlotLimit = 10
+
+// Furthest ahead, in days, an earliest-available search may start
+const searchStartLimit = 30
+
+// SlotOption is an open appointment slot of an HMS doctor
+type SlotOption struct {
+	DoctorID       int    `json:"doctor_id"`
+	DoctorName     string `json:"doctor_name,omitempty"`
+	Date           string `json:"date"`                      // YYYY-MM-DD
+	Time           string `json:"time"`                      // "03:04 PM"
+	Token          uint   `json:"token"`                     // slot number in the doctor's day, counted here from the sessions
+	AvailabilityID int    `json:"availability_id,omitempty"` // HMS session; with Date and Time it identifies the slot in HMS
+}
+
+// ID identifies the slot in WhatsApp list rows, e.g. "slot:12:2026-10-20:1530:23"
+func (o SlotOption) ID() string {
+	return fmt.Sprintf("slot:%d:%s:%s:%d", o.DoctorID, o.Date, o.startsAt().Format("1504"), o.Token)
+}
+
+// findSlot resolves a list row ID against the slots that were offered
+func findSlot(slots []SlotOption, id string) (SlotOption, bool) {
+	for _, slot := range slots {
+		if slot.ID() == id {
+			return slot, true
+		}
+	}
+	return SlotOption{}, false
+}
+
+// slotSearch looks for the earliest open slots across the doctors of a
+// department. HMS is queried in parallel, at most concurrency requests at a
+// time across all searches, and answers are reused for cacheTTL. Expired
+// answers are dropped as new ones are stored.
+type slotSearch struct {
+	days     int
+	cacheTTL time.Duration
+	sem      chan struct{}
+
+	mu      sync.Mutex
+	doctors map[string]cachedDoctors
+	slots   map[string]cachedSlots
+	swept   time.Time // when expired answers were last dropped
+}
+
+type cachedDoctors struct {
+	doctors []Doctor
+	expires time.Time
+}
+
+type cachedSlots struct {
+	slots   []SlotOption
+	expires time.Time
+}
+
+func newSlotSearch(cfg config.BookingConfig) *slotSearch {
+	if cfg.SearchDays <= 0 {
+		cfg.SearchDays = 7
+	}
+	if cfg.SearchConcurrency <= 0 {
+		cfg.SearchConcurrency = 4
+	}
+	return &slotSearch{
+		days:     cfg.SearchDays,
+		cacheTTL: cfg.SearchCacheTTL,
+		sem:      make(chan struct{}, cfg.SearchConcurrency),
+		doctors:  make(map[string]cachedDoctors),
+		slots:    make(map[string]cachedSlots),
+	}
+}
+
+// acquire waits for a free HMS request slot; false when ctx ends first
+func (s *slotSearch) acquire(ctx context.Context) bool {
+	select {
+	case s.sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *slotSearch) release() {
+	<-s.sem
+}
+
+// sweep drops expired answers, at most once per cacheTTL. Callers hold mu.
+func (s *slotSearch) sweep(now time.Time) {
+	if now.Sub(s.swept) < s.cacheTTL {
+		return
+	}
+	s.swept = now
+	for key, cached := range s.doctors {
+		if !now.Before(cached.expires) {
+			delete(s.doctors, key)
+		}
+	}
+	for key, cached := range s.slots {
+		if !now.Before(cached.expires) {
+			delete(s.slots, key)
+		}
+	}
+}
+
+// searchStart is the day a search asked to start on, no earlier than now and
+// no later than searchStartLimit days ahead
+func searchStart(now, requested time.Time) time.Time {
+	if requested.Before(now) {
+		return now
+	}
+	if limit := now.AddDate(0, 0, searchStartLimit); requested.After(limit) {
+		return limit
+	}
+	return requested
+}
+
+// forget drops the cached free slots of a doctor, e.g. after a booking
+func (s *slotSearch) forget(doctorID uint, date string) {
+	s.mu.Lock()
+	delete(s.slots, fmt.Sprintf("%d:%s", doctorID, date))
+	s.mu.Unlock()
+}
+
+// searchDoctors returns the doctors of a department working on a date
+func (wc *WhatsAppController) searchDoctors(ctx context.Context, dept uint, date string) ([]Doctor, error) {
+	s := wc.slotSearch
+	key := fmt.Sprintf("%d:%s", dept, date)
+
+	s.mu.Lock()
+	cached, ok := s.doctors[key]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.doctors, nil
+	}
+
+	if !s.acquire(ctx) {
+		return nil, ctx.Err()
+	}
+	doctors, err := wc.fetchDoctors(dept, date)
+	s.release()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	s.sweep(now)
+	s.doctors[key] = cachedDoctors{doctors: doctors, expires: now.Add(s.cacheTTL)}
+	s.mu.Unlock()
+	return doctors, nil
+}
+
+// searchFreeSlots returns the free slots of a doctor on a date
+func (wc *WhatsAppController) searchFreeSlots(ctx context.Context, doctor uint, date string) ([]SlotOption, error) {
+	s := wc.slotSearch
+	key := fmt.Sprintf("%d:%s", doctor, date)
+
+	s.mu.Lock()
+	cached, ok := s.slots[key]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.slots, nil
+	}
+
+	if !s.acquire(ctx) {
+		return nil, ctx.Err()
+	}
+	slots, err := wc.fetchFreeSlots(ctx, doctor, date)
+	s.release()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	s.sweep(now)
+	s.slots[key] = cachedSlots{slots: slots, expires: now.Add(s.cacheTTL)}
+	s.mu.Unlock()
+	return slots, nil
+}
+
+// earliestSlots returns the earliest open slots in a department over the
+// next search days from a date, with one doctor only when doctorID is set.
+// Slots in the time window ("evening") are preferred when there are any.
+func (wc *WhatsAppController) earliestSlots(ctx context.Context, holderID string, dept uint, doctorID int, from time.Time, window string) []SlotOption {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		found []SlotOption
+	)
+
+	for day := 0; day < wc.slotSearch.days; day++ {
+		date := from.AddDate(0, 0, day).Format("2006-01-02")
+		if _, closed := wc.chatbotService.ClinicHoliday(ctx, date); closed {
+			continue
+		}
+
+		wg.Add(1)
+		go func(date string) {
+			defer wg.Done()
+			doctors, err := wc.searchDoctors(ctx, dept, date)
+			if err != nil {
+				log.Println("slot search: doctors on", date, err)
+				return
+			}
+
+			for _, doctor := range doctors {
+				if doctorID != 0 && doctor.ID != doctorID {
+					continue
+				}
+				wg.Add(1)
+				go func(doctor Doctor) {
+					defer wg.Done()
+					slots, err := wc.searchFreeSlots(ctx, uint(doctor.ID), date)
+					if err != nil {
+						log.Println("slot search: slots of", doctor.ID, "on", date, err)
+						return
+					}
+
+					mu.Lock()
+					for _, slot := range slots {
+						slot.DoctorName = doctor.DoctorName
+						found = append(found, slot)
+					}
+					mu.Unlock()
+				}(doctor)
+			}
+		}(date)
+	}
+	wg.Wait()
+
+	// Leave out slots other patients are holding
+	held, err := wc.slotHoldService.HeldByOthers(ctx, holderID,
+		from.Format("2006-01-02"), from.AddDate(0, 0, wc.slotSearch.days-1).Format("2006-01-02"))
+	if err != nil {
+		log.Println("slot search: holds", err)
+	}
+	free := found[:0]
+	for _, option := range found {
+		if !held[option.ref()] {
+			free = append(free, option)
+		}
+	}
+	found = free
+
+	if window != "" {
+		if inWindow := filterSlotsByWindow(found, window); len(inWindow) > 0 {
+			found = inWindow
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].startsAt().Before(found[j].startsAt())
+	})
+	if len(found) > earliestSlotLimit {
+		found = found[:earliestSlotLimit]
+	}
+	return found
+}
+
+func (o SlotOption) ref() models.SlotRef {
+	return models.SlotRef{DoctorID: uint(o.DoctorID), Date: o.Date, Time: o.Time}
+}
+
+func (o SlotOption) startsAt() time.Time {
+	t, _ := time.Parse("2006-01-02 03:04 PM", o.Date+" "+o.Time)
+	return t
+}
+
+// sendEarliestSlots offers the earliest open slots from the booking's date,
+// or from today, and the waitlist when there are none
+func (wc *WhatsAppController) sendEarliestSlots(userID string, state *AppointmentData) {
+	locale := wc.localeFor(userID)
+	_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.searching"))
+
+	from := time.Now().In(services.ClinicLocation())
+	if date, err := time.ParseInLocation("2006-01-02", state.AppointmentDate, services.ClinicLocation()); err == nil {
+		from = searchStart(from, date)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	options := wc.earliestSlots(ctx, services.WhatsAppSessionID(userID), state.DepartmentID, state.PreferredDoctorID, from, state.TimeWindow)
+
+	// Hold what is offered; slots someone else got first are left out
+	held := wc.holdSlots(userID, options)
+	kept := options[:0]
+	for _, option := range options {
+		if held[option.ref()] {
+			kept = append(kept, option)
+		}
+	}
+	options = kept
+
+	if len(options) == 0 {
+		wc.offerWaitlist(userID, state)
+		return
+	}
+
+	state.EarliestSlots = options
+	state.Step = "choose_earliest_slot"
+
+	rows := make([]models.ListItem, 0, len(options))
+	for _, option := range options {
+		rows = append(rows, models.ListItem{
+			ID:          option.ID(),
+			Title:       option.startsAt().Format("Mon 02 Jan 03:04 PM"),
+			Description: truncate(option.DoctorName, 72),
+		})
+	}
+
+	interactive := &models.InteractiveMessage{
+		Type: "list",
+		Header: &models.MessageHeader{
+			Type: "text",
+			Text: i18n.T(locale, "booking.slots_header"),
+		},
+		Body: &models.InteractiveBody{
+			Text: i18n.T(locale, "booking.earliest_body", wc.slotSearch.days),
+		},
+		Action: &models.InteractiveAction{
+			Button:   i18n.T(locale, "booking.slots_button"),
+			Sections: []models.Section{{Rows: rows}},
+		},
+	}
+	_ = wc.whatsappService.SendInteractiveMessage(userID, interactive)
+}
+
+// EarliestSlots returns the earliest open slots in a department for web
+// clients. Query: department_id, optional doctor_id, from (YYYY-MM-DD, at
+// most searchStartLimit days ahead) and window (morning, afternoon, evening).
+func (wc *WhatsAppController) EarliestSlots(c *gin.Context) {
+	dept, err := strconv.Atoi(c.Query("department_id"))
+	if err != nil || dept <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "department_id is required"})
+		return
+	}
+	doctorID, _ := strconv.Atoi(c.Query("doctor_id"))
+
+	from := time.Now().In(services.ClinicLocation())
+	if date := c.Query("from"); date != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", date, services.ClinicLocation())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a YYYY-MM-DD date", "details": err.Error()})
+			return
+		}
+		from = searchStart(from, parsed)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	c.JSON(http.StatusOK, gin.H{
+		"slots": wc.earliestSlots(ctx, "", uint(dept), doctorID, from, c.Query("window")),
+		"days":  wc.slotSearch.days,
+	})
+}
+
+// withoutHeldSlots drops slots on a date that other patients are holding
+func (wc *WhatsAppController) withoutHeldSlots(ctx context.Context, userID, date string, slots []SlotOption) []SlotOption {
+	held, err := wc.slotHoldService.HeldByOthers(ctx, services.WhatsAppSessionID(userID), date, date)
+	if err != nil {
+		log.Println("slot hold error:", err)
+		return slots
+	}
+
+	free := []SlotOption{}
+	for _, slot := range slots {
+		if !held[slot.ref()] {
+			free = append(free, slot)
+		}
+	}
+	return free
+}
+
+// holdSlots holds slots for a WhatsApp user, replacing their earlier holds,
+// and returns the slots they got. When holds cannot be stored every slot is
+// returned, as the booking itself still checks with HMS.
+func (wc *WhatsAppController) holdSlots(userID string, slots []SlotOption) map[models.SlotRef]bool {
+	refs := make([]models.SlotRef, len(slots))
+	for i, slot := range slots {
+		refs[i] = slot.ref()
+	}
+
+	got := make(map[models.SlotRef]bool, len(slots))
+	held, err := wc.slotHoldService.Hold(context.Background(), services.WhatsAppSessionID(userID), refs)
+	if err != nil {
+		log.Println("slot hold error:", err)
+		if len(held) == 0 {
+			held = refs
+		}
+	}
+	for _, ref := range held {
+		got[ref] = true
+	}
+	return got
+}
+
+// holdSlot holds the slot a user picked; false when someone else holds it
+func (wc *WhatsAppController) holdSlot(userID string, slot SlotOption) bool {
+	return wc.holdSlots(userID, []SlotOption{slot})[slot.ref()]
+}
+
+// releaseSlots lets other patients see the slots a user was holding
+func (wc *WhatsAppController) releaseSlots(userID string) {
+	if err := wc.slotHoldService.Release(context.Background(), services.WhatsAppSessionID(userID)); err != nil {
+		log.Println("slot hold error:", err)
+	}
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearchStart(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		requested time.Time
+		want      time.Time
+	}{
+		{"past", now.AddDate(0, 0, -3), now},
+		{"next week", now.AddDate(0, 0, 7), now.AddDate(0, 0, 7)},
+		{"next year", now.AddDate(1, 0, 0), now.AddDate(0, 0, searchStartLimit)},
+	}
+	for _, tt := range tests {
+		if got := searchStart(now, tt.requested); !got.Equal(tt.want) {
+			t.Errorf("%s: searchStart() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSlotSearchSweepsExpiredAnswers(t *testing.T) {
+	s := &slotSearch{
+		cacheTTL: time.Minute,
+		doctors:  make(map[string]cachedDoctors),
+		slots:    make(map[string]cachedSlots),
+	}
+	now := time.Now()
+	s.doctors["1:2026-10-19"] = cachedDoctors{expires: now.Add(-time.Second)}
+	s.slots["7:2026-10-19"] = cachedSlots{expires: now.Add(-time.Second)}
+	s.slots["7:2026-10-20"] = cachedSlots{expires: now.Add(time.Second)}
+
+	s.sweep(now)
+	if len(s.doctors) != 0 || len(s.slots) != 1 {
+		t.Errorf("after sweep: %d doctor lists and %d slot lists cached, want 0 and 1", len(s.doctors), len(s.slots))
+	}
+}
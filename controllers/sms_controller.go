@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"encoding/xml"
+	"log"
+	"net/http"
+	"strings"
+
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/notify/sms"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SMSController struct {
+	chatbotService *services.ChatbotService
+	smsSender      *sms.TwilioSender
+	webhookURL     string
+}
+
+// NewSMSController takes a nil smsSender when SMS is not configured
+func NewSMSController(chatbotService *services.ChatbotService, smsSender *sms.TwilioSender, webhookURL string) *SMSController {
+	if smsSender != nil && webhookURL == "" {
+		log.Println("WARNING: TWILIO_WEBHOOK_URL is not set; inbound SMS will be rejected")
+	}
+	return &SMSController{
+		chatbotService: chatbotService,
+		smsSender:      smsSender,
+		webhookURL:     webhookURL,
+	}
+}
+
+// twimlResponse is the TwiML body Twilio sends back to the patient
+type twimlResponse struct {
+	XMLName xml.Name `xml:"Response"`
+	Message string   `xml:"Message,omitempty"`
+}
+
+// HandleWebhook receives inbound SMS from Twilio and answers with the
+// chatbot's reply
+func (sc *SMSController) HandleWebhook(c *gin.Context) {
+	if sc.smsSender == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "SMS is not configured"})
+		return
+	}
+	if err := c.Request.ParseForm(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	// Twilio signs the public URL, which only the configuration knows behind
+	// a proxy. Without it no request can be verified, so none is accepted:
+	// an unverified SMS could impersonate any number and raise emergencies.
+	if sc.webhookURL == "" {
+		log.Println("TWILIO_WEBHOOK_URL not set, rejecting inbound SMS")
+		c.JSON(http.StatusForbidden, gin.H{"error": "SMS webhook signature cannot be verified"})
+		return
+	}
+	if !sc.smsSender.ValidateSignature(sc.webhookURL, c.Request.PostForm, c.GetHeader("X-Twilio-Signature")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid signature"})
+		return
+	}
+
+	from := strings.TrimPrefix(c.PostForm("From"), "+")
+	body := strings.TrimSpace(c.PostForm("Body"))
+	if from == "" || body == "" {
+		c.XML(http.StatusOK, twimlResponse{})
+		return
+	}
+
+	response, err := sc.chatbotService.ProcessMessage(c.Request.Context(), models.ChatRequest{
+		Message:   body,
+		SessionID: services.SMSSessionID(from),
+		UserID:    from,
+		Channel:   models.ChannelSMS,
+	})
+	if err != nil {
+		log.Println("SMS chatbot error", err)
+		c.XML(http.StatusOK, twimlResponse{})
+		return
+	}
+
+	c.XML(http.StatusOK, twimlResponse{Message: response.Response})
+}
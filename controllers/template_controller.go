@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateController struct {
+	templateService *services.TemplateService
+}
+
+func NewTemplateController(templateService *services.TemplateService) *TemplateController {
+	return &TemplateController{
+		templateService: templateService,
+	}
+}
+
+// ListTemplates returns the synced message templates
+func (tc *TemplateController) ListTemplates(c *gin.Context) {
+	templates, err := tc.templateService.List(c.Request.Context(), c.Query("status"), c.Query("language"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve templates",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+		"count":     len(templates),
+	})
+}
+
+// CreateTemplate submits a new template to the business account
+func (tc *TemplateController) CreateTemplate(c *gin.Context) {
+	var req models.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	template, err := tc.templateService.Create(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Failed to create template",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// SyncTemplates refreshes the local template store from the business account
+func (tc *TemplateController) SyncTemplates(c *gin.Context) {
+	count, err := tc.templateService.Sync(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Failed to sync templates",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "synced",
+		"count":  count,
+	})
+}
@@ -0,0 +1,423 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"clinic-chatbot-backend/i18n"
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Reply IDs of the waitlist: the join invitation's buttons, and the offer
+// buttons "waitbook_<entry id>", "waitskip_<entry id>" and "waitleave_<entry id>"
+const (
+	waitlistJoinButton  = "waitlist_join"
+	waitlistNoButton    = "waitlist_no"
+	waitlistBookPrefix  = "waitbook_"
+	waitlistSkipPrefix  = "waitskip_"
+	waitlistLeavePrefix = "waitleave_"
+)
+
+// offerWaitlist asks a patient who found no open slots to join the waitlist
+func (wc *WhatsAppController) offerWaitlist(userID string, state *AppointmentData) {
+	locale := wc.localeFor(userID)
+	state.Step = "offer_waitlist"
+
+	interactive := &models.InteractiveMessage{
+		Type: "button",
+		Body: &models.InteractiveBody{
+			Text: i18n.T(locale, "waitlist.offer_join", wc.slotSearch.days),
+		},
+		Action: &models.InteractiveAction{
+			Buttons: []models.InteractiveButton{
+				{Type: "reply", Reply: &models.ButtonReply{ID: waitlistJoinButton, Title: i18n.T(locale, "waitlist.join_button")}},
+				{Type: "reply", Reply: &models.ButtonReply{ID: waitlistNoButton, Title: i18n.T(locale, "waitlist.no_button")}},
+			},
+		},
+	}
+	_ = wc.whatsappService.SendInteractiveMessage(userID, interactive)
+}
+
+// joinWaitlist puts the patient of a booking on the waitlist for the doctor
+// they asked for, or any doctor in the department, and ends the booking
+func (wc *WhatsAppController) joinWaitlist(ctx context.Context, userID string, state *AppointmentData) {
+	locale := wc.localeFor(userID)
+	defer func() {
+		delete(appointmentState, userID)
+		_ = wc.sendMainMenu(userID)
+	}()
+
+	from := time.Now().In(services.ClinicLocation())
+	if date, err := time.ParseInLocation("2006-01-02", state.AppointmentDate, services.ClinicLocation()); err == nil && date.After(from) {
+		from = date
+	}
+
+	entry := models.WaitlistEntry{
+		Phone:        userID,
+		Locale:       string(locale),
+		DepartmentID: state.DepartmentID,
+		DoctorID:     state.DoctorID,
+		DoctorName:   state.DoctorName,
+		FromDate:     from.Format("2006-01-02"),
+		ToDate:       from.AddDate(0, 0, wc.waitlistDays-1).Format("2006-01-02"),
+		TimeWindow:   state.TimeWindow,
+		PatientID:    state.PatientID,
+		PatientCode:  state.PatientCode,
+		PatientName:  state.PatientName,
+		Address:      state.Address,
+		PhoneNumber:  state.PhoneNumber,
+		DateOfBirth:  state.DateOfBirth,
+	}
+	if state.PreferredDoctorID != 0 {
+		// The search covered only the doctor asked for
+		entry.DoctorID = uint(state.PreferredDoctorID)
+		entry.DoctorName = "Dr. " + state.PreferredDoctor
+	}
+
+	saved, err := wc.waitlistService.Join(ctx, entry)
+	if err != nil {
+		log.Println("waitlist error:", err)
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.failed"))
+		return
+	}
+
+	who := saved.DoctorName
+	if saved.DoctorID == 0 || who == "" {
+		who = i18n.T(locale, "waitlist.any_doctor")
+	}
+	_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "waitlist.joined", who, saved.FromDate, saved.ToDate))
+}
+
+// handleWaitlistReply handles taps on waitlist offers, and a YES or NO reply
+// to a live offer when no booking is in progress, since offers sent as
+// templates outside the service window have no buttons
+func (wc *WhatsAppController) handleWaitlistReply(ctx context.Context, userID string, message models.WhatsAppMessage) bool {
+	if message.Interactive != nil && message.Interactive.ButtonReply != nil {
+		id := message.Interactive.ButtonReply.ID
+		switch {
+		case strings.HasPrefix(id, waitlistBookPrefix):
+			wc.acceptWaitlistOffer(ctx, userID, strings.TrimPrefix(id, waitlistBookPrefix))
+			return true
+		case strings.HasPrefix(id, waitlistSkipPrefix):
+			wc.passWaitlistOffer(ctx, userID, strings.TrimPrefix(id, waitlistSkipPrefix))
+			return true
+		case strings.HasPrefix(id, waitlistLeavePrefix):
+			wc.leaveWaitlist(ctx, userID, strings.TrimPrefix(id, waitlistLeavePrefix))
+			return true
+		}
+		return false
+	}
+
+	if message.Type != "text" || message.Text == nil {
+		return false
+	}
+	yes, no := i18n.IsYes(message.Text.Body), i18n.IsNo(message.Text.Body)
+	if !yes && !no {
+		return false
+	}
+	if _, booking := appointmentState[userID]; booking {
+		return false
+	}
+	entry, err := wc.waitlistService.ActiveOffer(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, services.ErrWaitlistEntryNotFound) {
+			log.Println("waitlist error:", err)
+		}
+		return false
+	}
+	if yes {
+		wc.acceptWaitlistOffer(ctx, userID, entry.ID.Hex())
+	} else {
+		wc.passWaitlistOffer(ctx, userID, entry.ID.Hex())
+	}
+	return true
+}
+
+// acceptWaitlistOffer books the slot offered to a waitlisted patient. The
+// patient goes back on the waitlist when the booking fails.
+func (wc *WhatsAppController) acceptWaitlistOffer(ctx context.Context, userID, id string) {
+	entry, err := wc.waitlistService.Claim(ctx, id, userID)
+	if err != nil {
+		if !errors.Is(err, services.ErrWaitlistEntryNotFound) {
+			log.Println("waitlist error:", err)
+		}
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(wc.localeFor(userID), "waitlist.offer_gone"))
+		return
+	}
+
+	// The booking holds the slot for the patient's session from here on
+	wc.releaseWaitlistHold(ctx, entry.ID.Hex())
+
+	offer := entry.Offer
+	state := &AppointmentData{
+		PatientID:    entry.PatientID,
+		PatientCode:  entry.PatientCode,
+		PatientName:  entry.PatientName,
+		Address:      entry.Address,
+		PhoneNumber:  entry.PhoneNumber,
+		DateOfBirth:  entry.DateOfBirth,
+		DepartmentID: entry.DepartmentID,
+		DoctorName:   offer.DoctorName,
+		CreatedFrom:  userID,
+		Step:         "waitlist_offer",
+	}
+	appointmentState[userID] = state
+
+	slot := SlotOption{
+		DoctorID:       int(offer.DoctorID),
+		DoctorName:     offer.DoctorName,
+		Date:           offer.Date,
+		Time:           offer.Time,
+		Token:          offer.Token,
+		AvailabilityID: offer.AvailabilityID,
+	}
+	if !wc.bookSelectedSlot(userID, state, slot) {
+		if err := wc.waitlistService.Requeue(ctx, entry.ID); err != nil {
+			log.Println("waitlist error:", err)
+		}
+	}
+}
+
+// passWaitlistOffer turns down an offer and hands the slot to the next patient
+func (wc *WhatsAppController) passWaitlistOffer(ctx context.Context, userID, id string) {
+	locale := wc.localeFor(userID)
+	if _, err := wc.waitlistService.Pass(ctx, id, userID); err != nil {
+		if !errors.Is(err, services.ErrWaitlistEntryNotFound) {
+			log.Println("waitlist error:", err)
+		}
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "waitlist.offer_gone"))
+		return
+	}
+	wc.releaseWaitlistHold(ctx, id)
+	_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "waitlist.skipped"))
+	wc.requestWaitlistCheck()
+}
+
+// leaveWaitlist takes the patient off the waitlist, freeing any offered slot
+func (wc *WhatsAppController) leaveWaitlist(ctx context.Context, userID, id string) {
+	entry, err := wc.waitlistService.Leave(ctx, id, userID)
+	if err != nil && !errors.Is(err, services.ErrWaitlistEntryNotFound) {
+		log.Println("waitlist error:", err)
+		return
+	}
+	_ = wc.whatsappService.SendTextMessage(userID, i18n.T(wc.localeFor(userID), "waitlist.left"))
+	_ = wc.sendMainMenu(userID)
+
+	if entry != nil && entry.Offer != nil {
+		wc.releaseWaitlistHold(ctx, id)
+		wc.requestWaitlistCheck()
+	}
+}
+
+// RunWaitlist checks HMS for opened slots for waitlisted patients until ctx
+// ends, on every poll and whenever a check is requested
+func (wc *WhatsAppController) RunWaitlist(ctx context.Context) {
+	ticker := time.NewTicker(wc.waitlistPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			wc.checkWaitlist()
+		case <-wc.waitlistCheck:
+			wc.checkWaitlist()
+		}
+	}
+}
+
+// requestWaitlistCheck asks RunWaitlist for a check without waiting for it.
+// Requests made while one is pending are merged into it, so a burst of
+// cancellations costs one round of HMS queries.
+func (wc *WhatsAppController) requestWaitlistCheck() {
+	select {
+	case wc.waitlistCheck <- struct{}{}:
+	default:
+	}
+}
+
+// checkWaitlist releases lapsed offers, then offers each waiting patient,
+// oldest first, the earliest open slot in their range. Slots offered to one
+// patient are held, so the next patient in line is offered another.
+func (wc *WhatsAppController) checkWaitlist() {
+	wc.waitlistMu.Lock()
+	defer wc.waitlistMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	now := time.Now().In(services.ClinicLocation())
+	expired, err := wc.waitlistService.ExpireOffers(ctx, now.Format("2006-01-02"))
+	if err != nil {
+		log.Println("waitlist error:", err)
+	}
+	for _, entry := range expired {
+		_ = wc.whatsappService.SendTextMessage(entry.Phone, i18n.T(i18n.Locale(entry.Locale), "waitlist.offer_expired"))
+	}
+
+	entries, err := wc.waitlistService.Waiting(ctx)
+	if err != nil {
+		log.Println("waitlist error:", err)
+		return
+	}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		wc.offerOpenedSlot(ctx, entry, now)
+	}
+}
+
+// offerOpenedSlot holds the earliest open slot in an entry's range that the
+// patient has not passed on and sends it to them
+func (wc *WhatsAppController) offerOpenedSlot(ctx context.Context, entry models.WaitlistEntry, now time.Time) {
+	// Joining the waitlist asked for offers, but an opt-out since then wins
+	if err := wc.contactService.CheckConsent(ctx, entry.Phone); err != nil && !errors.Is(err, services.ErrNoOptIn) {
+		if errors.Is(err, services.ErrContactOptedOut) {
+			wc.leaveWaitlists(ctx, entry.Phone)
+		} else {
+			log.Println("waitlist consent lookup error:", err)
+		}
+		return
+	}
+
+	from := now
+	if date, err := time.ParseInLocation("2006-01-02", entry.FromDate, services.ClinicLocation()); err == nil && date.After(from) {
+		from = date
+	}
+	passed := make(map[models.SlotRef]bool, len(entry.Passed))
+	for _, ref := range entry.Passed {
+		passed[ref] = true
+	}
+
+	holderID := waitlistHolder(entry.ID.Hex())
+	for _, slot := range wc.earliestSlots(ctx, holderID, entry.DepartmentID, int(entry.DoctorID), from, entry.TimeWindow) {
+		if slot.Date > entry.ToDate || passed[slot.ref()] {
+			continue
+		}
+		held, err := wc.slotHoldService.HoldFor(ctx, holderID, []models.SlotRef{slot.ref()}, wc.waitlistOfferTTL)
+		if err != nil {
+			log.Println("waitlist error:", err)
+			return
+		}
+		if len(held) == 0 {
+			continue
+		}
+
+		offeredAt := time.Now()
+		offer := models.WaitlistOffer{
+			SlotRef:        slot.ref(),
+			DoctorName:     slot.DoctorName,
+			Token:          slot.Token,
+			AvailabilityID: slot.AvailabilityID,
+			OfferedAt:      offeredAt,
+			ExpiresAt:      offeredAt.Add(wc.waitlistOfferTTL),
+		}
+		if err := wc.waitlistService.Offer(ctx, entry.ID, offer); err != nil {
+			log.Println("waitlist error:", err)
+			wc.releaseWaitlistHold(ctx, entry.ID.Hex())
+			return
+		}
+		if err := wc.sendWaitlistOffer(entry, slot); err != nil {
+			// An offer the patient never sees would keep the slot from
+			// everyone behind them until it expired
+			log.Println("waitlist offer not sent to", entry.Phone, err)
+			if err := wc.waitlistService.Withdraw(ctx, entry.ID); err != nil {
+				log.Println("waitlist error:", err)
+			}
+			wc.releaseWaitlistHold(ctx, entry.ID.Hex())
+		}
+		return
+	}
+}
+
+// leaveWaitlists takes a patient who opted out off every waitlist, freeing
+// the slots held for their offers
+func (wc *WhatsAppController) leaveWaitlists(ctx context.Context, userID string) {
+	offered, err := wc.waitlistService.LeaveAll(ctx, userID)
+	if err != nil {
+		log.Println("waitlist error:", err)
+	}
+	for _, entry := range offered {
+		wc.releaseWaitlistHold(ctx, entry.ID.Hex())
+	}
+	if len(offered) > 0 {
+		wc.requestWaitlistCheck()
+	}
+}
+
+// waitlistHolder holds slots offered to a waitlist entry, apart from the
+// patient's own holds, which change as they browse slots
+func waitlistHolder(entryID string) string {
+	return "waitlist:" + entryID
+}
+
+func (wc *WhatsAppController) releaseWaitlistHold(ctx context.Context, entryID string) {
+	if err := wc.slotHoldService.Release(ctx, waitlistHolder(entryID)); err != nil {
+		log.Println("slot hold error:", err)
+	}
+}
+
+// sendWaitlistOffer tells a waitlisted patient about the slot held for them.
+// Offers usually go out long after the patient last wrote, so outside the
+// service window they are sent as the approved offer template, which the
+// patient answers with YES or NO instead of buttons.
+func (wc *WhatsAppController) sendWaitlistOffer(entry models.WaitlistEntry, slot SlotOption) error {
+	locale := i18n.Locale(entry.Locale)
+	id := entry.ID.Hex()
+	date := slot.startsAt().Format("Mon 02 Jan")
+	minutes := int(wc.waitlistOfferTTL / time.Minute)
+
+	if wc.waitlistTemplate != "" {
+		open, _, err := wc.whatsappService.IsWithinServiceWindow(entry.Phone)
+		if err != nil || !open {
+			return wc.whatsappService.SendTemplate(entry.Phone, models.TemplateMessage{
+				Name:       wc.waitlistTemplate,
+				Language:   locale.TemplateLanguage(),
+				BodyParams: []string{slot.DoctorName, date, slot.Time, strconv.Itoa(minutes)},
+			})
+		}
+	}
+
+	interactive := &models.InteractiveMessage{
+		Type: "button",
+		Body: &models.InteractiveBody{
+			Text: i18n.T(locale, "waitlist.offer", slot.DoctorName, date, slot.Time, minutes),
+		},
+		Action: &models.InteractiveAction{
+			Buttons: []models.InteractiveButton{
+				{Type: "reply", Reply: &models.ButtonReply{ID: waitlistBookPrefix + id, Title: i18n.T(locale, "waitlist.book_button")}},
+				{Type: "reply", Reply: &models.ButtonReply{ID: waitlistSkipPrefix + id, Title: i18n.T(locale, "waitlist.skip_button")}},
+				{Type: "reply", Reply: &models.ButtonReply{ID: waitlistLeavePrefix + id, Title: i18n.T(locale, "waitlist.leave_button")}},
+			},
+		},
+	}
+	return wc.whatsappService.SendInteractiveMessage(entry.Phone, interactive)
+}
+
+// SlotOpened is called by HMS when an appointment is cancelled. The doctor's
+// cached slots for the day are dropped and a waitlist check is requested.
+func (wc *WhatsAppController) SlotOpened(c *gin.Context) {
+	var req models.SlotOpenedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be a YYYY-MM-DD date", "details": err.Error()})
+		return
+	}
+
+	wc.slotSearch.forget(req.DoctorID, req.Date)
+	wc.requestWaitlistCheck()
+	c.JSON(http.StatusAccepted, gin.H{"message": "Waitlist check requested"})
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WaitlistController struct {
+	waitlistService *services.WaitlistService
+}
+
+func NewWaitlistController(waitlistService *services.WaitlistService) *WaitlistController {
+	return &WaitlistController{
+		waitlistService: waitlistService,
+	}
+}
+
+// ListEntries returns waitlist entries in the order they are served. Query:
+// status, limit.
+func (wl *WaitlistController) ListEntries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	status := models.WaitlistStatus(c.Query("status"))
+
+	entries, err := wl.waitlistService.List(c.Request.Context(), status, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list waitlist",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"clinic-chatbot-backend/config"
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/notify"
+	"clinic-chatbot-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// fakeBackend stands in for the HTTP services the controller calls. WhatsApp
+// sends are recorded; HMS endpoints are answered by path. Requests made on a
+// cancelled context fail, as they would over the network.
+type fakeBackend struct {
+	mu   sync.Mutex
+	hms  map[string]func(body []byte) string
+	sent chan string
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		hms:  make(map[string]func(body []byte) string),
+		sent: make(chan string, 100),
+	}
+}
+
+// handle answers an HMS path such as "/api/patient/create"
+func (f *fakeBackend) handle(path string, answer func(body []byte) string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.hms[path] = answer
+}
+
+func (f *fakeBackend) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+
+	reply := func(status int, text string) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(text)),
+			Request:    req,
+		}
+	}
+
+	if req.URL.Host == "graph.facebook.com" {
+		f.sent <- string(body)
+		return reply(http.StatusOK, `{"messages":[{"id":"wamid.out"}]}`), nil
+	}
+
+	f.mu.Lock()
+	answer, ok := f.hms[req.URL.Path]
+	f.mu.Unlock()
+	if !ok {
+		return reply(http.StatusNotFound, `{"status":false,"message":"not found"}`), nil
+	}
+	return reply(http.StatusOK, answer(body)), nil
+}
+
+// waitForSend returns the first WhatsApp message sent that contains text
+func (f *fakeBackend) waitForSend(t *testing.T, text string) string {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case body := <-f.sent:
+			if strings.Contains(body, text) {
+				return body
+			}
+		case <-timeout:
+			t.Fatalf("no WhatsApp message containing %q was sent", text)
+			return ""
+		}
+	}
+}
+
+// newTestController builds the WhatsApp controller the way routes does, with
+// HTTP calls going to backend and a Mongo client that has no server behind
+// it, so every Mongo call fails quickly
+func newTestController(t *testing.T, backend *fakeBackend) *WhatsAppController {
+	t.Helper()
+	t.Setenv("GOOGLE_API_KEY", "test")
+	t.Setenv("JWT_SECRET", "test")
+	t.Setenv("JWT_REFRESH_SECRET", "test")
+	t.Setenv("WHATSAPP_ACCESS_TOKEN", "test")
+	t.Setenv("WHATSAPP_PHONE_NUMBER_ID", "1")
+	if err := config.Load(); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg := config.Get()
+
+	client, err := mongo.Connect(context.Background(), options.Client().
+		ApplyURI("mongodb://127.0.0.1:1").
+		SetServerSelectionTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("mongo client: %v", err)
+	}
+	database.UseMongoDB(client.Database("test"))
+
+	// Not restored: handling may still be running when a test ends, and must
+	// never reach the real HMS
+	http.DefaultTransport = backend
+
+	aiService := services.NewAIService()
+	inboxService := services.NewInboxService()
+	contactService := services.NewContactService()
+	whatsappService := services.NewWhatsAppService(contactService, cfg.Region)
+	emergencyService := services.NewEmergencyService(cfg.Emergency, notify.NewDispatcher(services.NewWhatsAppNotifier(whatsappService, "")))
+	doctorService := services.NewDoctorService()
+	chatbotService := services.NewChatbotService(aiService, inboxService, emergencyService, services.NewClinicService(), services.NewKnowledgeService(aiService), doctorService)
+
+	return NewWhatsAppController(whatsappService, chatbotService, inboxService, contactService,
+		services.NewTemplateService(whatsappService), emergencyService, services.NewMailService(cfg.Email),
+		services.NewFallbackService(whatsappService, nil, cfg.SMS.FallbackAfter), doctorService,
+		services.NewSlotHoldService(cfg.Booking.HoldTTL), services.NewWaitlistService(),
+		services.NewHouseholdService(), cfg.Booking)
+}
+
+// postText delivers a text message through HandleWebhook. The request context
+// is cancelled once the handler returns, as net/http does.
+func postText(t *testing.T, wc *WhatsAppController, from, text string) {
+	t.Helper()
+	payload, _ := json.Marshal(models.WhatsAppWebhookData{
+		Object: "whatsapp_business_account",
+		Entry: []models.WhatsAppEntry{{Changes: []models.WhatsAppChange{{
+			Field: "messages",
+			Value: models.WhatsAppValue{Messages: []models.WhatsAppMessage{{
+				From: from,
+				ID:   "wamid.in",
+				Type: "text",
+				Text: &models.WhatsAppText{Body: text},
+			}}},
+		}}}},
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/webhook", wc.HandleWebhook)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	cancel()
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("webhook answered %d", rec.Code)
+	}
+}
+
+func TestWebhookMessageHandledAfterResponse(t *testing.T) {
+	backend := newFakeBackend()
+	backend.handle("/api/patient/search", func([]byte) string {
+		return `{"status":true,"data":[]}`
+	})
+	created := make(chan struct{}, 1)
+	backend.handle("/api/patient/create", func([]byte) string {
+		created <- struct{}{}
+		return `{"status":true,"data":{"patientId":7,"patientCode":"PC-7"}}`
+	})
+	wc := newTestController(t, backend)
+
+	// Each test writes from its own number, as handling outlives the test
+	appointmentState["919800000001"] = &AppointmentData{
+		PatientName: "Anita Rao",
+		Address:     "12 MG Road",
+		PhoneNumber: "9876543210",
+		Step:        "await_patient_dateOfBirth",
+	}
+
+	postText(t, wc, "919800000001", "1990-04-12")
+
+	select {
+	case <-created:
+	case <-time.After(10 * time.Second):
+		t.Fatal("HMS was not asked to create the patient after the webhook returned")
+	}
+	backend.waitForSend(t, "PC-7")
+}
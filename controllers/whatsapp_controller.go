@@ -5,8 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-
-	// "errors"
+	"errors"
 
 	// "errors"
 	"io"
@@ -18,24 +17,74 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	// "net/http/httputil"
 	"strings"
+	"unicode"
 
+	"clinic-chatbot-backend/config"
+	"clinic-chatbot-backend/i18n"
 	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/notify/email"
 	"clinic-chatbot-backend/services"
+	"clinic-chatbot-backend/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 type WhatsAppController struct {
-	whatsappService *services.WhatsAppService
-	chatbotService  *services.ChatbotService
+	whatsappService  *services.WhatsAppService
+	chatbotService   *services.ChatbotService
+	inboxService     *services.InboxService
+	contactService   *services.ContactService
+	templateService  *services.TemplateService
+	emergencyService *services.EmergencyService
+	mailService      *services.MailService
+	fallbackService  *services.FallbackService
+	doctorService    *services.DoctorService
+	slotSearch       *slotSearch
+	slotHoldService  *services.SlotHoldService
+	slotGenerator    utils.SlotGenerator
+	waitlistService  *services.WaitlistService
+	householdService *services.HouseholdService
+	waitlistDays     int           // days a waitlist entry covers
+	waitlistPoll     time.Duration // how often HMS is checked for waitlisted patients
+	waitlistOfferTTL time.Duration // how long an opened slot is held for the patient offered it
+	waitlistTemplate string        // approved template offers are sent with outside the service window
+	waitlistMu       sync.Mutex    // one waitlist check at a time
+	waitlistCheck    chan struct{} // requests a waitlist check before the next poll
 }
 
-func NewWhatsAppController(whatsappService *services.WhatsAppService, chatbotService *services.ChatbotService) *WhatsAppController {
+func NewWhatsAppController(whatsappService *services.WhatsAppService, chatbotService *services.ChatbotService, inboxService *services.InboxService, contactService *services.ContactService, templateService *services.TemplateService, emergencyService *services.EmergencyService, mailService *services.MailService, fallbackService *services.FallbackService, doctorService *services.DoctorService, slotHoldService *services.SlotHoldService, waitlistService *services.WaitlistService, householdService *services.HouseholdService, bookingCfg config.BookingConfig) *WhatsAppController {
+	if bookingCfg.WaitlistDays <= 0 {
+		bookingCfg.WaitlistDays = 7
+	}
+	if bookingCfg.WaitlistPoll <= 0 {
+		bookingCfg.WaitlistPoll = 5 * time.Minute
+	}
+	if bookingCfg.WaitlistOfferTTL <= 0 {
+		bookingCfg.WaitlistOfferTTL = 15 * time.Minute
+	}
 	return &WhatsAppController{
-		whatsappService: whatsappService,
-		chatbotService:  chatbotService,
+		whatsappService:  whatsappService,
+		chatbotService:   chatbotService,
+		inboxService:     inboxService,
+		contactService:   contactService,
+		templateService:  templateService,
+		emergencyService: emergencyService,
+		mailService:      mailService,
+		fallbackService:  fallbackService,
+		doctorService:    doctorService,
+		slotSearch:       newSlotSearch(bookingCfg),
+		slotHoldService:  slotHoldService,
+		slotGenerator:    newSlotGenerator(bookingCfg),
+		waitlistService:  waitlistService,
+		householdService: householdService,
+		waitlistDays:     bookingCfg.WaitlistDays,
+		waitlistPoll:     bookingCfg.WaitlistPoll,
+		waitlistOfferTTL: bookingCfg.WaitlistOfferTTL,
+		waitlistTemplate: bookingCfg.WaitlistTemplate,
+		waitlistCheck:    make(chan struct{}, 1),
 	}
 }
 
@@ -57,10 +106,28 @@ type AppointmentData struct {
 	AppointmentDate string `json:"appointmentDate"`
 	DoctorID        uint   `json:"doctorId"`
 	DoctorName      string `json:"doctorName"`
-	OnlineTempToken uint   `json:"onlineTempToken"`
+	OnlineTempToken uint   `json:"onlineTempToken"`          // slot number in the doctor's day, counted here
+	AvailabilityID  int    `json:"availabilityId,omitempty"` // HMS session the slot is in
 	TimeSlot        string `json:"timeSlot"`
 	Step            string `json:"step"`
 	CreatedFrom     string `json:"createdFrom"`
+
+	// Details taken from a free-text request or the doctor directory; not sent to HMS
+	PreferredDoctor   string   `json:"-"`
+	PreferredDoctorID int      `json:"-"`
+	TimeWindow        string   `json:"-"`
+	Symptoms          []string `json:"-"`
+	ForRelation       string   `json:"-"` // family member asked for, e.g. "daughter"
+
+	// Set when the patient asked for the first available slot instead of a date
+	FirstAvailable bool         `json:"-"`
+	EarliestSlots  []SlotOption `json:"-"`
+
+	// Patient registration: set when adding a family member outside a
+	// booking, records that may be the new patient, and failed attempts
+	RegisterOnly         bool      `json:"-"`
+	ExistingRecords      []Patient `json:"-"`
+	RegistrationAttempts int       `json:"-"`
 }
 
 var appointmentState = make(map[string]*AppointmentData) // userID → data
@@ -71,6 +138,14 @@ var (
 	stateMutex sync.Mutex
 )
 
+// localePreference is the conversation language of a WhatsApp user
+type localePreference struct {
+	Locale   i18n.Locale
+	Explicit bool // chosen from the language menu rather than detected
+}
+
+var userLocale = make(map[string]localePreference) // userID -> language, guarded by stateMutex
+
 // Appointment structure
 // type Appointment struct {
 //     ID     string
@@ -79,19 +154,7 @@ var (
 //     Time   string
 // }
 
-// Very simple phone validation
-func isValidPhone(phone string) bool {
-	if len(phone) < 8 || len(phone) > 15 {
-		return false
-	}
-	for _, ch := range phone {
-		if ch < '0' || ch > '9' {
-			return false
-		}
-	}
-	return true
-}
-
+// callExternalAPI GETs an HMS endpoint and decodes its JSON response
 func callExternalAPI[T any](ctx context.Context, url string, target *T) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -172,28 +235,28 @@ func callExternalAPICallForPost[T any](ctx context.Context, method, url string,
 }
 
 func (wc *WhatsAppController) handleNewAppointment(ctx context.Context, userID string, message models.WhatsAppMessage) {
+	locale := wc.localeFor(userID)
 	state, exists := appointmentState[userID]
 	if !exists {
-		appointmentState[userID] = &AppointmentData{Step: "ask_patient_code_or_phone_number"}
-		_ = wc.whatsappService.SendTextMessage(
-			userID,
-			"🩺 Have you already consulted here before? (Yes/No)",
-		)
+		wc.startBooking(userID, &AppointmentData{})
 		return
 	}
 
 	switch state.Step {
 	case "ask_patient_code_or_phone_number":
 		if message.Type == "text" && message.Text != nil {
-			ans := strings.ToLower(strings.TrimSpace(message.Text.Body))
-			if ans == "yes" {
+			ans := message.Text.Body
+			if i18n.IsYes(ans) {
 				state.Step = "await_patient_code_or_phone_number"
-				_ = wc.whatsappService.SendTextMessage(userID, "📋 Please enter your patient id or phone number:")
-			} else if ans == "no" {
+				_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.ask_patient_id"))
+			} else if i18n.IsNo(ans) && state.PatientName != "" {
+				state.Step = "await_patient_address"
+				_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.ask_address"))
+			} else if i18n.IsNo(ans) {
 				state.Step = "await_patient_name"
-				_ = wc.whatsappService.SendTextMessage(userID, "👤 Please enter your full name:")
+				_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.ask_name"))
 			} else {
-				_ = wc.whatsappService.SendTextMessage(userID, "❌ Please reply Yes or No.")
+				_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.reply_yes_no"))
 			}
 		}
 
@@ -201,12 +264,14 @@ func (wc *WhatsAppController) handleNewAppointment(ctx context.Context, userID s
 		code := strings.TrimSpace(message.Text.Body)
 		patients, err := wc.verifyPatientCode(code)
 		if err != nil || len(patients) == 0 {
-			_ = wc.whatsappService.SendTextMessage(userID, "❌ No patient found. Please try again.")
+			_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.patient_not_found"))
 			delete(appointmentState, userID)
 			_ = wc.sendMainMenu(userID)
 			return
 		}
 
+		wc.rememberHousehold(userID, patients)
+
 		if len(patients) > 1 {
 			state.Step = "choose_patient_from_list"
 			_ = wc.sendPatientDetailsList(userID, patients)
@@ -215,15 +280,15 @@ func (wc *WhatsAppController) handleNewAppointment(ctx context.Context, userID s
 
 		// If exactly one patient found, save directly
 		patient := patients[0]
+		wc.rememberPatient(userID, patient, state.ForRelation)
 		state.PatientID = patient.ID
 		state.PatientCode = patient.PatientCode
 		state.PatientName = fmt.Sprintf("%s %s", patient.FirstName, patient.LastName)
 		state.PhoneNumber = patient.MobileNumber
 		state.Address = patient.Address
 		state.DateOfBirth = patient.DateOfBirth
-		state.Step = "choose_department"
 
-		_ = wc.sendDepartmentsList(userID)
+		wc.continueBooking(userID, state)
 
 	case "choose_patient_from_list":
 		if message.Interactive != nil && message.Interactive.ListReply != nil {
@@ -232,13 +297,14 @@ func (wc *WhatsAppController) handleNewAppointment(ctx context.Context, userID s
 			// 🔹 Call API again with selected ID/Code
 			selectedPatients, err := wc.verifyPatientCode(selectedID)
 			if err != nil || len(selectedPatients) == 0 {
-				_ = wc.whatsappService.SendTextMessage(userID, "❌ Could not fetch patient details. Please try again.")
+				_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.patient_fetch_failed"))
 				delete(appointmentState, userID)
 				_ = wc.sendMainMenu(userID)
 				return
 			}
 
 			patient := selectedPatients[0]
+			wc.rememberPatient(userID, patient, state.ForRelation)
 
 			// Save only the chosen patient details
 			state.PatientID = patient.ID
@@ -247,30 +313,50 @@ func (wc *WhatsAppController) handleNewAppointment(ctx context.Context, userID s
 			state.PhoneNumber = patient.MobileNumber
 			state.Address = patient.Address
 			state.DateOfBirth = patient.DateOfBirth
-			state.Step = "choose_department"
 
-			_ = wc.sendDepartmentsList(userID)
+			wc.continueBooking(userID, state)
 		}
 
 	case "await_patient_name":
 		state.PatientName = message.Text.Body
 		state.Step = "await_patient_address"
-		_ = wc.whatsappService.SendTextMessage(userID, "🏠 Please enter your address:")
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.ask_address"))
 
 	case "await_patient_address":
 		state.Address = message.Text.Body
+		if state.PhoneNumber != "" {
+			// A new patient booking from their own number
+			state.Step = "await_patient_dateOfBirth"
+			_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.ask_dob"))
+			return
+		}
 		state.Step = "await_patient_phone"
-		_ = wc.whatsappService.SendTextMessage(userID, "📞 Please enter your phone number:")
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.ask_phone"))
 
 	case "await_patient_phone":
-		state.PhoneNumber = message.Text.Body
+		number, err := wc.whatsappService.ParsePhone(message.Text.Body)
+		if err != nil {
+			_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.invalid_phone"))
+			return
+		}
+		state.PhoneNumber = number.Format(wc.whatsappService.Region())
 		state.Step = "await_patient_dateOfBirth"
-		_ = wc.whatsappService.SendTextMessage(userID, "📅 Please enter your date of birth (YYYY-MM-DD):")
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.ask_dob"))
 
 	case "await_patient_dateOfBirth":
-		state.DateOfBirth = message.Text.Body
-		state.Step = "choose_department"
-		_ = wc.sendDepartmentsList(userID)
+		dob := strings.TrimSpace(message.Text.Body)
+		if _, err := time.Parse("2006-01-02", dob); err != nil {
+			_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.invalid_dob"))
+			return
+		}
+		state.DateOfBirth = dob
+		wc.registerNewPatient(ctx, userID, state)
+
+	case "choose_existing_record":
+		wc.chooseExistingRecord(ctx, userID, state, message)
+
+	case "retry_registration":
+		wc.answerRegistrationRetry(ctx, userID, state, message)
 
 	case "choose_department":
 		if message.Type == "interactive" && message.Interactive.ListReply != nil {
@@ -283,15 +369,17 @@ func (wc *WhatsAppController) handleNewAppointment(ctx context.Context, userID s
 				state.DepartmentID = uint(idInt) // ✅ assign to your uint field
 			}
 			// state.DepartmentID = message.Interactive.ListReply.ID
-			state.Step = "await_date"
-			_ = wc.whatsappService.SendTextMessage(userID, "📅 Please enter your preferred date (YYYY-MM-DD):")
+			wc.continueBooking(userID, state)
 		}
 
 	case "await_date":
 		log.Println("appointment date from whatsapp: ", message.Text.Body)
-		state.AppointmentDate = message.Text.Body
-		state.Step = "choose_doctor"
-		_ = wc.sendDoctorsList(userID, state.DepartmentID, state.AppointmentDate)
+		if i18n.IsFirstAvailable(message.Text.Body) {
+			state.FirstAvailable = true
+		} else {
+			state.AppointmentDate = message.Text.Body
+		}
+		wc.continueBooking(userID, state)
 
 	// case "choose_doctor":
 	// 	if message.Type == "interactive" && message.Interactive.ListReply != nil {
@@ -310,22 +398,30 @@ func (wc *WhatsAppController) handleNewAppointment(ctx context.Context, userID s
 
 	case "choose_doctor":
 		if message.Type == "interactive" && message.Interactive.ListReply != nil {
+			if message.Interactive.ListReply.ID == firstAvailableRow {
+				state.FirstAvailable = true
+				wc.sendEarliestSlots(userID, state)
+				return
+			}
 			idInt, err := strconv.Atoi(message.Interactive.ListReply.ID)
 			if err != nil {
 				log.Println("Invalid ID from WhatsApp:", message.Interactive.ListReply.ID, err)
-			} else {
-				state.DoctorID = uint(idInt)
 			}
-			state.DoctorName = message.Interactive.ListReply.Title
+			wc.selectDoctor(userID, state, Doctor{ID: idInt, DoctorName: message.Interactive.ListReply.Title})
+		}
 
-			// check slots
-			hasSlots, _ := wc.sendSlotsList(userID, state.DoctorID, state.AppointmentDate)
-			if hasSlots {
-				state.Step = "choose_slot"
-			} else {
-				// reset appointment state
-				delete(appointmentState, userID)
+	case "choose_earliest_slot":
+		if message.Type == "interactive" && message.Interactive.ListReply != nil {
+			option, ok := findSlot(state.EarliestSlots, message.Interactive.ListReply.ID)
+			if !ok {
+				log.Println("Unknown slot from WhatsApp:", message.Interactive.ListReply.ID)
+				_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.slot_stale"))
+				wc.sendEarliestSlots(userID, state)
+				return
 			}
+			state.DoctorName = option.DoctorName
+			state.CreatedFrom = message.From
+			wc.bookSelectedSlot(userID, state, option)
 		}
 
 	case "choose_slot":
@@ -343,45 +439,272 @@ func (wc *WhatsAppController) handleNewAppointment(ctx context.Context, userID s
 				return
 			}
 
-			// state.OnlineTempToken = message.Interactive.ListReply.ID
-
-			// Convert ID (string) -> uint
-			idInt, err := strconv.Atoi(message.Interactive.ListReply.ID)
-			if err != nil {
-				log.Println("Invalid ID from WhatsApp:", message.Interactive.ListReply.ID, err)
-			} else {
-				state.OnlineTempToken = uint(idInt) // ✅ assign to your uint field
+			// Rows carry the slot ID; a tap on an older list is not guessed at
+			var option SlotOption
+			ok := false
+			if slots := slotState[userID]; slots != nil {
+				option, ok = findSlot(slots.Slots, selectedID)
+			}
+			if !ok {
+				log.Println("Unknown slot from WhatsApp:", selectedID, selectedTitle)
+				_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.slot_stale"))
+				wc.selectDoctor(userID, state, Doctor{ID: int(state.DoctorID), DoctorName: state.DoctorName})
+				return
 			}
-			state.TimeSlot = selectedTitle
 			state.CreatedFrom = message.From
-			appointmentDate := state.AppointmentDate
-			t, err := time.Parse("2006-01-02", appointmentDate)
-			if err != nil {
-				log.Println("Invalid date from WhatsApp:", appointmentDate, err)
+			wc.bookSelectedSlot(userID, state, option)
+		}
+
+	case "choose_household_member":
+		wc.chooseHouseholdMember(userID, state, message)
+
+	case "confirm_patient":
+		wc.answerConfirmMember(userID, state, message)
+
+	case "confirm_new_patient":
+		wc.answerNewPatient(userID, state, message)
+
+	case "await_member_name":
+		if message.Type == "text" && message.Text != nil {
+			state.PatientName = strings.TrimSpace(message.Text.Body)
+			if state.ForRelation != "" {
+				wc.askMemberDateOfBirth(userID, state)
+				return
 			}
-			appointmentDate = t.Format("Jan 02, 2006")
-			log.Println("appointment date from whatsapp: ", appointmentDate)
-			// t, err := time.Parse("2006-01-02T15:04:05", state.AppointmentDate)
-			// if err != nil {
-			// 	log.Println("Invalid date from WhatsApp:", state.AppointmentDate, err)
-			// }
-			// state.AppointmentDate = t.Format("Jan 02, 2006")
-			success := wc.createAppointment(state, userID)
-			if success {
-				_ = wc.whatsappService.SendTextMessage(userID,
-					fmt.Sprintf("✅ Appointment booked with %s on %s at %s",
-						state.DoctorName, appointmentDate, state.TimeSlot))
-			} else {
-				_ = wc.whatsappService.SendTextMessage(userID, "⚠️ Failed to book appointment. Try again later.")
+			state.Step = "choose_member_relation"
+			_ = wc.sendRelationList(userID, state.PatientName)
+		}
+
+	case "choose_member_relation":
+		relation := ""
+		if message.Interactive != nil && message.Interactive.ListReply != nil {
+			relation = strings.TrimPrefix(message.Interactive.ListReply.ID, relationRowPrefix)
+		} else if message.Type == "text" && message.Text != nil {
+			relation, _ = i18n.FamilyRelation(message.Text.Body)
+		}
+		if relation == "" {
+			_ = wc.sendRelationList(userID, state.PatientName)
+			return
+		}
+		state.ForRelation = relation
+		wc.askMemberDateOfBirth(userID, state)
+
+	case "await_member_dob":
+		if message.Type == "text" && message.Text != nil {
+			dob := strings.TrimSpace(message.Text.Body)
+			if _, err := time.Parse("2006-01-02", dob); err != nil {
+				_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.invalid_dob"))
+				return
+			}
+			state.DateOfBirth = dob
+			if state.Address = wc.householdAddress(ctx, userID); state.Address != "" {
+				wc.registerMember(ctx, userID, state)
+				return
 			}
+			state.Step = "await_member_address"
+			_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "family.ask_address", state.PatientName))
+		}
 
-			log.Println("Appointment state: ", appointmentState)
-			b, _ := json.MarshalIndent(state, "", "  ")
-			log.Println("Appointment state: ", string(b))
+	case "await_member_address":
+		if message.Type == "text" && message.Text != nil {
+			state.Address = strings.TrimSpace(message.Text.Body)
+			wc.registerMember(ctx, userID, state)
+		}
 
-			delete(appointmentState, userID)
-			_ = wc.sendMainMenu(userID)
+	case "offer_waitlist":
+		joined := message.Type == "interactive" && message.Interactive.ButtonReply != nil &&
+			message.Interactive.ButtonReply.ID == waitlistJoinButton
+		if message.Type == "text" && message.Text != nil && i18n.IsYes(message.Text.Body) {
+			joined = true
 		}
+		if joined {
+			wc.joinWaitlist(ctx, userID, state)
+			return
+		}
+		delete(appointmentState, userID)
+		_ = wc.sendMainMenu(userID)
+	}
+}
+
+// bookSelectedSlot creates the appointment for the chosen slot and ends the
+// booking, reporting whether the appointment was made
+func (wc *WhatsAppController) bookSelectedSlot(userID string, state *AppointmentData, slot SlotOption) bool {
+	locale := wc.localeFor(userID)
+	state.DoctorID = uint(slot.DoctorID)
+	state.AppointmentDate = slot.Date
+	state.TimeSlot = slot.Time
+	state.OnlineTempToken = slot.Token
+	state.AvailabilityID = slot.AvailabilityID
+	if !wc.holdSlot(userID, slot) {
+		// Our hold lapsed and another patient took the slot meanwhile
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.slot_taken"))
+		if state.Step == "choose_earliest_slot" {
+			wc.sendEarliestSlots(userID, state)
+		} else {
+			wc.selectDoctor(userID, state, Doctor{ID: int(state.DoctorID), DoctorName: state.DoctorName})
+		}
+		return false
+	}
+	defer wc.releaseSlots(userID)
+
+	appointmentDate := state.AppointmentDate
+	t, err := time.Parse("2006-01-02", appointmentDate)
+	if err != nil {
+		log.Println("Invalid date from WhatsApp:", appointmentDate, err)
+	}
+	appointmentDate = t.Format("Jan 02, 2006")
+	log.Println("appointment date from whatsapp: ", appointmentDate)
+	// t, err := time.Parse("2006-01-02T15:04:05", state.AppointmentDate)
+	// if err != nil {
+	// 	log.Println("Invalid date from WhatsApp:", state.AppointmentDate, err)
+	// }
+	// state.AppointmentDate = t.Format("Jan 02, 2006")
+	success := wc.createAppointment(state, userID)
+	if success {
+		_ = wc.whatsappService.SendTextMessage(userID,
+			i18n.T(locale, "booking.confirmed", state.DoctorName, appointmentDate, state.TimeSlot))
+	} else {
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.failed"))
+	}
+
+	log.Println("Appointment state: ", appointmentState)
+	b, _ := json.MarshalIndent(state, "", "  ")
+	log.Println("Appointment state: ", string(b))
+
+	delete(appointmentState, userID)
+	_ = wc.sendMainMenu(userID)
+	return success
+}
+
+// startBooking opens the booking flow, keeping any details already known.
+// Numbers with saved patients choose one of them, and other numbers are
+// looked up in HMS before the patient is asked anything.
+func (wc *WhatsAppController) startBooking(userID string, data *AppointmentData) {
+	appointmentState[userID] = data
+	if wc.offerHousehold(userID, data) || wc.recognisePatient(userID, data) {
+		return
+	}
+	wc.askConsultedBefore(userID, data)
+}
+
+// askConsultedBefore starts identifying the patient of a booking
+func (wc *WhatsAppController) askConsultedBefore(userID string, data *AppointmentData) {
+	data.Step = "ask_patient_code_or_phone_number"
+	_ = wc.whatsappService.SendTextMessage(
+		userID,
+		i18n.T(wc.localeFor(userID), "booking.ask_consulted_before"),
+	)
+}
+
+// startBookingFromText opens the booking flow for a free-text request such as
+// "book cardiology tomorrow evening with Dr Rao", prefilling what it mentions
+func (wc *WhatsAppController) startBookingFromText(ctx context.Context, userID, text string) {
+	entities := wc.chatbotService.ExtractEntities(ctx, text)
+	data := &AppointmentData{
+		PatientName:     entities.PatientName,
+		AppointmentDate: entities.Date,
+		PreferredDoctor: entities.DoctorName,
+		TimeWindow:      entities.TimeWindow,
+		Symptoms:        entities.Symptoms,
+	}
+	data.ForRelation, _ = i18n.FamilyRelation(text)
+
+	noted := []string{}
+	if entities.Department != "" {
+		if dept, ok := wc.findDepartment(entities.Department); ok {
+			data.DepartmentID = uint(dept.ID)
+			noted = append(noted, dept.DepartmentName)
+		}
+	}
+	if entities.DoctorName != "" {
+		noted = append(noted, "Dr. "+entities.DoctorName)
+	}
+	if entities.Date != "" {
+		noted = append(noted, entities.Date)
+	}
+	if entities.TimeWindow != "" {
+		noted = append(noted, entities.TimeWindow)
+	}
+	noted = append(noted, entities.Symptoms...)
+	if len(noted) > 0 {
+		_ = wc.whatsappService.SendTextMessage(userID,
+			i18n.T(wc.localeFor(userID), "booking.noted", strings.Join(noted, ", ")))
+	}
+
+	wc.startBooking(userID, data)
+}
+
+// continueBooking moves to the next detail the patient has not given yet,
+// skipping questions answered in a free-text request
+func (wc *WhatsAppController) continueBooking(userID string, state *AppointmentData) {
+	switch {
+	case state.DepartmentID == 0:
+		state.Step = "choose_department"
+		_ = wc.sendDepartmentsList(userID)
+
+	case state.AppointmentDate == "" && !state.FirstAvailable:
+		state.Step = "await_date"
+		locale := wc.localeFor(userID)
+		_ = wc.whatsappService.SendTextMessage(userID,
+			i18n.T(locale, "booking.ask_date")+"\n"+i18n.T(locale, "booking.first_available_hint"))
+
+	case wc.rejectHolidayDate(userID, state):
+		// Asked for another date; HMS is not queried for a closed day
+
+	case state.PatientID == 0 && state.PatientName == "":
+		// Booked from the doctor directory, which starts at the date
+		wc.startBooking(userID, state)
+
+	case state.FirstAvailable:
+		wc.sendEarliestSlots(userID, state)
+
+	default:
+		state.Step = "choose_doctor"
+		if state.PreferredDoctorID != 0 || state.PreferredDoctor != "" {
+			doctors, err := wc.fetchDoctors(state.DepartmentID, state.AppointmentDate)
+			if err != nil {
+				log.Println("API fetching error", err)
+			}
+			doctor, ok := doctorByID(doctors, state.PreferredDoctorID)
+			if !ok {
+				doctor, ok = matchDoctor(doctors, state.PreferredDoctor)
+			}
+			if ok {
+				wc.selectDoctor(userID, state, doctor)
+				return
+			}
+		}
+		_ = wc.sendDoctorsList(userID, state.DepartmentID, state.AppointmentDate)
+	}
+}
+
+// rejectHolidayDate asks for another date when the clinic is closed for a
+// holiday on the chosen one
+func (wc *WhatsAppController) rejectHolidayDate(userID string, state *AppointmentData) bool {
+	holiday, closed := wc.chatbotService.ClinicHoliday(context.Background(), state.AppointmentDate)
+	if !closed {
+		return false
+	}
+
+	_ = wc.whatsappService.SendTextMessage(userID,
+		i18n.T(wc.localeFor(userID), "booking.holiday_closed", state.AppointmentDate, holiday.Name))
+	state.AppointmentDate = ""
+	state.Step = "await_date"
+	return true
+}
+
+// selectDoctor records the chosen doctor and offers their free slots
+func (wc *WhatsAppController) selectDoctor(userID string, state *AppointmentData, doctor Doctor) {
+	state.DoctorID = uint(doctor.ID)
+	state.DoctorName = doctor.DoctorName
+
+	// check slots
+	hasSlots, _ := wc.sendSlotsList(userID, state.DoctorID, state.AppointmentDate)
+	if hasSlots {
+		state.Step = "choose_slot"
+	} else {
+		// Offer the earliest slots in the department instead of starting over
+		wc.sendEarliestSlots(userID, state)
 	}
 }
 
@@ -451,6 +774,9 @@ func (wc *WhatsAppController) VerifyWebhook(c *gin.Context) {
 //     c.JSON(http.StatusForbidden, gin.H{"error": "Verification failed"})
 // }
 
+// How long handling one webhook call may take, HMS and AI calls included
+const webhookProcessingTimeout = 2 * time.Minute
+
 // HandleWebhook processes incoming WhatsApp messages
 func (wc *WhatsAppController) HandleWebhook(c *gin.Context) {
 	var webhookData models.WhatsAppWebhookData
@@ -461,11 +787,15 @@ func (wc *WhatsAppController) HandleWebhook(c *gin.Context) {
 		return
 	}
 
-	// Get context for processing
-	ctx := c.Request.Context()
+	// net/http cancels the request context once we respond, so processing
+	// gets its own
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), webhookProcessingTimeout)
 
 	// Process webhook asynchronously to respond quickly
-	go wc.processWebhookData(ctx, webhookData)
+	go func() {
+		defer cancel()
+		wc.processWebhookData(ctx, webhookData)
+	}()
 
 	// Respond immediately to WhatsApp
 	c.JSON(http.StatusOK, gin.H{"status": "received"})
@@ -551,11 +881,65 @@ func (wc *WhatsAppController) handleIncomingMessage(ctx context.Context, message
 	log.Println("Incoming message:", message.Type)
 	userID := message.From
 
+	intent := wc.recordInbound(ctx, userID, message)
+	wc.recordServiceWindow(userID, message)
+	wc.detectLocale(userID, message)
+
+	// ========== Consent keywords (STOP / START) ==========
+	if message.Type == "text" && message.Text != nil && wc.handleConsentKeyword(userID, message.Text.Body) {
+		return
+	}
+
+	// ========== Staff acknowledging an emergency alert ==========
+	if message.Type == "text" && message.Text != nil && wc.handleAlertAck(userID, message.Text.Body) {
+		return
+	}
+
+	// ========== Emergency ==========
+	// Names and addresses typed during booking are not triaged
+	if intent == models.IntentEmergency && message.Type == "text" && message.Text != nil && !wc.awaitingPatientDetails(userID) {
+		wc.handleEmergency(userID, message.Text.Body)
+		return
+	}
+
+	// ========== Request for reception staff ==========
+	if intent == models.IntentHandoff && message.Type == "text" && message.Text != nil && !wc.awaitingPatientDetails(userID) {
+		reply, _ := wc.chatbotService.RequestHandoff(ctx, services.WhatsAppSessionID(userID), wc.localeFor(userID))
+		_ = wc.whatsappService.SendTextMessage(userID, reply)
+		return
+	}
+
+	// ========== After-hours notice ==========
+	if notice, ok := wc.chatbotService.AfterHoursNotice(ctx, services.WhatsAppSessionID(userID), wc.localeFor(userID)); ok {
+		_ = wc.whatsappService.SendTextMessage(userID, notice)
+	}
+
+	// ========== Language selection ==========
+	if message.Type == "text" && message.Text != nil && i18n.IsLanguageRequest(message.Text.Body) {
+		_ = wc.sendLanguageMenu(userID)
+		return
+	}
+	if message.Interactive != nil && message.Interactive.ListReply != nil &&
+		strings.HasPrefix(message.Interactive.ListReply.ID, languageRowPrefix) {
+		wc.handleLanguageChoice(userID, strings.TrimPrefix(message.Interactive.ListReply.ID, languageRowPrefix))
+		return
+	}
+
+	// ========== Doctor directory ==========
+	if wc.handleDirectoryReply(ctx, userID, message) {
+		return
+	}
+
+	// ========== Waitlist offers ==========
+	if wc.handleWaitlistReply(ctx, userID, message) {
+		return
+	}
+
 	// ========== CASE 0: User says "hi" ==========
 	if message.Type == "text" && message.Text != nil {
-		userText := strings.TrimSpace(strings.ToLower(message.Text.Body))
-		if userText == "hi" || userText == "hello" {
+		if i18n.IsGreeting(message.Text.Body) {
 			delete(appointmentState, userID)
+			wc.releaseSlots(userID)
 			_ = wc.sendMainMenu(userID)
 			return
 		}
@@ -574,16 +958,16 @@ func (wc *WhatsAppController) handleIncomingMessage(ctx context.Context, message
 		stateMutex.Unlock()
 
 		if state == "awaiting_phone" {
-			phone := strings.TrimSpace(message.Text.Body)
-
-			if !isValidPhone(phone) {
+			number, err := wc.whatsappService.ParsePhone(message.Text.Body)
+			if err != nil {
 				_ = wc.whatsappService.SendTextMessage(userID, "❌ Invalid input. Please enter a valid phone number.")
 				_ = wc.sendMainMenu(userID)
 				delete(userState, userID)
 				return
 			}
 
-			appointments, err := wc.fetchAppointments(phone)
+			// HMS stores local numbers nationally, foreign ones in E.164
+			appointments, err := wc.fetchAppointments(number.Format(wc.whatsappService.Region()))
 			if err != nil {
 				log.Println("appointment fetching error", err)
 				_ = wc.whatsappService.SendTextMessage(userID, "⚠️ Sorry, could not fetch your appointments right now.")
@@ -624,7 +1008,7 @@ func (wc *WhatsAppController) handleIncomingMessage(ctx context.Context, message
 					return
 
 				case "contact_us":
-					_ = wc.whatsappService.SendTextMessage(userID, "📞 Contact us at: +91-98765-43210")
+					wc.sendContactDetails(ctx, userID)
 					_ = wc.sendMainMenu(userID)
 					return
 				}
@@ -642,33 +1026,516 @@ func (wc *WhatsAppController) handleIncomingMessage(ctx context.Context, message
 					return
 				}
 
-				if details != "" {
-					_ = wc.whatsappService.SendTextMessage(userID, details)
-				} else {
-					_ = wc.whatsappService.SendTextMessage(userID, "❓ Appointment not found.")
-				}
+				if details != "" {
+					_ = wc.whatsappService.SendTextMessage(userID, details)
+				} else {
+					_ = wc.whatsappService.SendTextMessage(userID, "❓ Appointment not found.")
+				}
+
+				_ = wc.sendMainMenu(userID)
+				return
+			}
+
+			// if message.Interactive.ListReply != nil {
+			// 	// You still extract the ID if you need it for logging, auditing, etc.
+			// 	// apptID := message.Interactive.ListReply.ID
+			// 	// _ = wc.getAppointmentDetails(apptID) // optional — remove if not needed
+
+			// 	// Just send the main menu
+			// 	_ = wc.sendMainMenu(userID)
+
+			// 	return
+			// }
+
+		}
+	}
+
+	// ========== "Add family member" ==========
+	if message.Type == "text" && message.Text != nil && i18n.IsAddFamilyRequest(message.Text.Body) {
+		state := &AppointmentData{RegisterOnly: true}
+		state.ForRelation, _ = i18n.FamilyRelation(message.Text.Body)
+		appointmentState[userID] = state
+		wc.startAddMember(userID, state)
+		return
+	}
+
+	// ========== "Our doctors" ==========
+	if intent != models.IntentAppointment && message.Type == "text" && message.Text != nil && i18n.IsDoctorsRequest(message.Text.Body) {
+		wc.sendDoctorDirectory(ctx, userID)
+		return
+	}
+
+	// ========== Free-text booking request ==========
+	if intent == models.IntentAppointment && message.Type == "text" && message.Text != nil {
+		wc.startBookingFromText(ctx, userID, message.Text.Body)
+		return
+	}
+
+	// ========== Clinic information (address, hours, "are you open?") ==========
+	if intent == models.IntentClinicInfo && message.Type == "text" && message.Text != nil {
+		_ = wc.whatsappService.SendTextMessage(userID, wc.chatbotService.AnswerClinicInfo(ctx, message.Text.Body, wc.localeFor(userID)))
+		_ = wc.sendMainMenu(userID)
+		return
+	}
+
+	// ========== CASE 3: Default fallback ==========
+	_ = wc.whatsappService.SendTextMessage(userID, i18n.T(wc.localeFor(userID), "menu.not_understood"))
+	_ = wc.sendMainMenu(userID)
+}
+
+// languageRowPrefix marks language menu rows, e.g. "lang_ml"
+const languageRowPrefix = "lang_"
+
+// Reply IDs of the doctor directory: department rows "dirdept_<id>", doctor
+// rows "dirdoc_<employee id>" and "Book with doctor" buttons "bookdoc_<employee id>"
+const (
+	directoryDepartmentPrefix = "dirdept_"
+	directoryDoctorPrefix     = "dirdoc_"
+	bookDoctorPrefix          = "bookdoc_"
+	ourDoctorsButton          = "our_doctors"
+)
+
+// handleDirectoryReply handles taps on doctor directory lists and cards. They
+// are answered even during a booking, as a card can be opened from old messages.
+func (wc *WhatsAppController) handleDirectoryReply(ctx context.Context, userID string, message models.WhatsAppMessage) bool {
+	if message.Interactive == nil {
+		return false
+	}
+
+	if reply := message.Interactive.ListReply; reply != nil {
+		switch {
+		case strings.HasPrefix(reply.ID, directoryDepartmentPrefix):
+			id, _ := strconv.Atoi(strings.TrimPrefix(reply.ID, directoryDepartmentPrefix))
+			wc.sendDirectoryDoctors(ctx, userID, id, reply.Title)
+			return true
+		case strings.HasPrefix(reply.ID, directoryDoctorPrefix):
+			id, _ := strconv.Atoi(strings.TrimPrefix(reply.ID, directoryDoctorPrefix))
+			wc.sendDoctorCard(ctx, userID, id)
+			return true
+		}
+	}
+
+	if reply := message.Interactive.ButtonReply; reply != nil {
+		switch {
+		case reply.ID == ourDoctorsButton:
+			wc.sendDoctorDirectory(ctx, userID)
+			return true
+		case strings.HasPrefix(reply.ID, bookDoctorPrefix):
+			id, _ := strconv.Atoi(strings.TrimPrefix(reply.ID, bookDoctorPrefix))
+			wc.bookWithDoctor(ctx, userID, id)
+			return true
+		}
+	}
+	return false
+}
+
+// sendDoctorDirectory lists the departments with doctors in the directory,
+// or the doctors directly when there is only one department
+func (wc *WhatsAppController) sendDoctorDirectory(ctx context.Context, userID string) {
+	locale := wc.localeFor(userID)
+	departments, err := wc.doctorService.Departments(ctx)
+	if err != nil {
+		log.Println("doctor directory error:", err)
+	}
+	if len(departments) == 0 {
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "doctors.empty"))
+		_ = wc.sendMainMenu(userID)
+		return
+	}
+	if len(departments) == 1 {
+		wc.sendDirectoryDoctors(ctx, userID, departments[0].ID, departments[0].Name)
+		return
+	}
+
+	// WhatsApp lists hold at most 10 rows
+	if len(departments) > 10 {
+		departments = departments[:10]
+	}
+	rows := make([]models.ListItem, 0, len(departments))
+	for _, d := range departments {
+		rows = append(rows, models.ListItem{
+			ID:    directoryDepartmentPrefix + strconv.Itoa(d.ID),
+			Title: truncate(d.Name, 24),
+		})
+	}
+
+	interactive := &models.InteractiveMessage{
+		Type: "list",
+		Body: &models.InteractiveBody{Text: i18n.T(locale, "doctors.body_departments")},
+		Action: &models.InteractiveAction{
+			Button:   i18n.T(locale, "doctors.department_button"),
+			Sections: []models.Section{{Rows: rows}},
+		},
+	}
+	_ = wc.whatsappService.SendInteractiveMessage(userID, interactive)
+}
+
+// sendDirectoryDoctors lists the doctors of a department in the directory
+func (wc *WhatsAppController) sendDirectoryDoctors(ctx context.Context, userID string, departmentID int, departmentName string) {
+	locale := wc.localeFor(userID)
+	doctors, err := wc.doctorService.List(ctx, departmentID, false)
+	if err != nil {
+		log.Println("doctor directory error:", err)
+	}
+	if len(doctors) == 0 {
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "doctors.empty"))
+		_ = wc.sendMainMenu(userID)
+		return
+	}
+
+	if len(doctors) > 10 {
+		doctors = doctors[:10]
+	}
+	rows := make([]models.ListItem, 0, len(doctors))
+	for _, d := range doctors {
+		rows = append(rows, models.ListItem{
+			ID:          directoryDoctorPrefix + strconv.Itoa(d.EmployeeID),
+			Title:       truncate(d.Name, 24),
+			Description: truncate(strings.Join(d.Qualifications, ", "), 72),
+		})
+	}
+
+	interactive := &models.InteractiveMessage{
+		Type: "list",
+		Body: &models.InteractiveBody{Text: i18n.T(locale, "doctors.body_department", departmentName)},
+		Action: &models.InteractiveAction{
+			Button:   i18n.T(locale, "doctors.doctor_button"),
+			Sections: []models.Section{{Rows: rows}},
+		},
+	}
+	_ = wc.whatsappService.SendInteractiveMessage(userID, interactive)
+}
+
+// sendDoctorCard sends a doctor's profile with their photo as the header and
+// a button to book with them
+func (wc *WhatsAppController) sendDoctorCard(ctx context.Context, userID string, employeeID int) {
+	locale := wc.localeFor(userID)
+	doctor, err := wc.doctorService.Get(ctx, employeeID)
+	if err != nil || doctor.Hidden {
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "doctors.not_found"))
+		wc.sendDoctorDirectory(ctx, userID)
+		return
+	}
+
+	body := services.DoctorCardText(*doctor, locale)
+	header := &models.MessageHeader{Type: "text", Text: truncate(doctor.Name, 60)}
+	if doctor.PhotoURL != "" {
+		// Image headers carry no text, so the name opens the body instead
+		header = &models.MessageHeader{Type: "image", Media: &models.Media{Link: doctor.PhotoURL}}
+		body = "*" + doctor.Name + "*\n" + body
+	}
+
+	interactive := &models.InteractiveMessage{
+		Type:   "button",
+		Header: header,
+		Body:   &models.InteractiveBody{Text: truncate(body, 1024)},
+		Action: &models.InteractiveAction{
+			Buttons: []models.InteractiveButton{
+				{
+					Type: "reply",
+					Reply: &models.ButtonReply{
+						ID:    bookDoctorPrefix + strconv.Itoa(doctor.EmployeeID),
+						Title: i18n.T(locale, "doctors.book"),
+					},
+				},
+				{
+					Type: "reply",
+					Reply: &models.ButtonReply{
+						ID:    ourDoctorsButton,
+						Title: i18n.T(locale, "doctors.back"),
+					},
+				},
+			},
+		},
+	}
+	_ = wc.whatsappService.SendInteractiveMessage(userID, interactive)
+}
+
+// bookWithDoctor starts a booking with the department and doctor of a
+// profile card, asking for the date first
+func (wc *WhatsAppController) bookWithDoctor(ctx context.Context, userID string, employeeID int) {
+	locale := wc.localeFor(userID)
+	doctor, err := wc.doctorService.Get(ctx, employeeID)
+	// A card sent before the doctor was hidden must not book them
+	if err != nil || doctor.Hidden {
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "doctors.not_found"))
+		_ = wc.sendMainMenu(userID)
+		return
+	}
+
+	state := &AppointmentData{
+		DepartmentID:      uint(doctor.DepartmentID),
+		PreferredDoctor:   strings.TrimPrefix(strings.TrimPrefix(doctor.Name, "Dr."), " "),
+		PreferredDoctorID: doctor.EmployeeID,
+	}
+	appointmentState[userID] = state
+	wc.continueBooking(userID, state)
+}
+
+// sendContactDetails sends the phone numbers and address from the clinic profile
+func (wc *WhatsAppController) sendContactDetails(ctx context.Context, userID string) {
+	locale := wc.localeFor(userID)
+	clinic := wc.chatbotService.ClinicProfile(ctx)
+	text := i18n.T(locale, "chat.clinic_phone", services.FormatClinicPhones(clinic.Phones)) +
+		i18n.T(locale, "chat.clinic_address", services.FormatClinicAddresses(clinic.Addresses))
+	_ = wc.whatsappService.SendTextMessage(userID, strings.TrimSpace(text))
+}
+
+// localeFor returns the conversation language of a user, loading it from the
+// session on first use
+func (wc *WhatsAppController) localeFor(userID string) i18n.Locale {
+	return wc.localePreference(userID).Locale
+}
+
+func (wc *WhatsAppController) localePreference(userID string) localePreference {
+	stateMutex.Lock()
+	pref, ok := userLocale[userID]
+	stateMutex.Unlock()
+	if ok {
+		return pref
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pref = localePreference{Locale: i18n.Default}
+	stored, explicit, err := wc.inboxService.GetLocale(ctx, services.WhatsAppSessionID(userID))
+	if err != nil {
+		log.Println("locale lookup error", err)
+		return pref
+	}
+	if locale, ok := i18n.Parse(stored); ok {
+		pref = localePreference{Locale: locale, Explicit: explicit}
+	}
+
+	stateMutex.Lock()
+	userLocale[userID] = pref
+	stateMutex.Unlock()
+	return pref
+}
+
+// setLocale stores the conversation language on the session
+func (wc *WhatsAppController) setLocale(userID string, locale i18n.Locale, explicit bool) {
+	stateMutex.Lock()
+	userLocale[userID] = localePreference{Locale: locale, Explicit: explicit}
+	stateMutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := wc.inboxService.SetLocale(ctx, services.WhatsAppSessionID(userID), string(locale), explicit); err != nil {
+		log.Println("locale update error", err)
+	}
+}
+
+// detectLocale switches the conversation language to the script the user
+// writes in, unless they picked a language from the menu
+func (wc *WhatsAppController) detectLocale(userID string, message models.WhatsAppMessage) {
+	if message.Text == nil {
+		return
+	}
+	pref := wc.localePreference(userID)
+	if pref.Explicit {
+		return
+	}
+	if locale, ok := i18n.Detect(message.Text.Body); ok && locale != pref.Locale {
+		wc.setLocale(userID, locale, false)
+	}
+}
+
+// sendLanguageMenu lists the supported languages in their own script
+func (wc *WhatsAppController) sendLanguageMenu(to string) error {
+	locale := wc.localeFor(to)
+
+	rows := make([]models.ListItem, 0, len(i18n.Supported))
+	for _, l := range i18n.Supported {
+		rows = append(rows, models.ListItem{
+			ID:          languageRowPrefix + string(l),
+			Title:       l.Name(),
+			Description: l.EnglishName(),
+		})
+	}
+
+	interactive := &models.InteractiveMessage{
+		Type: "list",
+		Body: &models.InteractiveBody{Text: i18n.T(locale, "language.prompt")},
+		Action: &models.InteractiveAction{
+			Button: i18n.T(locale, "language.button"),
+			Sections: []models.Section{
+				{Title: i18n.T(locale, "language.section"), Rows: rows},
+			},
+		},
+	}
+	return wc.whatsappService.SendInteractiveMessage(to, interactive)
+}
+
+// handleLanguageChoice applies a language picked from the language menu
+func (wc *WhatsAppController) handleLanguageChoice(userID, code string) {
+	locale, ok := i18n.Parse(code)
+	if !ok {
+		_ = wc.sendLanguageMenu(userID)
+		return
+	}
+
+	wc.setLocale(userID, locale, true)
+	_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "language.changed"))
+	_ = wc.sendMainMenu(userID)
+}
+
+// recordInbound tracks an incoming WhatsApp message in the staff inbox and
+// returns its classified intent, which the reply is also chosen by. Answers to
+// booking prompts are classified by keywords only, so they never wait on the
+// AI model.
+func (wc *WhatsAppController) recordInbound(ctx context.Context, userID string, message models.WhatsAppMessage) models.MessageIntent {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	var text string
+	var intent models.MessageIntent
+
+	switch {
+	case message.Text != nil:
+		text = message.Text.Body
+		if wc.answeringPrompt(userID) {
+			intent = wc.chatbotService.ClassifyReply(text)
+		} else {
+			intent = wc.chatbotService.ClassifyIntent(ctx, text)
+		}
+	case message.Interactive != nil && message.Interactive.ListReply != nil:
+		text = message.Interactive.ListReply.Title
+	case message.Interactive != nil && message.Interactive.ButtonReply != nil:
+		text = message.Interactive.ButtonReply.Title
+		if message.Interactive.ButtonReply.ID == "new_appointment" {
+			intent = models.IntentAppointment
+		}
+	default:
+		text = fmt.Sprintf("[%s]", message.Type)
+	}
+
+	if err := wc.inboxService.RecordInbound(ctx, models.ChannelWhatsApp, services.WhatsAppSessionID(userID), userID, text, intent); err != nil {
+		log.Println("inbox recording error", err)
+	}
+	return intent
+}
+
+// recordServiceWindow stores the inbound timestamp that opens the 24-hour
+// customer service window for this contact
+func (wc *WhatsAppController) recordServiceWindow(userID string, message models.WhatsAppMessage) {
+	receivedAt := time.Now()
+	if ts, err := strconv.ParseInt(message.Timestamp, 10, 64); err == nil {
+		receivedAt = time.Unix(ts, 0)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	phone := wc.whatsappService.CleanPhoneNumber(userID)
+	if err := wc.contactService.RecordInbound(ctx, phone, receivedAt); err != nil {
+		log.Println("service window recording error", err)
+		return
+	}
+	if err := wc.contactService.RecordImplicitOptIn(ctx, phone); err != nil {
+		log.Println("opt-in recording error", err)
+	}
+}
+
+// awaitingPatientDetails reports whether the user is typing a patient's name
+// or address for a booking or a new family member
+func (wc *WhatsAppController) awaitingPatientDetails(userID string) bool {
+	state, exists := appointmentState[userID]
+	if !exists {
+		return false
+	}
+	switch state.Step {
+	case "await_patient_name", "await_patient_address", "await_member_name", "await_member_address":
+		return true
+	}
+	return false
+}
+
+// answeringPrompt reports whether the user is mid-way through a booking or
+// was asked for a phone number, so their next text answers a prompt
+func (wc *WhatsAppController) answeringPrompt(userID string) bool {
+	if _, exists := appointmentState[userID]; exists {
+		return true
+	}
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+	return userState[userID] == "awaiting_phone"
+}
+
+// handleEmergency alerts on-call staff and tells the patient who to call
+func (wc *WhatsAppController) handleEmergency(userID, text string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := wc.emergencyService.Raise(ctx, models.ChannelWhatsApp, services.WhatsAppSessionID(userID), userID, text); err != nil {
+		log.Println("emergency alert error", err)
+	}
+	_ = wc.whatsappService.SendTextMessage(userID, i18n.T(wc.localeFor(userID), "chat.emergency",
+		wc.emergencyService.PublicNumber(), wc.emergencyService.ClinicLine()))
+}
 
-				_ = wc.sendMainMenu(userID)
-				return
-			}
+// handleAlertAck processes "ACK <code>" from on-call staff.
+// Returns true if the message was an acknowledgement.
+func (wc *WhatsAppController) handleAlertAck(userID, text string) bool {
+	fields := strings.Fields(text)
+	if len(fields) != 2 || !strings.EqualFold(fields[0], "ACK") || !wc.emergencyService.IsStaff(userID) {
+		return false
+	}
 
-			// if message.Interactive.ListReply != nil {
-			// 	// You still extract the ID if you need it for logging, auditing, etc.
-			// 	// apptID := message.Interactive.ListReply.ID
-			// 	// _ = wc.getAppointmentDetails(apptID) // optional — remove if not needed
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-			// 	// Just send the main menu
-			// 	_ = wc.sendMainMenu(userID)
+	alert, err := wc.emergencyService.AcknowledgeByCode(ctx, fields[1], userID)
+	switch {
+	case errors.Is(err, services.ErrAlertNotFound):
+		_ = wc.whatsappService.SendTextMessage(userID, "❓ No emergency alert with code "+fields[1]+".")
+	case errors.Is(err, services.ErrAlertAcknowledged):
+		_ = wc.whatsappService.SendTextMessage(userID, "ℹ️ Alert "+fields[1]+" was already acknowledged.")
+	case err != nil:
+		log.Println("alert acknowledgement error", err)
+		_ = wc.whatsappService.SendTextMessage(userID, "⚠️ Could not acknowledge the alert. Please try again.")
+	default:
+		_ = wc.whatsappService.SendTextMessage(userID,
+			fmt.Sprintf("✅ Alert %s acknowledged. Patient: %s", alert.Code, alert.Contact))
+	}
+	return true
+}
 
-			// 	return
-			// }
+// handleConsentKeyword processes STOP/UNSUBSCRIBE/START messages.
+// Returns true if the message was a consent keyword.
+func (wc *WhatsAppController) handleConsentKeyword(userID, text string) bool {
+	keyword := strings.ToUpper(strings.TrimSpace(text))
 
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	phone := wc.whatsappService.CleanPhoneNumber(userID)
+
+	switch keyword {
+	case "STOP", "UNSUBSCRIBE":
+		if _, err := wc.contactService.OptOut(ctx, phone, models.OptInSourceKeyword); err != nil {
+			log.Println("opt-out error", err)
+			_ = wc.whatsappService.SendTextMessage(userID, "⚠️ Sorry, we could not update your preferences. Please try again.")
+			return true
 		}
+		wc.leaveWaitlists(ctx, userID)
+		_ = wc.whatsappService.SendTextMessage(userID,
+			"✅ You have been unsubscribed from clinic announcements and reminders. You can still message us anytime.\n\nReply START to subscribe again.")
+		return true
+
+	case "START", "SUBSCRIBE":
+		if _, err := wc.contactService.OptIn(ctx, phone, models.OptInSourceKeyword); err != nil {
+			log.Println("opt-in error", err)
+			_ = wc.whatsappService.SendTextMessage(userID, "⚠️ Sorry, we could not update your preferences. Please try again.")
+			return true
+		}
+		_ = wc.whatsappService.SendTextMessage(userID,
+			"✅ You are subscribed to clinic announcements and reminders.\n\nReply STOP to unsubscribe.")
+		return true
 	}
 
-	// ========== CASE 3: Default fallback ==========
-	_ = wc.whatsappService.SendTextMessage(userID, "🤔 Sorry, I didn’t understand that.")
-	_ = wc.sendMainMenu(userID)
+	return false
 }
 
 // ========================
@@ -727,6 +1594,25 @@ type apiPatientResponse struct {
 	} `json:"data"`
 }
 
+// newPatientRequest registers a patient in HMS
+type newPatientRequest struct {
+	FirstName    string `json:"firstName"`
+	LastName     string `json:"lastName"`
+	DateOfBirth  string `json:"dateOfBirth"`
+	MobileNumber string `json:"mobileNumber"`
+	Address      string `json:"address"`
+}
+
+type apiPatientCreateResponse struct {
+	Status     bool   `json:"status"`
+	StatusCode int    `json:"statusCode"`
+	Message    string `json:"message"`
+	Data       struct {
+		PatientID   int    `json:"patientId"`
+		PatientCode string `json:"patientCode"`
+	} `json:"data"`
+}
+
 type Department struct {
 	ID             int    `json:"departmentId"`
 	DepartmentName string `json:"departmentName"`
@@ -782,6 +1668,8 @@ type apiDoctorAvailabilityResponse struct {
 		AvailabilityID     int             `json:"availabilityId"`
 		AvailableTimeStart string          `json:"availableTimeStart"`
 		AvailableTimeEnd   string          `json:"availableTimeEnd"`
+		SlotDuration       int             `json:"slotDuration"` // minutes; 0 uses clinic config
+		BufferTime         int             `json:"bufferTime"`   // minutes between slots
 		BookedSlots        []BookedSlotDTO `json:"bookedSlots"`
 	} `json:"data"`
 }
@@ -796,7 +1684,7 @@ type apiAppointmentResponse struct {
 }
 
 type SlotState struct {
-	Slots    []string
+	Slots    []SlotOption
 	Page     int
 	PageSize int
 }
@@ -810,86 +1698,24 @@ func truncate(str string, max int) string {
 	return str[:max-1] + "…" // add ellipsis
 }
 
-func generateTimeSlots(start, end string, apptDateStr string) ([]string, error) {
-	layout := "15:04:05"
-
-	log.Println("=== GENERATING TIME SLOTS ===")
-	log.Println("Input Start:", start)
-	log.Println("Input End:", end)
-	log.Println("Appointment Date:", apptDateStr)
-
-	// Parse appointment date
-	apptDate, err := time.Parse("2006-01-02", apptDateStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid appointment date: %w", err)
-	}
-
-	// Parse raw times
-	tStartRaw, err := time.Parse(layout, start)
-	if err != nil {
-		return nil, err
-	}
-	tEndRaw, err := time.Parse(layout, end)
-	if err != nil {
-		return nil, err
-	}
-
-	loc, _ := time.LoadLocation("Asia/Kolkata")
-	now := time.Now().In(loc)
-
-	log.Println("Current Time:", now)
-
-	// Build slot times using appointment date
-	tStart := time.Date(
-		apptDate.Year(), apptDate.Month(), apptDate.Day(),
-		tStartRaw.Hour(), tStartRaw.Minute(), tStartRaw.Second(),
-		0, now.Location(),
-	)
-
-	tEnd := time.Date(
-		apptDate.Year(), apptDate.Month(), apptDate.Day(),
-		tEndRaw.Hour(), tEndRaw.Minute(), tEndRaw.Second(),
-		0, now.Location(),
-	)
-
-	log.Println("Start with appointment date:", tStart)
-	log.Println("End with appointment date:", tEnd)
-
-	slots := []string{}
-
-	// CASE 1: Appointment date is NOT today → return full slots
-	if apptDate.Format("2006-01-02") != now.Format("2006-01-02") {
-		log.Println("Appointment is NOT today → Return all slots")
-
-		for t := tStart; t.Before(tEnd); t = t.Add(15 * time.Minute) {
-			slots = append(slots, t.Format("15:04"))
-		}
-
-		return slots, nil
-	}
-
-	// CASE 2: Appointment **is today** → filter using current time
-	log.Println("Appointment is today → Filtering past time slots")
-
-	rounded := now.Truncate(15 * time.Minute)
-	if rounded.Before(now) {
-		rounded = rounded.Add(15 * time.Minute)
-	}
-	log.Println("Next allowed slot:", rounded)
-
-	for t := tStart; t.Before(tEnd); t = t.Add(15 * time.Minute) {
-		log.Println("Checking slot:", t.Format("15:04"))
-
-		if t.Before(rounded) {
-			log.Println("⛔ Skip:", t.Format("15:04"))
+// newSlotGenerator builds slot generation from clinic config, in the clinic's
+// time zone; HMS slot lengths and buffers override it per doctor session
+func newSlotGenerator(cfg config.BookingConfig) utils.SlotGenerator {
+	breaks := []utils.ClockRange{}
+	for _, b := range cfg.SlotBreaks {
+		r, err := utils.ParseClockRange(b)
+		if err != nil {
+			log.Println("ignoring SLOT_BREAKS entry:", err)
 			continue
 		}
-
-		log.Println("✅ Add:", t.Format("15:04"))
-		slots = append(slots, t.Format("15:04"))
+		breaks = append(breaks, r)
+	}
+	return utils.SlotGenerator{
+		Duration: cfg.SlotDuration,
+		Buffer:   cfg.SlotBuffer,
+		Breaks:   breaks,
+		Location: services.ClinicLocation(),
 	}
-
-	return slots, nil
 }
 
 
@@ -1092,10 +1918,10 @@ func (wc *WhatsAppController) verifyPatientCode(code string) ([]Patient, error)
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	url := fmt.Sprintf("http://61.2.142.81:8086/api/patient/search?userInput=%s", code)
+	endpoint := "http://61.2.142.81:8086/api/patient/search?userInput=" + url.QueryEscape(code)
 
 	var apiResp apiPatientResponse
-	if err := callExternalAPI(ctx, url, &apiResp); err != nil {
+	if err := callExternalAPI(ctx, endpoint, &apiResp); err != nil {
 		log.Println("API fetching error", err)
 		return nil, err
 	}
@@ -1130,7 +1956,34 @@ func (wc *WhatsAppController) verifyPatientCode(code string) ([]Patient, error)
 	// return false, AppointmentData{}
 }
 
+// registerPatient creates a patient in HMS and returns it with its ID and code
+func (wc *WhatsAppController) registerPatient(ctx context.Context, req newPatientRequest) (Patient, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	url := "http://61.2.142.81:8086/api/patient/create"
+
+	var resp apiPatientCreateResponse
+	if err := callExternalAPICallForPost(ctx, http.MethodPost, url, req, &resp); err != nil {
+		return Patient{}, err
+	}
+	if !resp.Status || resp.Data.PatientID == 0 {
+		return Patient{}, fmt.Errorf("patient not created: %s", resp.Message)
+	}
+
+	return Patient{
+		ID:           resp.Data.PatientID,
+		PatientCode:  resp.Data.PatientCode,
+		FirstName:    req.FirstName,
+		LastName:     req.LastName,
+		DateOfBirth:  req.DateOfBirth,
+		MobileNumber: req.MobileNumber,
+		Address:      req.Address,
+	}, nil
+}
+
 func (wc *WhatsAppController) sendPatientDetailsList(to string, patients []Patient) error {
+	locale := wc.localeFor(to)
 	rows := make([]models.ListItem, 0, len(patients))
 
 	for _, appt := range patients {
@@ -1139,13 +1992,13 @@ func (wc *WhatsAppController) sendPatientDetailsList(to string, patients []Patie
 		rows = append(rows, models.ListItem{
 			ID:          strconv.Itoa(appt.ID),
 			Title:       truncate(fullName, 24), // short for list
-			Description: truncate(fmt.Sprintf("Patient Code: %s", appt.PatientCode), 72),
+			Description: truncate(i18n.T(locale, "booking.patient_code", appt.PatientCode), 72),
 		})
 	}
 
 	sections := []models.Section{
 		{
-			Title: "Search Results",
+			Title: i18n.T(locale, "booking.patients_section"),
 			Rows:  rows,
 		},
 	}
@@ -1154,16 +2007,16 @@ func (wc *WhatsAppController) sendPatientDetailsList(to string, patients []Patie
 		Type: "list",
 		Header: &models.MessageHeader{
 			Type: "text",
-			Text: "📅 Patient Details",
+			Text: i18n.T(locale, "booking.patients_header"),
 		},
 		Body: &models.InteractiveBody{
-			Text: "Choose one patient for appointment:",
+			Text: i18n.T(locale, "booking.patients_body"),
 		},
 		Footer: &models.InteractiveFooter{
-			Text: "Clinic Support",
+			Text: i18n.T(locale, "menu.footer"),
 		},
 		Action: &models.InteractiveAction{
-			Button:   "Choose Patient",
+			Button:   i18n.T(locale, "booking.patients_button"),
 			Sections: sections,
 		},
 	}
@@ -1171,8 +2024,8 @@ func (wc *WhatsAppController) sendPatientDetailsList(to string, patients []Patie
 	return wc.whatsappService.SendInteractiveMessage(to, interactive)
 }
 
-func (wc *WhatsAppController) sendDepartmentsList(userID string) error {
-
+// fetchDepartments loads the department list from HMS
+func (wc *WhatsAppController) fetchDepartments() ([]Department, error) {
 	// 🔹 Force a fresh background context, safe timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
@@ -1181,8 +2034,7 @@ func (wc *WhatsAppController) sendDepartmentsList(userID string) error {
 
 	var apiResp apiDepartmentResponse
 	if err := callExternalAPI(ctx, url, &apiResp); err != nil {
-		log.Println("API fetching error", err)
-		return err
+		return nil, err
 	}
 
 	// Convert to your model
@@ -1194,6 +2046,98 @@ func (wc *WhatsAppController) sendDepartmentsList(userID string) error {
 			DepartmentName: d.DepartmentName,
 		}
 	}
+	return departments, nil
+}
+
+// findDepartment matches an extracted department name ("Pediatrics")
+// against the HMS departments, ignoring case and British spellings, and
+// returns the closest one. Names match on whole words, or on words of at
+// least five letters that start one another ("Paediatric", "Pediatrics").
+func (wc *WhatsAppController) findDepartment(name string) (Department, bool) {
+	departments, err := wc.fetchDepartments()
+	if err != nil {
+		log.Println("API fetching error", err)
+		return Department{}, false
+	}
+
+	want := departmentWords(name)
+	if len(want) == 0 {
+		return Department{}, false
+	}
+	var best Department
+	bestScore, bestExtra := 0, 0
+	for _, dept := range departments {
+		have := departmentWords(dept.DepartmentName)
+		score := departmentMatch(want, have)
+		extra := len(have) - len(want)
+		if extra < 0 {
+			extra = -extra
+		}
+		if score > bestScore || (score == bestScore && score > 0 && extra < bestExtra) {
+			best, bestScore, bestExtra = dept, score, extra
+		}
+	}
+	return best, bestScore > 0
+}
+
+// departmentWords splits a department name into lower-case words with
+// British spellings folded ("Paediatrics" -> "pediatrics")
+func departmentWords(name string) []string {
+	name = strings.ReplaceAll(strings.ToLower(name), "ae", "e")
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// departmentMatch scores two department names: 3 for the same words, 2 when
+// every word of one is a word of the other, 1 when every word of one starts
+// a word of the other, 0 for no match
+func departmentMatch(want, have []string) int {
+	if strings.Join(want, " ") == strings.Join(have, " ") {
+		return 3
+	}
+	sameWord := func(a, b string) bool { return a == b }
+	if wordsWithin(want, have, sameWord) || wordsWithin(have, want, sameWord) {
+		return 2
+	}
+	if wordsWithin(want, have, sharesPrefix) || wordsWithin(have, want, sharesPrefix) {
+		return 1
+	}
+	return 0
+}
+
+// wordsWithin reports whether every word of a matches some word of b
+func wordsWithin(a, b []string, match func(x, y string) bool) bool {
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if match(x, y) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return len(a) > 0
+}
+
+// sharesPrefix reports whether one word starts the other and the shorter
+// is long enough not to match by chance
+func sharesPrefix(x, y string) bool {
+	if len(x) > len(y) {
+		x, y = y, x
+	}
+	return len(x) >= 5 && strings.HasPrefix(y, x)
+}
+
+func (wc *WhatsAppController) sendDepartmentsList(userID string) error {
+	departments, err := wc.fetchDepartments()
+	if err != nil {
+		log.Println("API fetching error", err)
+		return err
+	}
 
 	b, _ := json.MarshalIndent(departments, "", "  ")
 	log.Println("departments", string(b))
@@ -1208,20 +2152,22 @@ func (wc *WhatsAppController) sendDepartmentsList(userID string) error {
 		})
 	}
 
+	locale := wc.localeFor(userID)
 	interactive := &models.InteractiveMessage{
 		Type: "list",
-		Body: &models.InteractiveBody{Text: "Please select a department"},
+		Body: &models.InteractiveBody{Text: i18n.T(locale, "booking.department_body")},
 		Action: &models.InteractiveAction{
-			Button: "Choose",
+			Button: i18n.T(locale, "booking.department_button"),
 			Sections: []models.Section{
-				{Title: "Departments", Rows: rows},
+				{Title: i18n.T(locale, "booking.department_section"), Rows: rows},
 			},
 		},
 	}
 	return wc.whatsappService.SendInteractiveMessage(userID, interactive)
 }
 
-func (wc *WhatsAppController) sendDoctorsList(userID string, dept uint, date string) error {
+// fetchDoctors loads the doctors of a department who are not on leave on the date
+func (wc *WhatsAppController) fetchDoctors(dept uint, date string) ([]Doctor, error) {
 	// 🔹 Context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
@@ -1238,8 +2184,7 @@ func (wc *WhatsAppController) sendDoctorsList(userID string, dept uint, date str
 
 	var apiResp apiDoctorResponse
 	if err := callExternalAPI(ctx, url, &apiResp); err != nil {
-		log.Println("API fetching error", err)
-		return err
+		return nil, err
 	}
 
 	// Convert to your model
@@ -1261,19 +2206,72 @@ func (wc *WhatsAppController) sendDoctorsList(userID string, dept uint, date str
 			})
 		}
 	}
+	return doctors, nil
+}
+
+// doctorByID finds a doctor by HMS employee ID
+func doctorByID(doctors []Doctor, id int) (Doctor, bool) {
+	for _, doctor := range doctors {
+		if id != 0 && doctor.ID == id {
+			return doctor, true
+		}
+	}
+	return Doctor{}, false
+}
+
+// matchDoctor finds a doctor by a name the patient typed, e.g. "Rao"
+func matchDoctor(doctors []Doctor, name string) (Doctor, bool) {
+	want := strings.Fields(strings.ToLower(name))
+	if len(want) == 0 {
+		return Doctor{}, false
+	}
+
+	for _, doctor := range doctors {
+		have := strings.Fields(strings.ToLower(strings.TrimPrefix(doctor.DoctorName, "Dr.")))
+		matched := 0
+		for _, w := range want {
+			for _, h := range have {
+				if w == h {
+					matched++
+					break
+				}
+			}
+		}
+		if matched == len(want) {
+			return doctor, true
+		}
+	}
+	return Doctor{}, false
+}
+
+// firstAvailableRow is the doctor list row asking for the earliest slot with any doctor
+const firstAvailableRow = "first_available"
+
+func (wc *WhatsAppController) sendDoctorsList(userID string, dept uint, date string) error {
+	doctors, err := wc.fetchDoctors(dept, date)
+	if err != nil {
+		log.Println("API fetching error", err)
+		return err
+	}
 
 	b, _ := json.MarshalIndent(doctors, "", "  ")
 	log.Println("doctors", string(b))
 
+	locale := wc.localeFor(userID)
 	if len(doctors) == 0 {
-		_ = wc.whatsappService.SendTextMessage(userID, "❌ No doctors available in this department on the selected date.")
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.no_doctors"))
 		delete(appointmentState, userID)
 		_ = wc.sendMainMenu(userID)
 		return nil
 	}
 
-	// Build WhatsApp list items
-	rows := make([]models.ListItem, 0, len(doctors))
+	// Build WhatsApp list items, starting with the earliest slot with any doctor
+	rows := make([]models.ListItem, 0, len(doctors)+1)
+	rows = append(rows, models.ListItem{
+		ID:          firstAvailableRow,
+		Title:       i18n.T(locale, "booking.first_available"),
+		Description: i18n.T(locale, "booking.first_available_desc"),
+	})
 	for _, doctor := range doctors {
 		rows = append(rows, models.ListItem{
 			ID:    strconv.Itoa(doctor.ID), // unique identifier for callback
@@ -1286,18 +2284,18 @@ func (wc *WhatsAppController) sendDoctorsList(userID string, dept uint, date str
 		Type: "list",
 		Header: &models.MessageHeader{
 			Type: "text",
-			Text: "👨‍⚕️ Available Doctors",
+			Text: i18n.T(locale, "booking.doctors_header"),
 		},
 		Body: &models.InteractiveBody{
-			Text: "Please select a doctor from the list below:",
+			Text: i18n.T(locale, "booking.doctors_body"),
 		},
 		Footer: &models.InteractiveFooter{
-			Text: "Clinic Support",
+			Text: i18n.T(locale, "menu.footer"),
 		},
 		Action: &models.InteractiveAction{
-			Button: "Choose Doctor",
+			Button: i18n.T(locale, "booking.doctors_button"),
 			Sections: []models.Section{
-				{Title: "Doctors", Rows: rows},
+				{Title: i18n.T(locale, "booking.doctors_section"), Rows: rows},
 			},
 		},
 	}
@@ -1368,47 +2366,23 @@ func (wc *WhatsAppController) sendSlotsList(userID string, doctor uint, date str
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	url := fmt.Sprintf(
-		"http://61.2.142.81:8086/api/doctorAvailability/byDate?doctorId=%d&inputDate=%s",
-		doctor, date,
-	)
-
-	var apiResp apiDoctorAvailabilityResponse
-	if err := callExternalAPI(ctx, url, &apiResp); err != nil {
+	allSlots, err := wc.fetchFreeSlots(ctx, doctor, date)
+	if err != nil {
 		log.Println("API fetching error", err)
 		return false, err
 	}
+	allSlots = wc.withoutHeldSlots(ctx, userID, date, allSlots)
 
-	// Collect all FREE slots
-	allSlots := []string{}
-	for _, avail := range apiResp.Data {
-		slots, err := generateTimeSlots(avail.AvailableTimeStart, avail.AvailableTimeEnd, date)
-		if err != nil {
-			log.Println("Error generating slots:", err)
-			continue
-		}
-
-		booked := make(map[string]bool)
-		for _, b := range avail.BookedSlots {
-			booked[b.TimeSlot] = true
-		}
-
-		for _, s := range slots {
-			t, err := time.Parse("15:04", s)
-			if err != nil {
-				continue
-			}
-			formatted := t.Format("03:04 PM")
-			if !booked[formatted] {
-				allSlots = append(allSlots, formatted)
-			}
+	// Narrow to the time window asked for ("evening"), unless nothing is left
+	if state, ok := appointmentState[userID]; ok && state.TimeWindow != "" {
+		if inWindow := filterSlotsByWindow(allSlots, state.TimeWindow); len(inWindow) > 0 {
+			allSlots = inWindow
 		}
 	}
 
 	if len(allSlots) == 0 {
-		_ = wc.whatsappService.SendTextMessage(userID, "❌ No available slots found for this doctor.")
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(wc.localeFor(userID), "booking.no_slots"))
 		delete(slotState, userID)
-		_ = wc.sendMainMenu(userID)
 		return false, nil // ❌ no slots
 	}
 
@@ -1427,10 +2401,71 @@ func (wc *WhatsAppController) sendSlotsList(userID string, doctor uint, date str
 	return true, nil // ✅ slots available
 }
 
+// fetchFreeSlots returns a doctor's unbooked slots on a date
+func (wc *WhatsAppController) fetchFreeSlots(ctx context.Context, doctor uint, date string) ([]SlotOption, error) {
+	url := fmt.Sprintf(
+		"http://61.2.142.81:8086/api/doctorAvailability/byDate?doctorId=%d&inputDate=%s",
+		doctor, date,
+	)
+
+	var apiResp apiDoctorAvailabilityResponse
+	if err := callExternalAPI(ctx, url, &apiResp); err != nil {
+		return nil, err
+	}
+
+	// Each availability record is a session; a doctor may have several a day
+	sessions := make([]utils.SlotSession, 0, len(apiResp.Data))
+	booked := make(map[string]bool)
+	for _, avail := range apiResp.Data {
+		sessions = append(sessions, utils.SlotSession{
+			Start:    avail.AvailableTimeStart,
+			End:      avail.AvailableTimeEnd,
+			Duration: time.Duration(avail.SlotDuration) * time.Minute,
+			Buffer:   time.Duration(avail.BufferTime) * time.Minute,
+		})
+		for _, b := range avail.BookedSlots {
+			booked[b.TimeSlot] = true
+		}
+	}
+
+	slots, err := wc.slotGenerator.Generate(date, sessions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate slots: %w", err)
+	}
+
+	// Collect all FREE slots
+	allSlots := []SlotOption{}
+	for _, slot := range slots {
+		formatted := slot.Start.Format("03:04 PM")
+		if !booked[formatted] {
+			allSlots = append(allSlots, SlotOption{
+				DoctorID:       int(doctor),
+				Date:           date,
+				Time:           formatted,
+				Token:          uint(slot.Token),
+				AvailabilityID: apiResp.Data[slot.Session].AvailabilityID,
+			})
+		}
+	}
+	return allSlots, nil
+}
+
+// filterSlotsByWindow keeps the slots that fall in the time window
+func filterSlotsByWindow(slots []SlotOption, window string) []SlotOption {
+	filtered := []SlotOption{}
+	for _, slot := range slots {
+		if utils.TimeWindowForHour(slot.startsAt().Hour()) == window {
+			filtered = append(filtered, slot)
+		}
+	}
+	return filtered
+}
+
 func (wc *WhatsAppController) sendSlotPage(userID string) error {
+	locale := wc.localeFor(userID)
 	state, ok := slotState[userID]
 	if !ok {
-		return wc.whatsappService.SendTextMessage(userID, "⚠ No slots available.")
+		return wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.no_slots"))
 	}
 
 	// Always reserve space for "Next Slots"
@@ -1438,7 +2473,7 @@ func (wc *WhatsAppController) sendSlotPage(userID string) error {
 
 	start := state.Page * pageSize
 	if start >= len(state.Slots) {
-		_ = wc.whatsappService.SendTextMessage(userID, "✅ No more slots.")
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.no_more_slots"))
 		delete(slotState, userID)
 		return nil
 	}
@@ -1448,24 +2483,35 @@ func (wc *WhatsAppController) sendSlotPage(userID string) error {
 		end = len(state.Slots)
 	}
 
+	// Hold the page for this patient; slots someone else got first are left out
+	held := wc.holdSlots(userID, state.Slots[start:end])
+
 	rows := []models.ListItem{}
-	for i, slot := range state.Slots[start:end] {
+	for _, slot := range state.Slots[start:end] {
+		if !held[slot.ref()] {
+			continue
+		}
 		rows = append(rows, models.ListItem{
-			ID:    strconv.Itoa(start + i + 1),
-			Title: slot,
+			ID:    slot.ID(),
+			Title: slot.Time,
 		})
 	}
+	if len(rows) == 0 && end >= len(state.Slots) {
+		_ = wc.whatsappService.SendTextMessage(userID, i18n.T(locale, "booking.no_more_slots"))
+		delete(slotState, userID)
+		return nil
+	}
 
 	// Add "Next Slots" row only if more remain
 	if end < len(state.Slots) {
 		rows = append(rows, models.ListItem{
 			ID:    "more",
-			Title: "➡ Next Slots",
+			Title: i18n.T(locale, "booking.slots_next"),
 		})
 	}
 
 	section := models.Section{
-		Title: fmt.Sprintf("Slots %d - %d", start+1, end),
+		Title: i18n.T(locale, "booking.slots_section", start+1, end),
 		Rows:  rows,
 	}
 
@@ -1475,13 +2521,13 @@ func (wc *WhatsAppController) sendSlotPage(userID string) error {
 		Type: "list",
 		Header: &models.MessageHeader{
 			Type: "text",
-			Text: "⏰ Available Time Slots",
+			Text: i18n.T(locale, "booking.slots_header"),
 		},
 		Body: &models.InteractiveBody{
-			Text: fmt.Sprintf("Page %d of %d", state.Page+1, totalPages),
+			Text: i18n.T(locale, "booking.slots_page", state.Page+1, totalPages),
 		},
 		Action: &models.InteractiveAction{
-			Button:   "Choose Slot",
+			Button:   i18n.T(locale, "booking.slots_button"),
 			Sections: []models.Section{section},
 		},
 	}
@@ -1495,6 +2541,7 @@ func (wc *WhatsAppController) createAppointment(data *AppointmentData, userID st
 	defer cancel()
 
 	url := "http://61.2.142.81:8086/api/tempAppointment/create"
+	locale := wc.localeFor(userID)
 
 	var resp apiAppointmentResponse
 
@@ -1502,7 +2549,7 @@ func (wc *WhatsAppController) createAppointment(data *AppointmentData, userID st
 	if err != nil {
 		// ❌ Send the exact error back to user
 		_ = wc.whatsappService.SendTextMessage(userID,
-			fmt.Sprintf("⚠️ Appointment could not be created: %s", err.Error()))
+			i18n.T(locale, "booking.create_error", err.Error()))
 
 		log.Println("❌ Appointment API error:", err)
 		// delete(appointmentState, userID)
@@ -1513,7 +2560,7 @@ func (wc *WhatsAppController) createAppointment(data *AppointmentData, userID st
 	if !resp.Status {
 		// ❌ Handle logical failure from API (even if status 200/201)
 		_ = wc.whatsappService.SendTextMessage(userID,
-			fmt.Sprintf("⚠️ Appointment failed: %s", resp.Message))
+			i18n.T(locale, "booking.create_rejected", resp.Message))
 
 		log.Printf("❌ Appointment creation failed: %s", resp.Message)
 		delete(appointmentState, userID)
@@ -1522,22 +2569,47 @@ func (wc *WhatsAppController) createAppointment(data *AppointmentData, userID st
 	}
 
 	log.Printf("✅ Appointment created successfully: %+v", resp)
-	_ = wc.whatsappService.SendTextMessage(userID,
-		"✅ Appointment created successfully!")
+	wc.slotSearch.forget(data.DoctorID, data.AppointmentDate)
+	if err := wc.fallbackService.SendCritical(ctx, models.CriticalConfirmation, userID,
+		i18n.T(locale, "booking.created")); err != nil {
+		log.Println("booking confirmation error", err)
+	}
+	go wc.emailConfirmation(*data)
 	return true
 }
 
+// emailConfirmation sends the booking to the front desk by email
+func (wc *WhatsAppController) emailConfirmation(data AppointmentData) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := wc.mailService.SendAppointmentConfirmation(ctx, email.AppointmentConfirmation{
+		Source:       "WhatsApp",
+		PatientName:  data.PatientName,
+		PatientCode:  data.PatientCode,
+		PatientPhone: data.PhoneNumber,
+		DoctorName:   data.DoctorName,
+		Date:         data.AppointmentDate,
+		TimeSlot:     data.TimeSlot,
+		Symptoms:     strings.Join(data.Symptoms, ", "),
+	})
+	if err != nil {
+		log.Println("appointment confirmation email error", err)
+	}
+}
+
 // ========================
 // Main Menu Buttons
 // ========================
 func (wc *WhatsAppController) sendMainMenu(to string) error {
+	locale := wc.localeFor(to)
 	interactive := &models.InteractiveMessage{
 		Type: "button",
 		Body: &models.InteractiveBody{
-			Text: "👋 Hi! How can we help you today?",
+			Text: i18n.T(locale, "menu.body") + "\n\n" + i18n.T(locale, "menu.language_hint") + "\n" + i18n.T(locale, "menu.doctors_hint"),
 		},
 		Footer: &models.InteractiveFooter{
-			Text: "Clinic Support",
+			Text: i18n.T(locale, "menu.footer"),
 		},
 		Action: &models.InteractiveAction{
 			Buttons: []models.InteractiveButton{
@@ -1545,21 +2617,21 @@ func (wc *WhatsAppController) sendMainMenu(to string) error {
 					Type: "reply",
 					Reply: &models.ButtonReply{
 						ID:    "my_appointment",
-						Title: "📅 My Appointment",
+						Title: i18n.T(locale, "menu.my_appointment"),
 					},
 				},
 				{
 					Type: "reply",
 					Reply: &models.ButtonReply{
 						ID:    "new_appointment",
-						Title: "🆕 New Appointment",
+						Title: i18n.T(locale, "menu.new_appointment"),
 					},
 				},
 				{
 					Type: "reply",
 					Reply: &models.ButtonReply{
 						ID:    "contact_us",
-						Title: "📞 Contact Us",
+						Title: i18n.T(locale, "menu.contact_us"),
 					},
 				},
 			},
@@ -1579,14 +2651,24 @@ func (wc *WhatsAppController) handleStatusUpdate(status models.WhatsAppStatus) {
 			fmt.Printf("WhatsApp Error: %d - %s: %s\n", err.Code, err.Title, err.Message)
 		}
 	}
+
+	// Critical messages fall back to SMS when WhatsApp fails
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	wc.fallbackService.HandleWhatsAppStatus(ctx, status)
 }
 
 // SendMessage sends a message to a specific WhatsApp number (for notifications)
 func (wc *WhatsAppController) SendMessage(c *gin.Context) {
 	var req struct {
-		To      string `json:"to" binding:"required"`
-		Message string `json:"message" binding:"required"`
-		Type    string `json:"type"`
+		To       string                       `json:"to" binding:"required"`
+		Message  string                       `json:"message"`
+		Type     string                       `json:"type"`
+		Template string                       `json:"template"`
+		Language string                       `json:"language"`
+		Params   []string                     `json:"params"`
+		Header   *models.TemplateHeaderParam  `json:"header"`
+		Buttons  []models.TemplateButtonParam `json:"buttons"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -1595,17 +2677,73 @@ func (wc *WhatsAppController) SendMessage(c *gin.Context) {
 	}
 
 	// Clean phone number
-	to := wc.whatsappService.CleanPhoneNumber(req.To)
+	to := wc.whatsappService.NormalizePhone(req.To)
+
+	// Admin sends are proactive, so the contact must not have opted out
+	if err := wc.contactService.CheckConsent(c.Request.Context(), to); err != nil {
+		if errors.Is(err, services.ErrContactOptedOut) || errors.Is(err, services.ErrNoOptIn) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Contact has not consented to messages",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to check contact consent",
+			"details": err.Error(),
+		})
+		return
+	}
 
 	var err error
+	delivery := req.Type
 	switch req.Type {
 	case "template":
-		// Handle template messages if needed
-		err = wc.whatsappService.SendTextMessage(to, req.Message)
+		if req.Template == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "template is required"})
+			return
+		}
+		err = wc.templateService.Send(c.Request.Context(), to, models.TemplateMessage{
+			Name:       req.Template,
+			Language:   req.Language,
+			Header:     req.Header,
+			BodyParams: req.Params,
+			Buttons:    req.Buttons,
+		})
 	default:
+		if req.Message == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "message is required"})
+			return
+		}
+		delivery = "text"
+		if open, _, lookupErr := wc.whatsappService.IsWithinServiceWindow(to); lookupErr == nil && !open && wc.whatsappService.WindowFallbackTemplate() != "" {
+			delivery = "template:" + wc.whatsappService.WindowFallbackTemplate()
+		}
 		err = wc.whatsappService.SendTextMessage(to, req.Message)
 	}
 
+	var validationErr *services.TemplateValidationError
+	if errors.Is(err, services.ErrTemplateNotFound) || errors.As(err, &validationErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid template message",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if errors.Is(err, services.ErrOutsideServiceWindow) {
+		_, lastInbound, _ := wc.whatsappService.IsWithinServiceWindow(to)
+		resp := gin.H{
+			"error":   "Customer service window closed",
+			"details": "This contact has not messaged in the last 24 hours. Send an approved template instead (type: \"template\").",
+		}
+		if !lastInbound.IsZero() {
+			resp["last_inbound_at"] = lastInbound
+		}
+		c.JSON(http.StatusUnprocessableEntity, resp)
+		return
+	}
+
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to send message",
@@ -1615,8 +2753,9 @@ func (wc *WhatsAppController) SendMessage(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"status": "sent",
-		"to":     to,
+		"status":   "sent",
+		"to":       to,
+		"delivery": delivery,
 	})
 }
 
@@ -63,6 +63,13 @@ func GetMongoDB() *mongo.Database {
     return mongoDB
 }
 
+// UseMongoDB makes GetMongoDB return db without pinging it or creating
+// indexes. Tests use it with a client that has no server behind it.
+func UseMongoDB(db *mongo.Database) {
+    mongoClient = db.Client()
+    mongoDB = db
+}
+
 // GetMongoClient returns the MongoDB client
 func GetMongoClient() *mongo.Client {
     if mongoClient == nil {
@@ -131,6 +138,205 @@ func createIndexes(ctx context.Context) error {
         return fmt.Errorf("failed to create user indexes: %w", err)
     }
     
+    // Conversations (staff inbox) indexes
+    conversationsCollection := mongoDB.Collection("conversations")
+    conversationIndexes := []mongo.IndexModel{
+        {
+            Keys:    bson.D{{Key: "session_id", Value: 1}},
+            Options: options.Index().SetUnique(true),
+        },
+        {
+            Keys: bson.D{
+                {Key: "status", Value: 1},
+                {Key: "last_activity", Value: -1},
+            },
+        },
+        {
+            Keys: bson.D{{Key: "assigned_agent", Value: 1}},
+        },
+        {
+            Keys: bson.D{{Key: "user_id", Value: 1}},
+        },
+    }
+    
+    if _, err := conversationsCollection.Indexes().CreateMany(ctx, conversationIndexes); err != nil {
+        return fmt.Errorf("failed to create conversation indexes: %w", err)
+    }
+    
+    // Contacts indexes
+    contactsCollection := mongoDB.Collection("contacts")
+    contactIndexes := []mongo.IndexModel{
+        {
+            Keys:    bson.D{{Key: "phone", Value: 1}},
+            Options: options.Index().SetUnique(true),
+        },
+    }
+    
+    if _, err := contactsCollection.Indexes().CreateMany(ctx, contactIndexes); err != nil {
+        return fmt.Errorf("failed to create contact indexes: %w", err)
+    }
+    
+    // Message templates indexes
+    templatesCollection := mongoDB.Collection("message_templates")
+    templateIndexes := []mongo.IndexModel{
+        {
+            Keys: bson.D{
+                {Key: "name", Value: 1},
+                {Key: "language", Value: 1},
+            },
+            Options: options.Index().SetUnique(true),
+        },
+    }
+    
+    if _, err := templatesCollection.Indexes().CreateMany(ctx, templateIndexes); err != nil {
+        return fmt.Errorf("failed to create template indexes: %w", err)
+    }
+    
+    // Campaign indexes
+    campaignRecipientsCollection := mongoDB.Collection("campaign_recipients")
+    campaignRecipientIndexes := []mongo.IndexModel{
+        {
+            Keys: bson.D{
+                {Key: "campaign_id", Value: 1},
+                {Key: "status", Value: 1},
+            },
+        },
+    }
+    
+    if _, err := campaignRecipientsCollection.Indexes().CreateMany(ctx, campaignRecipientIndexes); err != nil {
+        return fmt.Errorf("failed to create campaign recipient indexes: %w", err)
+    }
+    
+    campaignsCollection := mongoDB.Collection("campaigns")
+    if _, err := campaignsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+        Keys: bson.D{{Key: "status", Value: 1}},
+    }); err != nil {
+        return fmt.Errorf("failed to create campaign indexes: %w", err)
+    }
+
+    // Emergency alert indexes
+    alertsCollection := mongoDB.Collection("emergency_alerts")
+    alertIndexes := []mongo.IndexModel{
+        {
+            Keys: bson.D{
+                {Key: "status", Value: 1},
+                {Key: "last_notified_at", Value: 1},
+            },
+        },
+        {
+            Keys: bson.D{
+                {Key: "session_id", Value: 1},
+                {Key: "status", Value: 1},
+            },
+        },
+        {
+            Keys:    bson.D{{Key: "code", Value: 1}},
+            Options: options.Index().SetUnique(true),
+        },
+    }
+
+    if _, err := alertsCollection.Indexes().CreateMany(ctx, alertIndexes); err != nil {
+        return fmt.Errorf("failed to create emergency alert indexes: %w", err)
+    }
+
+    // Critical message (SMS fallback) indexes
+    criticalCollection := mongoDB.Collection("critical_messages")
+    criticalIndexes := []mongo.IndexModel{
+        {
+            Keys: bson.D{{Key: "whatsapp_message_id", Value: 1}},
+        },
+        {
+            Keys: bson.D{
+                {Key: "status", Value: 1},
+                {Key: "created_at", Value: 1},
+            },
+        },
+    }
+
+    if _, err := criticalCollection.Indexes().CreateMany(ctx, criticalIndexes); err != nil {
+        return fmt.Errorf("failed to create critical message indexes: %w", err)
+    }
+
+    // Knowledge base indexes
+    knowledgeChunksCollection := mongoDB.Collection("knowledge_chunks")
+    if _, err := knowledgeChunksCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+        Keys: bson.D{
+            {Key: "document_id", Value: 1},
+            {Key: "index", Value: 1},
+        },
+    }); err != nil {
+        return fmt.Errorf("failed to create knowledge chunk indexes: %w", err)
+    }
+
+    // Doctor directory indexes
+    doctorProfilesCollection := mongoDB.Collection("doctor_profiles")
+    if _, err := doctorProfilesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+        {
+            Keys:    bson.D{{Key: "employee_id", Value: 1}},
+            Options: options.Index().SetUnique(true),
+        },
+        {
+            Keys: bson.D{{Key: "department_id", Value: 1}},
+        },
+    }); err != nil {
+        return fmt.Errorf("failed to create doctor profile indexes: %w", err)
+    }
+
+    // Slot hold indexes; Mongo deletes holds once they expire
+    slotHoldsCollection := mongoDB.Collection("slot_holds")
+    if _, err := slotHoldsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+        {
+            Keys: bson.D{
+                {Key: "doctor_id", Value: 1},
+                {Key: "date", Value: 1},
+                {Key: "time", Value: 1},
+            },
+            Options: options.Index().SetUnique(true),
+        },
+        {
+            Keys: bson.D{{Key: "holder_id", Value: 1}},
+        },
+        {
+            Keys:    bson.D{{Key: "expires_at", Value: 1}},
+            Options: options.Index().SetExpireAfterSeconds(0),
+        },
+    }); err != nil {
+        return fmt.Errorf("failed to create slot hold indexes: %w", err)
+    }
+
+    // Waitlist indexes; waiting patients are served oldest first
+    waitlistCollection := mongoDB.Collection("waitlist")
+    if _, err := waitlistCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+        {
+            Keys: bson.D{
+                {Key: "status", Value: 1},
+                {Key: "created_at", Value: 1},
+            },
+        },
+        {
+            Keys: bson.D{
+                {Key: "phone", Value: 1},
+                {Key: "status", Value: 1},
+            },
+        },
+        {
+            Keys: bson.D{{Key: "offer.expires_at", Value: 1}},
+        },
+    }); err != nil {
+        return fmt.Errorf("failed to create waitlist indexes: %w", err)
+    }
+
+    // Household indexes; one household per WhatsApp number
+    householdsCollection := mongoDB.Collection("households")
+    if _, err := householdsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+        {
+            Keys:    bson.D{{Key: "phone", Value: 1}},
+            Options: options.Index().SetUnique(true),
+        },
+    }); err != nil {
+        return fmt.Errorf("failed to create household indexes: %w", err)
+    }
+
     log.Println("Database indexes created successfully")
     return nil
 }
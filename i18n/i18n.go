@@ -0,0 +1,294 @@
+// Package i18n holds the bot's message catalog and locale helpers.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type Locale string
+
+const (
+	English   Locale = "en"
+	Hindi     Locale = "hi"
+	Malayalam Locale = "ml"
+	Tamil     Locale = "ta"
+)
+
+// Default is used when no locale has been chosen or detected
+const Default = English
+
+// Supported lists the locales with a complete catalog, in menu order
+var Supported = []Locale{English, Hindi, Malayalam, Tamil}
+
+var nativeNames = map[Locale]string{
+	English:   "English",
+	Hindi:     "हिन्दी",
+	Malayalam: "മലയാളം",
+	Tamil:     "தமிழ்",
+}
+
+var englishNames = map[Locale]string{
+	English:   "English",
+	Hindi:     "Hindi",
+	Malayalam: "Malayalam",
+	Tamil:     "Tamil",
+}
+
+// aliases maps user input (codes, English and native names) to a locale
+var aliases = map[string]Locale{
+	"en": English, "english": English,
+	"hi": Hindi, "hindi": Hindi, "हिन्दी": Hindi, "हिंदी": Hindi,
+	"ml": Malayalam, "malayalam": Malayalam, "മലയാളം": Malayalam,
+	"ta": Tamil, "tamil": Tamil, "தமிழ்": Tamil,
+}
+
+// Name returns the language name in its own script, e.g. "हिन्दी"
+func (l Locale) Name() string {
+	return nativeNames[l]
+}
+
+// EnglishName returns the language name in English, e.g. "Hindi"
+func (l Locale) EnglishName() string {
+	return englishNames[l]
+}
+
+// TemplateLanguage returns the WhatsApp template language code for the locale
+func (l Locale) TemplateLanguage() string {
+	return string(l)
+}
+
+// Parse resolves a locale code or language name. Region suffixes such as
+// "en-IN" are ignored.
+func Parse(s string) (Locale, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "-_"); i > 0 {
+		s = s[:i]
+	}
+	l, ok := aliases[s]
+	return l, ok
+}
+
+// Detect guesses the locale from the script of the text. Latin text is not
+// detected because patients often type Hindi or Malayalam in English letters.
+func Detect(text string) (Locale, bool) {
+	counts := map[Locale]int{}
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Devanagari, r):
+			counts[Hindi]++
+		case unicode.Is(unicode.Malayalam, r):
+			counts[Malayalam]++
+		case unicode.Is(unicode.Tamil, r):
+			counts[Tamil]++
+		}
+	}
+
+	best, bestCount := Locale(""), 0
+	for _, l := range Supported {
+		if counts[l] > bestCount {
+			best, bestCount = l, counts[l]
+		}
+	}
+	return best, bestCount > 0
+}
+
+// T returns the message for key in the given locale, formatted with args.
+// Missing translations fall back to English, and missing keys to the key itself.
+func T(locale Locale, key string, args ...interface{}) string {
+	msg, ok := catalog[locale][key]
+	if !ok {
+		msg, ok = catalog[English][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+var yesWords = map[string]bool{
+	"yes": true, "y": true, "haan": true, "ha": true, "han": true,
+	"हाँ": true, "हां": true, "जी": true,
+	"അതെ": true, "ഉവ്വ്": true, "ഉണ്ട്": true,
+	"ஆம்": true, "ஆமா": true,
+}
+
+var noWords = map[string]bool{
+	"no": true, "n": true, "nahi": true, "nahin": true,
+	"नहीं": true, "ना": true,
+	"ഇല്ല": true, "അല്ല": true,
+	"இல்லை": true, "இல்ல": true,
+}
+
+// IsYes reports whether the reply means "yes" in any supported language
+func IsYes(text string) bool {
+	return yesWords[strings.ToLower(strings.TrimSpace(text))]
+}
+
+// IsNo reports whether the reply means "no" in any supported language
+func IsNo(text string) bool {
+	return noWords[strings.ToLower(strings.TrimSpace(text))]
+}
+
+var greetingWords = map[string]bool{
+	"hi": true, "hello": true,
+	"namaste": true, "नमस्ते": true, "नमस्कार": true,
+	"namaskaram": true, "നമസ്കാരം": true,
+	"vanakkam": true, "வணக்கம்": true,
+}
+
+// IsGreeting reports whether the text is a bare greeting that restarts the menu
+func IsGreeting(text string) bool {
+	return greetingWords[strings.ToLower(strings.TrimSpace(text))]
+}
+
+var languageKeywords = map[string]bool{
+	"language": true, "lang": true,
+	"भाषा": true, "ഭാഷ": true, "மொழி": true,
+}
+
+// IsLanguageRequest reports whether the text asks to change the language
+func IsLanguageRequest(text string) bool {
+	return languageKeywords[strings.ToLower(strings.TrimSpace(text))]
+}
+
+// handoffPhrases ask for a person instead of the bot
+var handoffPhrases = []string{
+	"human", "real person", "agent", "receptionist", "talk to someone", "speak to someone",
+	"talk to staff", "speak to staff", "customer care",
+	"इंसान", "किसी से बात", "रिसेप्शन",
+	"ആരോടെങ്കിലും സംസാരിക്കണം", "റിസപ്ഷൻ",
+	"யாரிடமாவது பேச", "வரவேற்பு",
+}
+
+// IsHandoffRequest reports whether the text asks to talk to clinic staff
+func IsHandoffRequest(text string) bool {
+	text = strings.ToLower(text)
+	for _, phrase := range handoffPhrases {
+		if containsPhrase(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsPhrase matches phrase at word boundaries, so "agent" does not
+// match "reagent"
+func containsPhrase(text, phrase string) bool {
+	for start := 0; ; {
+		i := strings.Index(text[start:], phrase)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(phrase)
+		if (i == 0 || !isWordByte(text[i-1])) && (end == len(text) || !isWordByte(text[end])) {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9'
+}
+
+// doctorsPhrases ask to browse the doctor directory
+var doctorsPhrases = []string{
+	"doctors", "our doctors", "doctor list", "list of doctors",
+	"डॉक्टरों", "डॉक्टर सूची",
+	"ഡോക്ടർമാർ", "ഡോക്ടർമാരെ",
+	"மருத்துவர்கள்",
+}
+
+// IsDoctorsRequest reports whether the text asks to see the doctor directory
+func IsDoctorsRequest(text string) bool {
+	text = strings.ToLower(text)
+	for _, phrase := range doctorsPhrases {
+		if containsPhrase(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstAvailablePhrases ask for the earliest open slot instead of a date.
+// "first" alone is not one: "first of November" is a date.
+var firstAvailablePhrases = []string{
+	"first available", "earliest", "asap", "soonest", "any day", "any date",
+	"जल्द से जल्द", "सबसे पहले",
+	"ആദ്യം ലഭ്യമായ", "എത്രയും വേഗം",
+	"முதலில் கிடைக்கும்", "விரைவில்",
+}
+
+// IsFirstAvailable reports whether the text asks for the earliest open slot:
+// one of firstAvailablePhrases, or FIRST as the whole reply
+func IsFirstAvailable(text string) bool {
+	text = strings.ToLower(text)
+	if strings.Trim(text, " .!\t\r\n") == "first" {
+		return true
+	}
+	for _, phrase := range firstAvailablePhrases {
+		if containsPhrase(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// Relations are the family relations of household members, in the order
+// they are offered. Labels are the "family.relation.<relation>" messages.
+var Relations = []string{"self", "spouse", "son", "daughter", "father", "mother", "brother", "sister", "grandparent", "other"}
+
+// relationPhrases name family members, e.g. "book for my daughter".
+// Grandparents come first so "grandmother" is not read as "mother".
+var relationPhrases = []struct {
+	relation string
+	phrases  []string
+}{
+	{"grandparent", []string{"grandfather", "grandmother", "grandpa", "grandma", "दादा", "दादी", "नाना", "नानी", "മുത്തച്ഛൻ", "മുത്തശ്ശി", "தாத்தா", "பாட்டி"}},
+	{"spouse", []string{"wife", "husband", "spouse", "पत्नी", "पति", "ഭാര്യ", "ഭർത്താവ്", "மனைவி", "கணவர்"}},
+	{"son", []string{"son", "बेटा", "बेटे", "മകൻ", "மகன்"}},
+	{"daughter", []string{"daughter", "बेटी", "മകൾ", "மகள்"}},
+	{"father", []string{"father", "dad", "पिता", "पापा", "അച്ഛൻ", "அப்பா", "தந்தை"}},
+	{"mother", []string{"mother", "mom", "mum", "माँ", "मां", "माता", "അമ്മ", "அம்மா", "தாய்"}},
+	{"brother", []string{"brother", "भाई", "സഹോദരൻ", "சகோதரர்", "அண்ணன்", "தம்பி"}},
+	{"sister", []string{"sister", "बहन", "സഹോദരി", "சகோதரி", "அக்கா", "தங்கை"}},
+	{"self", []string{"myself", "for me", "मेरे लिए", "എനിക്ക്", "எனக்கு"}},
+}
+
+// FamilyRelation returns the family relation a text mentions, if any
+func FamilyRelation(text string) (string, bool) {
+	text = strings.ToLower(text)
+	for _, r := range relationPhrases {
+		for _, phrase := range r.phrases {
+			if containsPhrase(text, phrase) {
+				return r.relation, true
+			}
+		}
+	}
+	return "", false
+}
+
+// addFamilyPhrases ask to register a family member as a new patient
+var addFamilyPhrases = []string{
+	"add family member", "add a family member", "add family", "new family member",
+	"परिवार का सदस्य जोड़", "परिवार जोड़",
+	"കുടുംബാംഗത്തെ ചേർക്കുക", "കുടുംബാംഗം ചേർക്കുക",
+	"குடும்ப உறுப்பினரைச் சேர்", "குடும்ப உறுப்பினர் சேர்",
+}
+
+// IsAddFamilyRequest reports whether the text asks to add a family member
+func IsAddFamilyRequest(text string) bool {
+	text = strings.ToLower(text)
+	for _, phrase := range addFamilyPhrases {
+		if containsPhrase(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
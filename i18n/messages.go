@@ -0,0 +1,738 @@
+package i18n
+
+// catalog holds every user-facing bot string per locale. Keys are grouped by
+// feature: menu.*, language.*, booking.* (WhatsApp flow) and chat.* (ChatbotService).
+// WhatsApp button titles are limited to 20 characters.
+var catalog = map[Locale]map[string]string{
+	English: {
+		// Main menu
+		"menu.body":            "👋 Hi! How can we help you today?",
+		"menu.language_hint":   "🌐 Reply LANGUAGE to change language.",
+		"menu.doctors_hint":    "👩‍⚕️ Reply DOCTORS to meet our doctors.",
+		"menu.footer":          "Clinic Support",
+		"menu.my_appointment":  "📅 My Appointment",
+		"menu.new_appointment": "🆕 New Appointment",
+		"menu.contact_us":      "📞 Contact Us",
+		"menu.not_understood":  "🤔 Sorry, I didn’t understand that.",
+
+		// Language selection
+		"language.prompt":  "🌐 Please choose your language:",
+		"language.button":  "Choose Language",
+		"language.section": "Languages",
+		"language.changed": "✅ Language set to English.",
+
+		// Appointment booking flow
+		"booking.ask_consulted_before": "🩺 Have you already consulted here before? (Yes/No)",
+		"booking.ask_patient_id":       "📋 Please enter your patient id or phone number:",
+		"booking.ask_name":             "👤 Please enter your full name:",
+		"booking.ask_address":          "🏠 Please enter your address:",
+		"booking.ask_phone":            "📞 Please enter your phone number:",
+		"booking.invalid_phone":        "❌ That doesn't look like a valid phone number. Please enter it again, e.g. 98765 43210 or +91 98765 43210:",
+		"booking.holiday_closed":       "🏖️ We're closed on %s for %s. Please enter another date (YYYY-MM-DD):",
+		"booking.ask_dob":              "📅 Please enter your date of birth (YYYY-MM-DD):",
+		"booking.invalid_dob":          "❌ Please enter the date of birth as YYYY-MM-DD, e.g. 2015-06-21.",
+		"booking.ask_date":             "📅 Please enter your preferred date (YYYY-MM-DD):",
+		"booking.reply_yes_no":         "❌ Please reply Yes or No.",
+		"booking.patient_not_found":    "❌ No patient found. Please try again.",
+		"booking.patient_fetch_failed": "❌ Could not fetch patient details. Please try again.",
+		"booking.patients_header":      "📅 Patient Details",
+		"booking.patients_body":        "Choose one patient for appointment:",
+		"booking.patients_button":      "Choose Patient",
+		"booking.patients_section":     "Search Results",
+		"booking.patient_code":         "Patient Code: %s",
+		"booking.department_body":      "Please select a department",
+		"booking.department_button":    "Choose",
+		"booking.department_section":   "Departments",
+		"booking.doctors_header":       "👨‍⚕️ Available Doctors",
+		"booking.doctors_body":         "Please select a doctor from the list below:",
+		"booking.doctors_button":       "Choose Doctor",
+		"booking.doctors_section":      "Doctors",
+		"booking.no_doctors":           "❌ No doctors available in this department on the selected date.",
+		"booking.slots_header":         "⏰ Available Time Slots",
+		"booking.slots_page":           "Page %d of %d",
+		"booking.slots_button":         "Choose Slot",
+		"booking.slots_section":        "Slots %d - %d",
+		"booking.slots_next":           "➡ Next Slots",
+		"booking.no_slots":             "❌ No available slots found for this doctor.",
+		"booking.no_more_slots":        "✅ No more slots.",
+		"booking.slot_taken":           "⚠️ Sorry, that slot was just taken by someone else. Please choose another:",
+		"booking.slot_stale":           "⌛ That list is out of date. Please choose from this one:",
+		"booking.created":              "✅ Appointment created successfully!",
+		"booking.reminder":             "⏰ Reminder: you have an appointment with %s on %s at %s.",
+		"booking.create_error":         "⚠️ Appointment could not be created: %s",
+		"booking.create_rejected":      "⚠️ Appointment failed: %s",
+		"booking.confirmed":            "✅ Appointment booked with %s on %s at %s",
+		"booking.failed":               "⚠️ Failed to book appointment. Try again later.",
+		"booking.noted":                "📝 Noted: %s",
+		"booking.first_available":      "⚡ First available",
+		"booking.first_available_desc": "Earliest slot with any doctor",
+		"booking.first_available_hint": "Or reply FIRST for the earliest available slot.",
+		"booking.searching":            "🔎 Looking for the earliest open slots…",
+		"booking.earliest_body":        "Earliest open slots in the next %d days:",
+
+		// ChatbotService (web chat)
+		"chat.emergency":              "🚨 EMERGENCY DETECTED! Please call %s immediately or visit the nearest emergency room. For immediate assistance, call our emergency line: %s",
+		"chat.action.call_emergency":  "Call Emergency",
+		"chat.action.call_clinic_er":  "Call Clinic Emergency",
+		"chat.clinic_intro":           "Here's information about %s:\n\n",
+		"chat.clinic_address":         "📍 Address: %s\n",
+		"chat.clinic_phone":           "📞 Phone: %s\n",
+		"chat.clinic_hours":           "🕐 Hours: %s\n",
+		"chat.clinic_services":        "🏥 Services: %s\n",
+		"chat.clinic_departments":     "🩺 Departments: %s\n",
+		"chat.clinic_anything_else":   "\nIs there anything specific you'd like to know?",
+		"chat.action.show_map":        "Show on Map",
+		"chat.action.call_clinic":     "Call Clinic",
+		"chat.action.book":            "Book an Appointment",
+		"chat.medical_unavailable":    "I apologize, but I'm having trouble processing your medical query right now. For medical concerns, it's always best to consult with our healthcare providers directly. Would you like to book an appointment?",
+		"chat.medical_disclaimer":     "\n\n⚠️ Note: This information is for educational purposes only. Please consult with our healthcare providers for personalized medical advice.",
+		"chat.sources":                "\n\n📄 Sources:\n%s",
+		"chat.knowledge_quote":        "Here is what our clinic information says:\n\n%s",
+		"chat.action.consultation":    "Book a Consultation",
+		"chat.action.nurse":           "Speak to a Nurse",
+		"chat.action.view_doctors":    "View Our Doctors",
+		"chat.good_morning":           "Good morning",
+		"chat.good_afternoon":         "Good afternoon",
+		"chat.good_evening":           "Good evening",
+		"chat.greeting":               "%s! Welcome to %s. I'm here to help you with:\n\n• 📅 Booking appointments\n• 🏥 Clinic information\n• 💊 General health queries\n• 🚨 Emergency assistance\n• 📋 Managing your appointments\n\nHow can I assist you today?",
+		"chat.action.quick_book":      "Book Appointment",
+		"chat.action.clinic_hours":    "Clinic Hours",
+		"chat.action.emergency_help":  "Emergency Help",
+		"chat.cancel_prompt":          "I can help you cancel your appointment. Please provide your appointment ID or let me look up your upcoming appointments.",
+		"chat.reschedule_prompt":      "I can help you reschedule your appointment. First, let me find your current appointment. Please provide your appointment ID or shall I look up your appointments?",
+		"chat.action.my_appointments": "View My Appointments",
+		"chat.action.enter_appt_id":   "Enter Appointment ID",
+
+		// Opening hours and reception handoff
+		"hours.open_now":            "✅ We're open now, until %s.",
+		"hours.closed_now":          "🌙 We're closed right now. We open again %s.",
+		"hours.holiday_now":         "🏖️ We're closed today for %s. We open again %s.",
+		"hours.closed_now_unknown":  "🌙 We're closed right now.",
+		"hours.open_on":             "✅ On %s we're open %s-%s.",
+		"hours.closed_on":           "🌙 We're closed on %s.",
+		"hours.holiday_on":          "🏖️ We're closed on %s for %s.",
+		"hours.after_hours":         "🌙 We're closed right now and open again %s. You can still book an appointment here. In an emergency, call %s.",
+		"hours.after_hours_unknown": "🌙 We're closed right now. You can still book an appointment here. In an emergency, call %s.",
+		"hours.handoff":             "👩‍⚕️ I've asked our reception team to join this chat. Someone will reply shortly.",
+		"hours.handoff_closed":      "Our reception team is available again %s and will reply then. Meanwhile I can help you book an appointment.",
+		"hours.handoff_unavailable": "Our reception team isn't available right now. Meanwhile I can help you book an appointment.",
+
+		// Doctor directory
+		"doctors.body_departments":  "👩‍⚕️ Meet our doctors. Choose a department:",
+		"doctors.department_button": "Departments",
+		"doctors.body_department":   "👩‍⚕️ Our doctors in %s:",
+		"doctors.body_all":          "👩‍⚕️ Our doctors:",
+		"doctors.doctor_button":     "View doctors",
+		"doctors.empty":             "Our doctor directory isn't available right now. Please try again later.",
+		"doctors.not_found":         "Sorry, I couldn't find that doctor.",
+		"doctors.qualifications":    "🎓 Qualifications: %s",
+		"doctors.languages":         "🗣️ Languages: %s",
+		"doctors.days":              "📅 Consulting days: %s",
+		"doctors.fee":               "💰 Consultation fee: %s",
+		"doctors.book":              "Book with doctor",
+		"doctors.back":              "All doctors",
+
+		// Waitlist
+		"waitlist.offer_join":    "😔 No open slots in the next %d days. Would you like to join the waitlist? We'll message you as soon as a slot opens.",
+		"waitlist.join_button":   "Join waitlist",
+		"waitlist.no_button":     "No thanks",
+		"waitlist.joined":        "✅ You're on the waitlist for %s from %s to %s. We'll message you when a slot opens.",
+		"waitlist.any_doctor":    "any doctor in the department",
+		"waitlist.offer":         "🎉 A slot just opened with %s on %s at %s. We're holding it for you for %d minutes. Tap Book now or reply YES to book it.",
+		"waitlist.book_button":   "Book now",
+		"waitlist.skip_button":   "Not this one",
+		"waitlist.leave_button":  "Leave waitlist",
+		"waitlist.offer_expired": "⌛ The slot we held for you has been released. You're still on the waitlist.",
+		"waitlist.offer_gone":    "⌛ Sorry, that offer is no longer available.",
+		"waitlist.skipped":       "👍 No problem, you're still on the waitlist.",
+		"waitlist.left":          "You've left the waitlist.",
+
+		// Family members booked for from one number
+		"family.booking_for":          "👤 Booking for %s.",
+		"family.confirm":              "👋 Welcome back! Booking for %s?",
+		"family.confirm_yes":          "Yes",
+		"family.new_patient":          "👋 Welcome! We don't have a patient registered with this number yet. Is this appointment for you?",
+		"family.for_me":               "For me",
+		"family.choose_body":          "👨‍👩‍👧 Who is this appointment for?",
+		"family.section":              "Your family",
+		"family.someone_else":         "Someone else",
+		"family.someone_else_desc":    "Find another patient by ID or phone",
+		"family.add":                  "Add family member",
+		"family.add_desc":             "Register a new patient",
+		"family.ask_name":             "👤 Please enter the family member's full name:",
+		"family.ask_relation":         "How is %s related to you?",
+		"family.relation_button":      "Choose relation",
+		"family.ask_dob":              "📅 Please enter %s's date of birth (YYYY-MM-DD):",
+		"family.ask_address":          "🏠 Please enter %s's address:",
+		"family.relation.self":        "Myself",
+		"family.relation.spouse":      "Spouse",
+		"family.relation.son":         "Son",
+		"family.relation.daughter":    "Daughter",
+		"family.relation.father":      "Father",
+		"family.relation.mother":      "Mother",
+		"family.relation.brother":     "Brother",
+		"family.relation.sister":      "Sister",
+		"family.relation.grandparent": "Grandparent",
+		"family.relation.other":       "Other",
+
+		// New patient registration
+		"registration.existing_body":    "🔎 We found existing records that may belong to %s. Is one of these the patient?",
+		"registration.existing_button":  "Choose record",
+		"registration.existing_section": "Existing records",
+		"registration.none":             "None of these",
+		"registration.none_desc":        "Register as a new patient",
+		"registration.done":             "✅ %s is registered. Patient code: %s. Please keep it for future visits.",
+		"registration.found":            "✅ Using the existing record of %s, patient code %s.",
+		"registration.retry":            "⚠️ We couldn't complete the registration just now. Would you like to try again?",
+		"registration.retry_button":     "Try again",
+		"registration.cancel_button":    "Cancel",
+		"registration.failed":           "⚠️ We still couldn't complete the registration. We'll book with the details given and the front desk will finish it.",
+		"registration.failed_later":     "⚠️ We couldn't complete the registration. Please try again later.",
+	},
+
+	Hindi: {
+		"menu.body":            "👋 नमस्ते! आज हम आपकी क्या मदद कर सकते हैं?",
+		"menu.language_hint":   "🌐 भाषा बदलने के लिए LANGUAGE लिखें।",
+		"menu.doctors_hint":    "👩‍⚕️ हमारे डॉक्टरों के बारे में जानने के लिए DOCTORS लिखें।",
+		"menu.footer":          "क्लिनिक सहायता",
+		"menu.my_appointment":  "📅 मेरी अपॉइंटमेंट",
+		"menu.new_appointment": "🆕 नई अपॉइंटमेंट",
+		"menu.contact_us":      "📞 संपर्क करें",
+		"menu.not_understood":  "🤔 क्षमा करें, मैं समझ नहीं पाया।",
+
+		"language.prompt":  "🌐 कृपया अपनी भाषा चुनें:",
+		"language.button":  "भाषा चुनें",
+		"language.section": "भाषाएँ",
+		"language.changed": "✅ भाषा हिन्दी में बदल दी गई है।",
+
+		"booking.ask_consulted_before": "🩺 क्या आप पहले यहाँ परामर्श ले चुके हैं? (हाँ/नहीं)",
+		"booking.ask_patient_id":       "📋 कृपया अपना पेशेंट आईडी या फ़ोन नंबर दर्ज करें:",
+		"booking.ask_name":             "👤 कृपया अपना पूरा नाम दर्ज करें:",
+		"booking.ask_address":          "🏠 कृपया अपना पता दर्ज करें:",
+		"booking.ask_phone":            "📞 कृपया अपना फ़ोन नंबर दर्ज करें:",
+		"booking.invalid_phone":        "❌ यह मान्य फ़ोन नंबर नहीं लगता। कृपया फिर से दर्ज करें, जैसे 98765 43210 या +91 98765 43210:",
+		"booking.holiday_closed":       "🏖️ %s को %s के कारण हम बंद हैं। कृपया कोई और तारीख दर्ज करें (YYYY-MM-DD):",
+		"booking.ask_dob":              "📅 कृपया अपनी जन्मतिथि दर्ज करें (YYYY-MM-DD):",
+		"booking.invalid_dob":          "❌ कृपया जन्मतिथि YYYY-MM-DD के रूप में दर्ज करें, जैसे 2015-06-21।",
+		"booking.ask_date":             "📅 कृपया अपनी पसंदीदा तारीख दर्ज करें (YYYY-MM-DD):",
+		"booking.reply_yes_no":         "❌ कृपया हाँ या नहीं में उत्तर दें।",
+		"booking.patient_not_found":    "❌ कोई मरीज़ नहीं मिला। कृपया फिर से प्रयास करें।",
+		"booking.patient_fetch_failed": "❌ मरीज़ का विवरण प्राप्त नहीं हो सका। कृपया फिर से प्रयास करें।",
+		"booking.patients_header":      "📅 मरीज़ का विवरण",
+		"booking.patients_body":        "अपॉइंटमेंट के लिए एक मरीज़ चुनें:",
+		"booking.patients_button":      "मरीज़ चुनें",
+		"booking.patients_section":     "खोज परिणाम",
+		"booking.patient_code":         "पेशेंट कोड: %s",
+		"booking.department_body":      "कृपया एक विभाग चुनें",
+		"booking.department_button":    "चुनें",
+		"booking.department_section":   "विभाग",
+		"booking.doctors_header":       "👨‍⚕️ उपलब्ध डॉक्टर",
+		"booking.doctors_body":         "कृपया नीचे दी गई सूची से डॉक्टर चुनें:",
+		"booking.doctors_button":       "डॉक्टर चुनें",
+		"booking.doctors_section":      "डॉक्टर",
+		"booking.no_doctors":           "❌ चुनी गई तारीख पर इस विभाग में कोई डॉक्टर उपलब्ध नहीं है।",
+		"booking.slots_header":         "⏰ उपलब्ध समय",
+		"booking.slots_page":           "पृष्ठ %d / %d",
+		"booking.slots_button":         "समय चुनें",
+		"booking.slots_section":        "स्लॉट %d - %d",
+		"booking.slots_next":           "➡ अगले स्लॉट",
+		"booking.no_slots":             "❌ इस डॉक्टर के लिए कोई स्लॉट उपलब्ध नहीं है।",
+		"booking.no_more_slots":        "✅ और स्लॉट नहीं हैं।",
+		"booking.slot_taken":           "⚠️ माफ़ कीजिए, यह स्लॉट अभी किसी और ने ले लिया। कृपया दूसरा चुनें:",
+		"booking.slot_stale":           "⌛ वह सूची पुरानी हो गई है। कृपया इसमें से चुनें:",
+		"booking.created":              "✅ अपॉइंटमेंट सफलतापूर्वक बन गई!",
+		"booking.reminder":             "⏰ रिमाइंडर: %s के साथ आपकी अपॉइंटमेंट %s को %s बजे है।",
+		"booking.create_error":         "⚠️ अपॉइंटमेंट नहीं बन सकी: %s",
+		"booking.create_rejected":      "⚠️ अपॉइंटमेंट विफल: %s",
+		"booking.confirmed":            "✅ %s के साथ %s को %s बजे अपॉइंटमेंट बुक हो गई",
+		"booking.failed":               "⚠️ अपॉइंटमेंट बुक नहीं हो सकी। कृपया बाद में प्रयास करें।",
+		"booking.noted":                "📝 नोट किया: %s",
+		"booking.first_available":      "⚡ सबसे पहला उपलब्ध",
+		"booking.first_available_desc": "किसी भी डॉक्टर के साथ सबसे जल्दी स्लॉट",
+		"booking.first_available_hint": "या सबसे जल्दी उपलब्ध स्लॉट के लिए FIRST लिखें।",
+		"booking.searching":            "🔎 सबसे जल्दी खाली स्लॉट खोजे जा रहे हैं…",
+		"booking.earliest_body":        "अगले %d दिनों में सबसे जल्दी खाली स्लॉट:",
+
+		"chat.emergency":              "🚨 आपातकाल! कृपया तुरंत %s पर कॉल करें या नज़दीकी आपातकालीन कक्ष में जाएँ। तत्काल सहायता के लिए हमारी आपातकालीन लाइन पर कॉल करें: %s",
+		"chat.action.call_emergency":  "आपातकालीन कॉल",
+		"chat.action.call_clinic_er":  "क्लिनिक आपातकाल कॉल",
+		"chat.clinic_intro":           "%s के बारे में जानकारी:\n\n",
+		"chat.clinic_address":         "📍 पता: %s\n",
+		"chat.clinic_phone":           "📞 फ़ोन: %s\n",
+		"chat.clinic_hours":           "🕐 समय: %s\n",
+		"chat.clinic_services":        "🏥 सेवाएँ: %s\n",
+		"chat.clinic_departments":     "🩺 विभाग: %s\n",
+		"chat.clinic_anything_else":   "\nक्या आप कुछ और जानना चाहेंगे?",
+		"chat.action.show_map":        "नक्शे पर देखें",
+		"chat.action.call_clinic":     "क्लिनिक को कॉल करें",
+		"chat.action.book":            "अपॉइंटमेंट बुक करें",
+		"chat.medical_unavailable":    "क्षमा करें, अभी आपके स्वास्थ्य प्रश्न का उत्तर देने में समस्या आ रही है। स्वास्थ्य संबंधी चिंताओं के लिए हमारे डॉक्टरों से सीधे परामर्श लेना सबसे अच्छा है। क्या आप अपॉइंटमेंट बुक करना चाहेंगे?",
+		"chat.medical_disclaimer":     "\n\n⚠️ नोट: यह जानकारी केवल शैक्षिक उद्देश्य के लिए है। व्यक्तिगत चिकित्सा सलाह के लिए कृपया हमारे डॉक्टरों से परामर्श लें।",
+		"chat.sources":                "\n\n📄 स्रोत:\n%s",
+		"chat.knowledge_quote":        "हमारी क्लिनिक जानकारी के अनुसार:\n\n%s",
+		"chat.action.consultation":    "परामर्श बुक करें",
+		"chat.action.nurse":           "नर्स से बात करें",
+		"chat.action.view_doctors":    "हमारे डॉक्टर देखें",
+		"chat.good_morning":           "सुप्रभात",
+		"chat.good_afternoon":         "नमस्कार",
+		"chat.good_evening":           "शुभ संध्या",
+		"chat.greeting":               "%s! %s में आपका स्वागत है। मैं इनमें आपकी मदद कर सकता हूँ:\n\n• 📅 अपॉइंटमेंट बुक करना\n• 🏥 क्लिनिक की जानकारी\n• 💊 सामान्य स्वास्थ्य प्रश्न\n• 🚨 आपातकालीन सहायता\n• 📋 आपकी अपॉइंटमेंट प्रबंधित करना\n\nआज मैं आपकी क्या सहायता करूँ?",
+		"chat.action.quick_book":      "अपॉइंटमेंट बुक करें",
+		"chat.action.clinic_hours":    "क्लिनिक का समय",
+		"chat.action.emergency_help":  "आपातकालीन सहायता",
+		"chat.cancel_prompt":          "मैं आपकी अपॉइंटमेंट रद्द करने में मदद कर सकता हूँ। कृपया अपनी अपॉइंटमेंट आईडी दें या मुझे आपकी आगामी अपॉइंटमेंट देखने दें।",
+		"chat.reschedule_prompt":      "मैं आपकी अपॉइंटमेंट बदलने में मदद कर सकता हूँ। पहले आपकी मौजूदा अपॉइंटमेंट ढूँढ़ते हैं। कृपया अपनी अपॉइंटमेंट आईडी दें, या क्या मैं आपकी अपॉइंटमेंट देखूँ?",
+		"chat.action.my_appointments": "मेरी अपॉइंटमेंट देखें",
+		"chat.action.enter_appt_id":   "अपॉइंटमेंट आईडी दर्ज करें",
+
+		// Opening hours and reception handoff
+		"hours.open_now":            "✅ हम अभी खुले हैं, %s तक।",
+		"hours.closed_now":          "🌙 हम अभी बंद हैं। हम फिर %s खुलेंगे।",
+		"hours.holiday_now":         "🏖️ आज %s के कारण हम बंद हैं। हम फिर %s खुलेंगे।",
+		"hours.closed_now_unknown":  "🌙 हम अभी बंद हैं।",
+		"hours.open_on":             "✅ %s को हम %s-%s खुले हैं।",
+		"hours.closed_on":           "🌙 %s को हम बंद हैं।",
+		"hours.holiday_on":          "🏖️ %s को %s के कारण हम बंद हैं।",
+		"hours.after_hours":         "🌙 हम अभी बंद हैं और फिर %s खुलेंगे। आप यहाँ अभी भी अपॉइंटमेंट बुक कर सकते हैं। आपात स्थिति में %s पर कॉल करें।",
+		"hours.after_hours_unknown": "🌙 हम अभी बंद हैं। आप यहाँ अभी भी अपॉइंटमेंट बुक कर सकते हैं। आपात स्थिति में %s पर कॉल करें।",
+		"hours.handoff":             "👩‍⚕️ मैंने हमारी रिसेप्शन टीम को इस चैट से जुड़ने के लिए कहा है। जल्द ही कोई जवाब देगा।",
+		"hours.handoff_closed":      "हमारी रिसेप्शन टीम फिर %s उपलब्ध होगी और तब जवाब देगी। तब तक मैं अपॉइंटमेंट बुक करने में मदद कर सकता हूँ।",
+		"hours.handoff_unavailable": "हमारी रिसेप्शन टीम अभी उपलब्ध नहीं है। तब तक मैं अपॉइंटमेंट बुक करने में मदद कर सकता हूँ।",
+
+		// Doctor directory
+		"doctors.body_departments":  "👩‍⚕️ हमारे डॉक्टरों से मिलें। विभाग चुनें:",
+		"doctors.department_button": "विभाग",
+		"doctors.body_department":   "👩‍⚕️ %s में हमारे डॉक्टर:",
+		"doctors.body_all":          "👩‍⚕️ हमारे डॉक्टर:",
+		"doctors.doctor_button":     "डॉक्टर देखें",
+		"doctors.empty":             "डॉक्टरों की सूची अभी उपलब्ध नहीं है। कृपया बाद में प्रयास करें।",
+		"doctors.not_found":         "क्षमा करें, वह डॉक्टर नहीं मिले।",
+		"doctors.qualifications":    "🎓 योग्यता: %s",
+		"doctors.languages":         "🗣️ भाषाएँ: %s",
+		"doctors.days":              "📅 परामर्श के दिन: %s",
+		"doctors.fee":               "💰 परामर्श शुल्क: %s",
+		"doctors.book":              "इनसे बुक करें",
+		"doctors.back":              "सभी डॉक्टर",
+
+		// Waitlist
+		"waitlist.offer_join":    "😔 अगले %d दिनों में कोई खाली स्लॉट नहीं है। क्या आप प्रतीक्षा सूची में शामिल होना चाहेंगे? स्लॉट खुलते ही हम आपको संदेश भेजेंगे।",
+		"waitlist.join_button":   "सूची में जुड़ें",
+		"waitlist.no_button":     "नहीं, धन्यवाद",
+		"waitlist.joined":        "✅ आप %s के लिए %s से %s तक की प्रतीक्षा सूची में हैं। स्लॉट खुलने पर हम आपको संदेश भेजेंगे।",
+		"waitlist.any_doctor":    "विभाग के किसी भी डॉक्टर",
+		"waitlist.offer":         "🎉 %s के साथ %s को %s बजे एक स्लॉट खाली हुआ है। यह %d मिनट तक आपके लिए रखा गया है। बुक करने के लिए \"अभी बुक करें\" दबाएँ या YES लिखें।",
+		"waitlist.book_button":   "अभी बुक करें",
+		"waitlist.skip_button":   "यह नहीं",
+		"waitlist.leave_button":  "सूची छोड़ें",
+		"waitlist.offer_expired": "⌛ आपके लिए रखा गया स्लॉट छोड़ दिया गया है। आप अभी भी प्रतीक्षा सूची में हैं।",
+		"waitlist.offer_gone":    "⌛ माफ़ कीजिए, यह ऑफ़र अब उपलब्ध नहीं है।",
+		"waitlist.skipped":       "👍 कोई बात नहीं, आप अभी भी प्रतीक्षा सूची में हैं।",
+		"waitlist.left":          "आपने प्रतीक्षा सूची छोड़ दी है।",
+
+		// Family members booked for from one number
+		"family.booking_for":          "👤 %s के लिए बुकिंग।",
+		"family.confirm":              "👋 फिर से स्वागत है! क्या बुकिंग %s के लिए है?",
+		"family.confirm_yes":          "हाँ",
+		"family.new_patient":          "👋 स्वागत है! इस नंबर से अभी कोई मरीज़ पंजीकृत नहीं है। क्या यह अपॉइंटमेंट आपके लिए है?",
+		"family.for_me":               "मेरे लिए",
+		"family.choose_body":          "👨‍👩‍👧 यह अपॉइंटमेंट किसके लिए है?",
+		"family.section":              "आपका परिवार",
+		"family.someone_else":         "कोई और",
+		"family.someone_else_desc":    "आईडी या फ़ोन से दूसरा मरीज़ खोजें",
+		"family.add":                  "परिवार का सदस्य जोड़ें",
+		"family.add_desc":             "नया मरीज़ पंजीकृत करें",
+		"family.ask_name":             "👤 कृपया परिवार के सदस्य का पूरा नाम दर्ज करें:",
+		"family.ask_relation":         "%s आपके क्या लगते हैं?",
+		"family.relation_button":      "रिश्ता चुनें",
+		"family.ask_dob":              "📅 कृपया %s की जन्मतिथि दर्ज करें (YYYY-MM-DD):",
+		"family.ask_address":          "🏠 कृपया %s का पता दर्ज करें:",
+		"family.relation.self":        "मैं स्वयं",
+		"family.relation.spouse":      "पति/पत्नी",
+		"family.relation.son":         "बेटा",
+		"family.relation.daughter":    "बेटी",
+		"family.relation.father":      "पिता",
+		"family.relation.mother":      "माता",
+		"family.relation.brother":     "भाई",
+		"family.relation.sister":      "बहन",
+		"family.relation.grandparent": "दादा-दादी/नाना-नानी",
+		"family.relation.other":       "अन्य",
+
+		// New patient registration
+		"registration.existing_body":    "🔎 हमें कुछ मौजूदा रिकॉर्ड मिले हैं जो %s के हो सकते हैं। क्या इनमें से कोई वही मरीज़ है?",
+		"registration.existing_button":  "रिकॉर्ड चुनें",
+		"registration.existing_section": "मौजूदा रिकॉर्ड",
+		"registration.none":             "इनमें से कोई नहीं",
+		"registration.none_desc":        "नए मरीज़ के रूप में पंजीकरण करें",
+		"registration.done":             "✅ %s पंजीकृत हो गए हैं। पेशेंट कोड: %s। कृपया इसे अगली बार के लिए संभाल कर रखें।",
+		"registration.found":            "✅ %s का मौजूदा रिकॉर्ड उपयोग किया जा रहा है, पेशेंट कोड %s।",
+		"registration.retry":            "⚠️ अभी पंजीकरण पूरा नहीं हो सका। क्या आप फिर से कोशिश करना चाहेंगे?",
+		"registration.retry_button":     "फिर कोशिश करें",
+		"registration.cancel_button":    "रद्द करें",
+		"registration.failed":           "⚠️ पंजीकरण अभी भी पूरा नहीं हो सका। हम दिए गए विवरण के साथ बुकिंग करेंगे और रिसेप्शन पंजीकरण पूरा करेगा।",
+		"registration.failed_later":     "⚠️ पंजीकरण पूरा नहीं हो सका। कृपया बाद में फिर कोशिश करें।",
+	},
+
+	Malayalam: {
+		"menu.body":            "👋 നമസ്കാരം! ഇന്ന് ഞങ്ങൾക്ക് നിങ്ങളെ എങ്ങനെ സഹായിക്കാനാകും?",
+		"menu.language_hint":   "🌐 ഭാഷ മാറ്റാൻ LANGUAGE എന്ന് ടൈപ്പ് ചെയ്യുക.",
+		"menu.doctors_hint":    "👩‍⚕️ ഞങ്ങളുടെ ഡോക്ടർമാരെ കാണാൻ DOCTORS എന്ന് മറുപടി നൽകുക.",
+		"menu.footer":          "ക്ലിനിക് സഹായം",
+		"menu.my_appointment":  "📅 എന്റെ ബുക്കിംഗ്",
+		"menu.new_appointment": "🆕 പുതിയ ബുക്കിംഗ്",
+		"menu.contact_us":      "📞 ബന്ധപ്പെടുക",
+		"menu.not_understood":  "🤔 ക്ഷമിക്കണം, എനിക്ക് മനസ്സിലായില്ല.",
+
+		"language.prompt":  "🌐 ദയവായി നിങ്ങളുടെ ഭാഷ തിരഞ്ഞെടുക്കുക:",
+		"language.button":  "ഭാഷ തിരഞ്ഞെടുക്കുക",
+		"language.section": "ഭാഷകൾ",
+		"language.changed": "✅ ഭാഷ മലയാളം ആക്കി.",
+
+		"booking.ask_consulted_before": "🩺 നിങ്ങൾ മുമ്പ് ഇവിടെ ഡോക്ടറെ കണ്ടിട്ടുണ്ടോ? (അതെ/ഇല്ല)",
+		"booking.ask_patient_id":       "📋 ദയവായി നിങ്ങളുടെ പേഷ്യന്റ് ഐഡി അല്ലെങ്കിൽ ഫോൺ നമ്പർ നൽകുക:",
+		"booking.ask_name":             "👤 ദയവായി നിങ്ങളുടെ മുഴുവൻ പേര് നൽകുക:",
+		"booking.ask_address":          "🏠 ദയവായി നിങ്ങളുടെ വിലാസം നൽകുക:",
+		"booking.ask_phone":            "📞 ദയവായി നിങ്ങളുടെ ഫോൺ നമ്പർ നൽകുക:",
+		"booking.invalid_phone":        "❌ ഇത് സാധുവായ ഫോൺ നമ്പറായി തോന്നുന്നില്ല. ദയവായി വീണ്ടും നൽകുക, ഉദാ. 98765 43210 അല്ലെങ്കിൽ +91 98765 43210:",
+		"booking.holiday_closed":       "🏖️ %s, %s കാരണം ഞങ്ങൾ അടച്ചിരിക്കും. ദയവായി മറ്റൊരു തീയതി നൽകുക (YYYY-MM-DD):",
+		"booking.ask_dob":              "📅 ദയവായി നിങ്ങളുടെ ജനനത്തീയതി നൽകുക (YYYY-MM-DD):",
+		"booking.invalid_dob":          "❌ ജനനത്തീയതി YYYY-MM-DD ആയി നൽകുക, ഉദാ. 2015-06-21.",
+		"booking.ask_date":             "📅 ദയവായി നിങ്ങൾക്ക് ഇഷ്ടമുള്ള തീയതി നൽകുക (YYYY-MM-DD):",
+		"booking.reply_yes_no":         "❌ ദയവായി അതെ അല്ലെങ്കിൽ ഇല്ല എന്ന് മറുപടി നൽകുക.",
+		"booking.patient_not_found":    "❌ രോഗിയെ കണ്ടെത്തിയില്ല. ദയവായി വീണ്ടും ശ്രമിക്കുക.",
+		"booking.patient_fetch_failed": "❌ രോഗിയുടെ വിവരങ്ങൾ ലഭ്യമായില്ല. ദയവായി വീണ്ടും ശ്രമിക്കുക.",
+		"booking.patients_header":      "📅 രോഗിയുടെ വിവരങ്ങൾ",
+		"booking.patients_body":        "അപ്പോയിന്റ്മെന്റിനായി ഒരു രോഗിയെ തിരഞ്ഞെടുക്കുക:",
+		"booking.patients_button":      "രോഗിയെ തിരഞ്ഞെടുക്കൂ",
+		"booking.patients_section":     "തിരയൽ ഫലങ്ങൾ",
+		"booking.patient_code":         "പേഷ്യന്റ് കോഡ്: %s",
+		"booking.department_body":      "ദയവായി ഒരു വിഭാഗം തിരഞ്ഞെടുക്കുക",
+		"booking.department_button":    "തിരഞ്ഞെടുക്കുക",
+		"booking.department_section":   "വിഭാഗങ്ങൾ",
+		"booking.doctors_header":       "👨‍⚕️ ലഭ്യമായ ഡോക്ടർമാർ",
+		"booking.doctors_body":         "താഴെയുള്ള പട്ടികയിൽ നിന്ന് ഒരു ഡോക്ടറെ തിരഞ്ഞെടുക്കുക:",
+		"booking.doctors_button":       "ഡോക്ടർ തിരഞ്ഞെടുക്കൂ",
+		"booking.doctors_section":      "ഡോക്ടർമാർ",
+		"booking.no_doctors":           "❌ തിരഞ്ഞെടുത്ത തീയതിയിൽ ഈ വിഭാഗത്തിൽ ഡോക്ടർമാർ ലഭ്യമല്ല.",
+		"booking.slots_header":         "⏰ ലഭ്യമായ സമയങ്ങൾ",
+		"booking.slots_page":           "പേജ് %d / %d",
+		"booking.slots_button":         "സമയം തിരഞ്ഞെടുക്കുക",
+		"booking.slots_section":        "സ്ലോട്ടുകൾ %d - %d",
+		"booking.slots_next":           "➡ അടുത്ത സ്ലോട്ടുകൾ",
+		"booking.no_slots":             "❌ ഈ ഡോക്ടർക്ക് ഒഴിവുള്ള സ്ലോട്ടുകൾ ഇല്ല.",
+		"booking.no_more_slots":        "✅ കൂടുതൽ സ്ലോട്ടുകൾ ഇല്ല.",
+		"booking.slot_taken":           "⚠️ ക്ഷമിക്കണം, ആ സ്ലോട്ട് ഇപ്പോൾ മറ്റൊരാൾ എടുത്തു. ദയവായി മറ്റൊന്ന് തിരഞ്ഞെടുക്കുക:",
+		"booking.slot_stale":           "⌛ ആ പട്ടിക പഴയതാണ്. ദയവായി ഇതിൽ നിന്ന് തിരഞ്ഞെടുക്കുക:",
+		"booking.created":              "✅ അപ്പോയിന്റ്മെന്റ് വിജയകരമായി സൃഷ്ടിച്ചു!",
+		"booking.reminder":             "⏰ ഓർമ്മപ്പെടുത്തൽ: %s-നൊപ്പം നിങ്ങളുടെ അപ്പോയിന്റ്മെന്റ് %s-ന് %s-നാണ്.",
+		"booking.create_error":         "⚠️ അപ്പോയിന്റ്മെന്റ് സൃഷ്ടിക്കാനായില്ല: %s",
+		"booking.create_rejected":      "⚠️ അപ്പോയിന്റ്മെന്റ് പരാജയപ്പെട്ടു: %s",
+		"booking.confirmed":            "✅ %s-മായി %s-ന് %s-ക്ക് അപ്പോയിന്റ്മെന്റ് ബുക്ക് ചെയ്തു",
+		"booking.failed":               "⚠️ അപ്പോയിന്റ്മെന്റ് ബുക്ക് ചെയ്യാനായില്ല. പിന്നീട് വീണ്ടും ശ്രമിക്കുക.",
+		"booking.noted":                "📝 രേഖപ്പെടുത്തി: %s",
+		"booking.first_available":      "⚡ ആദ്യം ലഭ്യമായത്",
+		"booking.first_available_desc": "ഏത് ഡോക്ടറുമായും ഏറ്റവും അടുത്ത സ്ലോട്ട്",
+		"booking.first_available_hint": "അല്ലെങ്കിൽ ആദ്യം ലഭ്യമായ സ്ലോട്ടിനായി FIRST എന്ന് മറുപടി നൽകുക.",
+		"booking.searching":            "🔎 ഏറ്റവും അടുത്ത ഒഴിവുള്ള സ്ലോട്ടുകൾ തിരയുന്നു…",
+		"booking.earliest_body":        "അടുത്ത %d ദിവസത്തിനുള്ളിലെ ഏറ്റവും അടുത്ത ഒഴിവുകൾ:",
+
+		"chat.emergency":              "🚨 അടിയന്തര സാഹചര്യം! ഉടൻ %s എന്ന നമ്പറിൽ വിളിക്കുക അല്ലെങ്കിൽ അടുത്തുള്ള അത്യാഹിത വിഭാഗത്തിൽ എത്തുക. അടിയന്തര സഹായത്തിന് ഞങ്ങളുടെ എമർജൻസി ലൈനിൽ വിളിക്കുക: %s",
+		"chat.action.call_emergency":  "എമർജൻസി കോൾ",
+		"chat.action.call_clinic_er":  "ക്ലിനിക് എമർജൻസി",
+		"chat.clinic_intro":           "%s-നെക്കുറിച്ചുള്ള വിവരങ്ങൾ:\n\n",
+		"chat.clinic_address":         "📍 വിലാസം: %s\n",
+		"chat.clinic_phone":           "📞 ഫോൺ: %s\n",
+		"chat.clinic_hours":           "🕐 സമയം: %s\n",
+		"chat.clinic_services":        "🏥 സേവനങ്ങൾ: %s\n",
+		"chat.clinic_departments":     "🩺 വിഭാഗങ്ങൾ: %s\n",
+		"chat.clinic_anything_else":   "\nനിങ്ങൾക്ക് മറ്റെന്തെങ്കിലും അറിയണോ?",
+		"chat.action.show_map":        "മാപ്പിൽ കാണുക",
+		"chat.action.call_clinic":     "ക്ലിനിക്കിൽ വിളിക്കുക",
+		"chat.action.book":            "അപ്പോയിന്റ്മെന്റ് ബുക്ക് ചെയ്യുക",
+		"chat.medical_unavailable":    "ക്ഷമിക്കണം, നിങ്ങളുടെ ആരോഗ്യ ചോദ്യം ഇപ്പോൾ പ്രോസസ്സ് ചെയ്യാൻ ബുദ്ധിമുട്ടുണ്ട്. ആരോഗ്യ പ്രശ്നങ്ങൾക്ക് ഞങ്ങളുടെ ഡോക്ടർമാരെ നേരിട്ട് കാണുന്നതാണ് ഏറ്റവും നല്ലത്. ഒരു അപ്പോയിന്റ്മെന്റ് ബുക്ക് ചെയ്യണോ?",
+		"chat.medical_disclaimer":     "\n\n⚠️ ശ്രദ്ധിക്കുക: ഈ വിവരങ്ങൾ വിദ്യാഭ്യാസ ആവശ്യത്തിന് മാത്രമുള്ളതാണ്. വ്യക്തിഗത വൈദ്യോപദേശത്തിന് ഞങ്ങളുടെ ഡോക്ടർമാരെ സമീപിക്കുക.",
+		"chat.sources":                "\n\n📄 ഉറവിടങ്ങൾ:\n%s",
+		"chat.knowledge_quote":        "ഞങ്ങളുടെ ക്ലിനിക് വിവരങ്ങൾ പ്രകാരം:\n\n%s",
+		"chat.action.consultation":    "കൺസൾട്ടേഷൻ ബുക്ക് ചെയ്യുക",
+		"chat.action.nurse":           "നഴ്സുമായി സംസാരിക്കുക",
+		"chat.action.view_doctors":    "ഞങ്ങളുടെ ഡോക്ടർമാർ",
+		"chat.good_morning":           "സുപ്രഭാതം",
+		"chat.good_afternoon":         "നമസ്കാരം",
+		"chat.good_evening":           "ശുഭ സന്ധ്യ",
+		"chat.greeting":               "%s! %s-ലേക്ക് സ്വാഗതം. ഇവയിൽ ഞാൻ നിങ്ങളെ സഹായിക്കാം:\n\n• 📅 അപ്പോയിന്റ്മെന്റ് ബുക്കിംഗ്\n• 🏥 ക്ലിനിക് വിവരങ്ങൾ\n• 💊 പൊതുവായ ആരോഗ്യ ചോദ്യങ്ങൾ\n• 🚨 അടിയന്തര സഹായം\n• 📋 നിങ്ങളുടെ അപ്പോയിന്റ്മെന്റുകൾ\n\nഇന്ന് ഞാൻ എങ്ങനെ സഹായിക്കണം?",
+		"chat.action.quick_book":      "അപ്പോയിന്റ്മെന്റ് ബുക്ക് ചെയ്യുക",
+		"chat.action.clinic_hours":    "ക്ലിനിക് സമയം",
+		"chat.action.emergency_help":  "അടിയന്തര സഹായം",
+		"chat.cancel_prompt":          "നിങ്ങളുടെ അപ്പോയിന്റ്മെന്റ് റദ്ദാക്കാൻ ഞാൻ സഹായിക്കാം. ദയവായി അപ്പോയിന്റ്മെന്റ് ഐഡി നൽകുക, അല്ലെങ്കിൽ വരാനിരിക്കുന്ന അപ്പോയിന്റ്മെന്റുകൾ ഞാൻ നോക്കട്ടെ.",
+		"chat.reschedule_prompt":      "നിങ്ങളുടെ അപ്പോയിന്റ്മെന്റ് മാറ്റാൻ ഞാൻ സഹായിക്കാം. ആദ്യം നിലവിലുള്ള അപ്പോയിന്റ്മെന്റ് കണ്ടെത്താം. ദയവായി അപ്പോയിന്റ്മെന്റ് ഐഡി നൽകുക, അല്ലെങ്കിൽ ഞാൻ നോക്കട്ടെ?",
+		"chat.action.my_appointments": "എന്റെ അപ്പോയിന്റ്മെന്റുകൾ",
+		"chat.action.enter_appt_id":   "അപ്പോയിന്റ്മെന്റ് ഐഡി നൽകുക",
+
+		// Opening hours and reception handoff
+		"hours.open_now":            "✅ ഞങ്ങൾ ഇപ്പോൾ തുറന്നിരിക്കുന്നു, %s വരെ.",
+		"hours.closed_now":          "🌙 ഞങ്ങൾ ഇപ്പോൾ അടച്ചിരിക്കുന്നു. വീണ്ടും %s തുറക്കും.",
+		"hours.holiday_now":         "🏖️ %s കാരണം ഇന്ന് ഞങ്ങൾ അടച്ചിരിക്കുന്നു. വീണ്ടും %s തുറക്കും.",
+		"hours.closed_now_unknown":  "🌙 ഞങ്ങൾ ഇപ്പോൾ അടച്ചിരിക്കുന്നു.",
+		"hours.open_on":             "✅ %s ഞങ്ങൾ %s-%s തുറന്നിരിക്കും.",
+		"hours.closed_on":           "🌙 %s ഞങ്ങൾ അടച്ചിരിക്കും.",
+		"hours.holiday_on":          "🏖️ %s, %s കാരണം ഞങ്ങൾ അടച്ചിരിക്കും.",
+		"hours.after_hours":         "🌙 ഞങ്ങൾ ഇപ്പോൾ അടച്ചിരിക്കുന്നു, വീണ്ടും %s തുറക്കും. നിങ്ങൾക്ക് ഇവിടെ അപ്പോയിന്റ്മെന്റ് ബുക്ക് ചെയ്യാം. അടിയന്തര സാഹചര്യത്തിൽ %s വിളിക്കുക.",
+		"hours.after_hours_unknown": "🌙 ഞങ്ങൾ ഇപ്പോൾ അടച്ചിരിക്കുന്നു. നിങ്ങൾക്ക് ഇവിടെ അപ്പോയിന്റ്മെന്റ് ബുക്ക് ചെയ്യാം. അടിയന്തര സാഹചര്യത്തിൽ %s വിളിക്കുക.",
+		"hours.handoff":             "👩‍⚕️ ഈ ചാറ്റിൽ ചേരാൻ ഞങ്ങളുടെ റിസപ്ഷൻ ടീമിനോട് ആവശ്യപ്പെട്ടിട്ടുണ്ട്. ഉടൻ ആരെങ്കിലും മറുപടി നൽകും.",
+		"hours.handoff_closed":      "ഞങ്ങളുടെ റിസപ്ഷൻ ടീം വീണ്ടും %s ലഭ്യമാകും, അപ്പോൾ മറുപടി നൽകും. അതുവരെ അപ്പോയിന്റ്മെന്റ് ബുക്ക് ചെയ്യാൻ ഞാൻ സഹായിക്കാം.",
+		"hours.handoff_unavailable": "ഞങ്ങളുടെ റിസപ്ഷൻ ടീം ഇപ്പോൾ ലഭ്യമല്ല. അതുവരെ അപ്പോയിന്റ്മെന്റ് ബുക്ക് ചെയ്യാൻ ഞാൻ സഹായിക്കാം.",
+
+		// Doctor directory
+		"doctors.body_departments":  "👩‍⚕️ ഞങ്ങളുടെ ഡോക്ടർമാരെ പരിചയപ്പെടൂ. വിഭാഗം തിരഞ്ഞെടുക്കുക:",
+		"doctors.department_button": "വിഭാഗങ്ങൾ",
+		"doctors.body_department":   "👩‍⚕️ %s വിഭാഗത്തിലെ ഡോക്ടർമാർ:",
+		"doctors.body_all":          "👩‍⚕️ ഞങ്ങളുടെ ഡോക്ടർമാർ:",
+		"doctors.doctor_button":     "ഡോക്ടർമാർ",
+		"doctors.empty":             "ഡോക്ടർമാരുടെ പട്ടിക ഇപ്പോൾ ലഭ്യമല്ല. ദയവായി പിന്നീട് ശ്രമിക്കുക.",
+		"doctors.not_found":         "ക്ഷമിക്കണം, ആ ഡോക്ടറെ കണ്ടെത്താനായില്ല.",
+		"doctors.qualifications":    "🎓 യോഗ്യതകൾ: %s",
+		"doctors.languages":         "🗣️ ഭാഷകൾ: %s",
+		"doctors.days":              "📅 കൺസൾട്ടിംഗ് ദിവസങ്ങൾ: %s",
+		"doctors.fee":               "💰 കൺസൾട്ടേഷൻ ഫീസ്: %s",
+		"doctors.book":              "ബുക്ക് ചെയ്യുക",
+		"doctors.back":              "എല്ലാ ഡോക്ടർമാരും",
+
+		// Waitlist
+		"waitlist.offer_join":    "😔 അടുത്ത %d ദിവസത്തിനുള്ളിൽ ഒഴിവുള്ള സ്ലോട്ടുകളില്ല. വെയ്റ്റിംഗ് ലിസ്റ്റിൽ ചേരണോ? സ്ലോട്ട് ഒഴിവാകുമ്പോൾ തന്നെ ഞങ്ങൾ സന്ദേശം അയയ്ക്കും.",
+		"waitlist.join_button":   "ലിസ്റ്റിൽ ചേരുക",
+		"waitlist.no_button":     "വേണ്ട, നന്ദി",
+		"waitlist.joined":        "✅ %s എന്നതിനായി %s മുതൽ %s വരെയുള്ള വെയ്റ്റിംഗ് ലിസ്റ്റിൽ നിങ്ങളുണ്ട്. സ്ലോട്ട് ഒഴിവാകുമ്പോൾ ഞങ്ങൾ സന്ദേശം അയയ്ക്കും.",
+		"waitlist.any_doctor":    "വിഭാഗത്തിലെ ഏത് ഡോക്ടറും",
+		"waitlist.offer":         "🎉 %s-നൊപ്പം %s-ന് %s-ന് ഒരു സ്ലോട്ട് ഒഴിവായി. %d മിനിറ്റ് ഇത് നിങ്ങൾക്കായി മാറ്റിവച്ചിരിക്കുന്നു. ബുക്ക് ചെയ്യാൻ \"ബുക്ക് ചെയ്യുക\" അമർത്തുക അല്ലെങ്കിൽ YES എന്ന് മറുപടി നൽകുക.",
+		"waitlist.book_button":   "ബുക്ക് ചെയ്യുക",
+		"waitlist.skip_button":   "ഇത് വേണ്ട",
+		"waitlist.leave_button":  "ലിസ്റ്റ് വിടുക",
+		"waitlist.offer_expired": "⌛ നിങ്ങൾക്കായി മാറ്റിവച്ച സ്ലോട്ട് ഒഴിവാക്കി. നിങ്ങൾ ഇപ്പോഴും വെയ്റ്റിംഗ് ലിസ്റ്റിലുണ്ട്.",
+		"waitlist.offer_gone":    "⌛ ക്ഷമിക്കണം, ആ ഓഫർ ഇപ്പോൾ ലഭ്യമല്ല.",
+		"waitlist.skipped":       "👍 കുഴപ്പമില്ല, നിങ്ങൾ ഇപ്പോഴും വെയ്റ്റിംഗ് ലിസ്റ്റിലുണ്ട്.",
+		"waitlist.left":          "നിങ്ങൾ വെയ്റ്റിംഗ് ലിസ്റ്റ് വിട്ടു.",
+
+		// Family members booked for from one number
+		"family.booking_for":          "👤 %s-നായി ബുക്ക് ചെയ്യുന്നു.",
+		"family.confirm":              "👋 വീണ്ടും സ്വാഗതം! %s-നായി ബുക്ക് ചെയ്യട്ടെ?",
+		"family.confirm_yes":          "അതെ",
+		"family.new_patient":          "👋 സ്വാഗതം! ഈ നമ്പറിൽ ഇതുവരെ ഒരു രോഗിയും രജിസ്റ്റർ ചെയ്തിട്ടില്ല. ഈ അപ്പോയിന്റ്മെന്റ് നിങ്ങൾക്കാണോ?",
+		"family.for_me":               "എനിക്ക്",
+		"family.choose_body":          "👨‍👩‍👧 ഈ അപ്പോയിന്റ്മെന്റ് ആർക്കുവേണ്ടിയാണ്?",
+		"family.section":              "നിങ്ങളുടെ കുടുംബം",
+		"family.someone_else":         "മറ്റൊരാൾ",
+		"family.someone_else_desc":    "ഐഡി അല്ലെങ്കിൽ ഫോൺ വഴി മറ്റൊരു രോഗിയെ കണ്ടെത്തുക",
+		"family.add":                  "കുടുംബാംഗത്തെ ചേർക്കുക",
+		"family.add_desc":             "പുതിയ രോഗിയെ രജിസ്റ്റർ ചെയ്യുക",
+		"family.ask_name":             "👤 കുടുംബാംഗത്തിന്റെ മുഴുവൻ പേര് നൽകുക:",
+		"family.ask_relation":         "%s നിങ്ങളുടെ ആരാണ്?",
+		"family.relation_button":      "ബന്ധം തിരഞ്ഞെടുക്കുക",
+		"family.ask_dob":              "📅 %s-ന്റെ ജനനത്തീയതി നൽകുക (YYYY-MM-DD):",
+		"family.ask_address":          "🏠 %s-ന്റെ വിലാസം നൽകുക:",
+		"family.relation.self":        "ഞാൻ തന്നെ",
+		"family.relation.spouse":      "ഭാര്യ/ഭർത്താവ്",
+		"family.relation.son":         "മകൻ",
+		"family.relation.daughter":    "മകൾ",
+		"family.relation.father":      "അച്ഛൻ",
+		"family.relation.mother":      "അമ്മ",
+		"family.relation.brother":     "സഹോദരൻ",
+		"family.relation.sister":      "സഹോദരി",
+		"family.relation.grandparent": "മുത്തച്ഛൻ/മുത്തശ്ശി",
+		"family.relation.other":       "മറ്റുള്ളവർ",
+
+		// New patient registration
+		"registration.existing_body":    "🔎 %s-ന്റേതാകാൻ സാധ്യതയുള്ള നിലവിലുള്ള രേഖകൾ കണ്ടെത്തി. ഇവയിൽ ഒന്നാണോ രോഗി?",
+		"registration.existing_button":  "രേഖ തിരഞ്ഞെടുക്കുക",
+		"registration.existing_section": "നിലവിലുള്ള രേഖകൾ",
+		"registration.none":             "ഇവയൊന്നുമല്ല",
+		"registration.none_desc":        "പുതിയ രോഗിയായി രജിസ്റ്റർ ചെയ്യുക",
+		"registration.done":             "✅ %s രജിസ്റ്റർ ചെയ്തു. പേഷ്യന്റ് കോഡ്: %s. അടുത്ത സന്ദർശനങ്ങൾക്കായി ഇത് സൂക്ഷിക്കുക.",
+		"registration.found":            "✅ %s-ന്റെ നിലവിലുള്ള രേഖ ഉപയോഗിക്കുന്നു, പേഷ്യന്റ് കോഡ് %s.",
+		"registration.retry":            "⚠️ ഇപ്പോൾ രജിസ്ട്രേഷൻ പൂർത്തിയാക്കാൻ കഴിഞ്ഞില്ല. വീണ്ടും ശ്രമിക്കണോ?",
+		"registration.retry_button":     "വീണ്ടും ശ്രമിക്കുക",
+		"registration.cancel_button":    "റദ്ദാക്കുക",
+		"registration.failed":           "⚠️ രജിസ്ട്രേഷൻ ഇപ്പോഴും പൂർത്തിയായില്ല. നൽകിയ വിവരങ്ങൾ ഉപയോഗിച്ച് ബുക്ക് ചെയ്യാം, റിസപ്ഷൻ രജിസ്ട്രേഷൻ പൂർത്തിയാക്കും.",
+		"registration.failed_later":     "⚠️ രജിസ്ട്രേഷൻ പൂർത്തിയാക്കാൻ കഴിഞ്ഞില്ല. ദയവായി പിന്നീട് വീണ്ടും ശ്രമിക്കുക.",
+	},
+
+	Tamil: {
+		"menu.body":            "👋 வணக்கம்! இன்று நாங்கள் உங்களுக்கு எப்படி உதவலாம்?",
+		"menu.language_hint":   "🌐 மொழியை மாற்ற LANGUAGE என தட்டச்சு செய்யவும்.",
+		"menu.doctors_hint":    "👩‍⚕️ எங்கள் மருத்துவர்களைப் பார்க்க DOCTORS என பதிலளிக்கவும்.",
+		"menu.footer":          "கிளினிக் உதவி",
+		"menu.my_appointment":  "📅 என் முன்பதிவு",
+		"menu.new_appointment": "🆕 புதிய முன்பதிவு",
+		"menu.contact_us":      "📞 தொடர்பு கொள்ள",
+		"menu.not_understood":  "🤔 மன்னிக்கவும், எனக்கு புரியவில்லை.",
+
+		"language.prompt":  "🌐 உங்கள் மொழியைத் தேர்ந்தெடுக்கவும்:",
+		"language.button":  "மொழி தேர்வு",
+		"language.section": "மொழிகள்",
+		"language.changed": "✅ மொழி தமிழாக மாற்றப்பட்டது.",
+
+		"booking.ask_consulted_before": "🩺 நீங்கள் இதற்கு முன் இங்கு ஆலோசனை பெற்றுள்ளீர்களா? (ஆம்/இல்லை)",
+		"booking.ask_patient_id":       "📋 உங்கள் நோயாளர் ஐடி அல்லது தொலைபேசி எண்ணை உள்ளிடவும்:",
+		"booking.ask_name":             "👤 உங்கள் முழுப் பெயரை உள்ளிடவும்:",
+		"booking.ask_address":          "🏠 உங்கள் முகவரியை உள்ளிடவும்:",
+		"booking.ask_phone":            "📞 உங்கள் தொலைபேசி எண்ணை உள்ளிடவும்:",
+		"booking.invalid_phone":        "❌ இது சரியான தொலைபேசி எண்ணாகத் தெரியவில்லை. மீண்டும் உள்ளிடவும், எ.கா. 98765 43210 அல்லது +91 98765 43210:",
+		"booking.holiday_closed":       "🏖️ %s அன்று %s காரணமாக மூடியிருப்போம். வேறு தேதியை உள்ளிடவும் (YYYY-MM-DD):",
+		"booking.ask_dob":              "📅 உங்கள் பிறந்த தேதியை உள்ளிடவும் (YYYY-MM-DD):",
+		"booking.invalid_dob":          "❌ பிறந்த தேதியை YYYY-MM-DD வடிவில் உள்ளிடவும், எ.கா. 2015-06-21.",
+		"booking.ask_date":             "📅 நீங்கள் விரும்பும் தேதியை உள்ளிடவும் (YYYY-MM-DD):",
+		"booking.reply_yes_no":         "❌ ஆம் அல்லது இல்லை என்று பதிலளிக்கவும்.",
+		"booking.patient_not_found":    "❌ நோயாளர் எவரும் கிடைக்கவில்லை. மீண்டும் முயற்சிக்கவும்.",
+		"booking.patient_fetch_failed": "❌ நோயாளர் விவரங்களைப் பெற முடியவில்லை. மீண்டும் முயற்சிக்கவும்.",
+		"booking.patients_header":      "📅 நோயாளர் விவரங்கள்",
+		"booking.patients_body":        "முன்பதிவுக்கு ஒரு நோயாளரைத் தேர்ந்தெடுக்கவும்:",
+		"booking.patients_button":      "நோயாளர் தேர்வு",
+		"booking.patients_section":     "தேடல் முடிவுகள்",
+		"booking.patient_code":         "நோயாளர் குறியீடு: %s",
+		"booking.department_body":      "ஒரு துறையைத் தேர்ந்தெடுக்கவும்",
+		"booking.department_button":    "தேர்வு",
+		"booking.department_section":   "துறைகள்",
+		"booking.doctors_header":       "👨‍⚕️ கிடைக்கும் மருத்துவர்கள்",
+		"booking.doctors_body":         "கீழே உள்ள பட்டியலிலிருந்து ஒரு மருத்துவரைத் தேர்ந்தெடுக்கவும்:",
+		"booking.doctors_button":       "மருத்துவர் தேர்வு",
+		"booking.doctors_section":      "மருத்துவர்கள்",
+		"booking.no_doctors":           "❌ தேர்ந்தெடுத்த தேதியில் இந்தத் துறையில் மருத்துவர்கள் இல்லை.",
+		"booking.slots_header":         "⏰ கிடைக்கும் நேரங்கள்",
+		"booking.slots_page":           "பக்கம் %d / %d",
+		"booking.slots_button":         "நேரம் தேர்வு",
+		"booking.slots_section":        "நேரங்கள் %d - %d",
+		"booking.slots_next":           "➡ அடுத்த நேரங்கள்",
+		"booking.no_slots":             "❌ இந்த மருத்துவருக்கு காலியான நேரங்கள் இல்லை.",
+		"booking.no_more_slots":        "✅ மேலும் நேரங்கள் இல்லை.",
+		"booking.slot_taken":           "⚠️ மன்னிக்கவும், அந்த நேரம் இப்போது வேறொருவரால் எடுக்கப்பட்டது. வேறொன்றைத் தேர்ந்தெடுக்கவும்:",
+		"booking.slot_stale":           "⌛ அந்தப் பட்டியல் பழையது. இதிலிருந்து தேர்ந்தெடுக்கவும்:",
+		"booking.created":              "✅ முன்பதிவு வெற்றிகரமாக உருவாக்கப்பட்டது!",
+		"booking.reminder":             "⏰ நினைவூட்டல்: %s உடன் உங்கள் முன்பதிவு %s அன்று %s மணிக்கு.",
+		"booking.create_error":         "⚠️ முன்பதிவை உருவாக்க முடியவில்லை: %s",
+		"booking.create_rejected":      "⚠️ முன்பதிவு தோல்வியடைந்தது: %s",
+		"booking.confirmed":            "✅ %s உடன் %s அன்று %s மணிக்கு முன்பதிவு செய்யப்பட்டது",
+		"booking.failed":               "⚠️ முன்பதிவு செய்ய முடியவில்லை. பின்னர் மீண்டும் முயற்சிக்கவும்.",
+		"booking.noted":                "📝 குறித்துக்கொண்டோம்: %s",
+		"booking.first_available":      "⚡ முதலில் கிடைப்பது",
+		"booking.first_available_desc": "எந்த மருத்துவருடனும் விரைவான நேரம்",
+		"booking.first_available_hint": "அல்லது விரைவில் கிடைக்கும் நேரத்திற்கு FIRST என பதிலளிக்கவும்.",
+		"booking.searching":            "🔎 விரைவில் கிடைக்கும் நேரங்களைத் தேடுகிறோம்…",
+		"booking.earliest_body":        "அடுத்த %d நாட்களில் விரைவில் கிடைக்கும் நேரங்கள்:",
+
+		"chat.emergency":              "🚨 அவசரநிலை! உடனடியாக %s ஐ அழைக்கவும் அல்லது அருகிலுள்ள அவசர சிகிச்சைப் பிரிவுக்குச் செல்லவும். உடனடி உதவிக்கு எங்கள் அவசர எண்ணை அழைக்கவும்: %s",
+		"chat.action.call_emergency":  "அவசர அழைப்பு",
+		"chat.action.call_clinic_er":  "கிளினிக் அவசர எண்",
+		"chat.clinic_intro":           "%s பற்றிய தகவல்:\n\n",
+		"chat.clinic_address":         "📍 முகவரி: %s\n",
+		"chat.clinic_phone":           "📞 தொலைபேசி: %s\n",
+		"chat.clinic_hours":           "🕐 நேரம்: %s\n",
+		"chat.clinic_services":        "🏥 சேவைகள்: %s\n",
+		"chat.clinic_departments":     "🩺 துறைகள்: %s\n",
+		"chat.clinic_anything_else":   "\nவேறு ஏதாவது தெரிந்துகொள்ள விரும்புகிறீர்களா?",
+		"chat.action.show_map":        "வரைபடத்தில் காண்க",
+		"chat.action.call_clinic":     "கிளினிக்கை அழைக்கவும்",
+		"chat.action.book":            "முன்பதிவு செய்யவும்",
+		"chat.medical_unavailable":    "மன்னிக்கவும், உங்கள் மருத்துவக் கேள்வியை இப்போது செயலாக்க முடியவில்லை. மருத்துவ சந்தேகங்களுக்கு எங்கள் மருத்துவர்களை நேரடியாக அணுகுவதே சிறந்தது. முன்பதிவு செய்ய விரும்புகிறீர்களா?",
+		"chat.medical_disclaimer":     "\n\n⚠️ குறிப்பு: இந்தத் தகவல் கல்வி நோக்கத்திற்காக மட்டுமே. தனிப்பட்ட மருத்துவ ஆலோசனைக்கு எங்கள் மருத்துவர்களை அணுகவும்.",
+		"chat.sources":                "\n\n📄 ஆதாரங்கள்:\n%s",
+		"chat.knowledge_quote":        "எங்கள் கிளினிக் தகவலின்படி:\n\n%s",
+		"chat.action.consultation":    "ஆலோசனை முன்பதிவு",
+		"chat.action.nurse":           "செவிலியருடன் பேசவும்",
+		"chat.action.view_doctors":    "எங்கள் மருத்துவர்கள்",
+		"chat.good_morning":           "காலை வணக்கம்",
+		"chat.good_afternoon":         "மதிய வணக்கம்",
+		"chat.good_evening":           "மாலை வணக்கம்",
+		"chat.greeting":               "%s! %s க்கு வரவேற்கிறோம். இவற்றில் நான் உங்களுக்கு உதவ முடியும்:\n\n• 📅 முன்பதிவு செய்தல்\n• 🏥 கிளினிக் தகவல்\n• 💊 பொதுவான சுகாதாரக் கேள்விகள்\n• 🚨 அவசர உதவி\n• 📋 உங்கள் முன்பதிவுகளை நிர்வகித்தல்\n\nஇன்று நான் உங்களுக்கு எப்படி உதவலாம்?",
+		"chat.action.quick_book":      "முன்பதிவு செய்யவும்",
+		"chat.action.clinic_hours":    "கிளினிக் நேரம்",
+		"chat.action.emergency_help":  "அவசர உதவி",
+		"chat.cancel_prompt":          "உங்கள் முன்பதிவை ரத்து செய்ய நான் உதவ முடியும். உங்கள் முன்பதிவு ஐடியை வழங்கவும் அல்லது உங்கள் வரவிருக்கும் முன்பதிவுகளை நான் பார்க்கட்டுமா?",
+		"chat.reschedule_prompt":      "உங்கள் முன்பதிவை மாற்ற நான் உதவ முடியும். முதலில் உங்கள் தற்போதைய முன்பதிவைக் கண்டறிவோம். உங்கள் முன்பதிவு ஐடியை வழங்கவும், அல்லது நான் பார்க்கட்டுமா?",
+		"chat.action.my_appointments": "என் முன்பதிவுகள்",
+		"chat.action.enter_appt_id":   "முன்பதிவு ஐடி உள்ளிடவும்",
+
+		// Opening hours and reception handoff
+		"hours.open_now":            "✅ நாங்கள் இப்போது திறந்திருக்கிறோம், %s வரை.",
+		"hours.closed_now":          "🌙 நாங்கள் இப்போது மூடியிருக்கிறோம். மீண்டும் %s திறப்போம்.",
+		"hours.holiday_now":         "🏖️ %s காரணமாக இன்று மூடியிருக்கிறோம். மீண்டும் %s திறப்போம்.",
+		"hours.closed_now_unknown":  "🌙 நாங்கள் இப்போது மூடியிருக்கிறோம்.",
+		"hours.open_on":             "✅ %s அன்று %s-%s திறந்திருப்போம்.",
+		"hours.closed_on":           "🌙 %s அன்று மூடியிருப்போம்.",
+		"hours.holiday_on":          "🏖️ %s அன்று %s காரணமாக மூடியிருப்போம்.",
+		"hours.after_hours":         "🌙 நாங்கள் இப்போது மூடியிருக்கிறோம், மீண்டும் %s திறப்போம். நீங்கள் இங்கே முன்பதிவு செய்யலாம். அவசரநிலையில் %s ஐ அழைக்கவும்.",
+		"hours.after_hours_unknown": "🌙 நாங்கள் இப்போது மூடியிருக்கிறோம். நீங்கள் இங்கே முன்பதிவு செய்யலாம். அவசரநிலையில் %s ஐ அழைக்கவும்.",
+		"hours.handoff":             "👩‍⚕️ இந்த உரையாடலில் சேர எங்கள் வரவேற்புக் குழுவைக் கேட்டுள்ளேன். விரைவில் ஒருவர் பதிலளிப்பார்.",
+		"hours.handoff_closed":      "எங்கள் வரவேற்புக் குழு மீண்டும் %s கிடைக்கும், அப்போது பதிலளிக்கும். அதுவரை முன்பதிவு செய்ய நான் உதவுகிறேன்.",
+		"hours.handoff_unavailable": "எங்கள் வரவேற்புக் குழு இப்போது கிடைக்கவில்லை. அதுவரை முன்பதிவு செய்ய நான் உதவுகிறேன்.",
+
+		// Doctor directory
+		"doctors.body_departments":  "👩‍⚕️ எங்கள் மருத்துவர்களைச் சந்தியுங்கள். துறையைத் தேர்ந்தெடுக்கவும்:",
+		"doctors.department_button": "துறைகள்",
+		"doctors.body_department":   "👩‍⚕️ %s துறையின் மருத்துவர்கள்:",
+		"doctors.body_all":          "👩‍⚕️ எங்கள் மருத்துவர்கள்:",
+		"doctors.doctor_button":     "மருத்துவர்கள்",
+		"doctors.empty":             "மருத்துவர் பட்டியல் இப்போது கிடைக்கவில்லை. பின்னர் முயற்சிக்கவும்.",
+		"doctors.not_found":         "மன்னிக்கவும், அந்த மருத்துவரைக் கண்டறிய முடியவில்லை.",
+		"doctors.qualifications":    "🎓 தகுதிகள்: %s",
+		"doctors.languages":         "🗣️ மொழிகள்: %s",
+		"doctors.days":              "📅 ஆலோசனை நாட்கள்: %s",
+		"doctors.fee":               "💰 ஆலோசனைக் கட்டணம்: %s",
+		"doctors.book":              "முன்பதிவு செய்",
+		"doctors.back":              "எல்லா மருத்துவர்கள்",
+
+		// Waitlist
+		"waitlist.offer_join":    "😔 அடுத்த %d நாட்களில் காலியான நேரம் இல்லை. காத்திருப்புப் பட்டியலில் சேர விரும்புகிறீர்களா? நேரம் கிடைத்தவுடன் உங்களுக்குச் செய்தி அனுப்புவோம்.",
+		"waitlist.join_button":   "பட்டியலில் சேர்",
+		"waitlist.no_button":     "வேண்டாம், நன்றி",
+		"waitlist.joined":        "✅ %s க்காக %s முதல் %s வரை காத்திருப்புப் பட்டியலில் உள்ளீர்கள். நேரம் கிடைத்ததும் செய்தி அனுப்புவோம்.",
+		"waitlist.any_doctor":    "துறையின் எந்த மருத்துவரும்",
+		"waitlist.offer":         "🎉 %s உடன் %s அன்று %s மணிக்கு ஒரு நேரம் காலியாகியுள்ளது. %d நிமிடங்களுக்கு இது உங்களுக்காக வைக்கப்பட்டுள்ளது. முன்பதிவு செய்ய \"இப்போது பதிவு\" என்பதைத் தட்டவும் அல்லது YES என பதிலளிக்கவும்.",
+		"waitlist.book_button":   "இப்போது பதிவு",
+		"waitlist.skip_button":   "இது வேண்டாம்",
+		"waitlist.leave_button":  "வெளியேறு",
+		"waitlist.offer_expired": "⌛ உங்களுக்காக வைக்கப்பட்ட நேரம் விடுவிக்கப்பட்டது. நீங்கள் இன்னும் காத்திருப்புப் பட்டியலில் உள்ளீர்கள்.",
+		"waitlist.offer_gone":    "⌛ மன்னிக்கவும், அந்த வாய்ப்பு இப்போது கிடைக்கவில்லை.",
+		"waitlist.skipped":       "👍 பரவாயில்லை, நீங்கள் இன்னும் காத்திருப்புப் பட்டியலில் உள்ளீர்கள்.",
+		"waitlist.left":          "நீங்கள் காத்திருப்புப் பட்டியலிலிருந்து விலகிவிட்டீர்கள்.",
+
+		// Family members booked for from one number
+		"family.booking_for":          "👤 %s க்காக முன்பதிவு செய்கிறோம்.",
+		"family.confirm":              "👋 மீண்டும் வருக! %s க்காக முன்பதிவு செய்யவா?",
+		"family.confirm_yes":          "ஆம்",
+		"family.new_patient":          "👋 வருக! இந்த எண்ணில் இன்னும் எந்த நோயாளியும் பதிவு செய்யப்படவில்லை. இந்த சந்திப்பு உங்களுக்கா?",
+		"family.for_me":               "எனக்கு",
+		"family.choose_body":          "👨‍👩‍👧 இந்த சந்திப்பு யாருக்காக?",
+		"family.section":              "உங்கள் குடும்பம்",
+		"family.someone_else":         "வேறொருவர்",
+		"family.someone_else_desc":    "ஐடி அல்லது தொலைபேசி மூலம் வேறு நோயாளியைத் தேடவும்",
+		"family.add":                  "குடும்ப உறுப்பினர் சேர்",
+		"family.add_desc":             "புதிய நோயாளியைப் பதிவு செய்யவும்",
+		"family.ask_name":             "👤 குடும்ப உறுப்பினரின் முழுப் பெயரை உள்ளிடவும்:",
+		"family.ask_relation":         "%s உங்களுக்கு என்ன உறவு?",
+		"family.relation_button":      "உறவைத் தேர்வுசெய்",
+		"family.ask_dob":              "📅 %s இன் பிறந்த தேதியை உள்ளிடவும் (YYYY-MM-DD):",
+		"family.ask_address":          "🏠 %s இன் முகவரியை உள்ளிடவும்:",
+		"family.relation.self":        "நானே",
+		"family.relation.spouse":      "கணவர்/மனைவி",
+		"family.relation.son":         "மகன்",
+		"family.relation.daughter":    "மகள்",
+		"family.relation.father":      "தந்தை",
+		"family.relation.mother":      "தாய்",
+		"family.relation.brother":     "சகோதரர்",
+		"family.relation.sister":      "சகோதரி",
+		"family.relation.grandparent": "தாத்தா/பாட்டி",
+		"family.relation.other":       "மற்றவர்",
+
+		// New patient registration
+		"registration.existing_body":    "🔎 %s உடையதாக இருக்கக்கூடிய பதிவுகள் கண்டறியப்பட்டன. இவற்றில் ஒன்று அந்த நோயாளியா?",
+		"registration.existing_button":  "பதிவைத் தேர்வுசெய்",
+		"registration.existing_section": "உள்ள பதிவுகள்",
+		"registration.none":             "இவை எதுவும் இல்லை",
+		"registration.none_desc":        "புதிய நோயாளியாகப் பதிவு செய்யவும்",
+		"registration.done":             "✅ %s பதிவு செய்யப்பட்டார். நோயாளர் குறியீடு: %s. அடுத்த வருகைகளுக்கு இதை வைத்துக்கொள்ளவும்.",
+		"registration.found":            "✅ %s இன் உள்ள பதிவு பயன்படுத்தப்படுகிறது, நோயாளர் குறியீடு %s.",
+		"registration.retry":            "⚠️ இப்போது பதிவை முடிக்க முடியவில்லை. மீண்டும் முயற்சிக்கவா?",
+		"registration.retry_button":     "மீண்டும் முயற்சி",
+		"registration.cancel_button":    "ரத்து செய்",
+		"registration.failed":           "⚠️ பதிவை இன்னும் முடிக்க முடியவில்லை. கொடுத்த விவரங்களுடன் முன்பதிவு செய்வோம், வரவேற்பு பதிவை முடிக்கும்.",
+		"registration.failed_later":     "⚠️ பதிவை முடிக்க முடியவில்லை. பின்னர் மீண்டும் முயற்சிக்கவும்.",
+	},
+}
@@ -0,0 +1,121 @@
+// middleware/auth.go
+package middleware
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "log"
+    "net/http"
+    "strings"
+    "time"
+    
+    "github.com/gin-gonic/gin"
+)
+
+// StaffKey is the context key holding the subject of a verified staff token
+const StaffKey = "staff"
+
+var errInvalidToken = errors.New("invalid token")
+
+// RequireAuth admits requests with a bearer JWT signed with HS256 and the
+// shared secret, and not expired. Without a secret every request is refused.
+func RequireAuth(secret string) gin.HandlerFunc {
+    if secret == "" {
+        log.Println("WARNING: JWT_SECRET is not set; admin routes will refuse every request")
+    }
+    
+    return func(c *gin.Context) {
+        token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+        if !ok || secret == "" {
+            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+            return
+        }
+        
+        subject, err := verifyToken(strings.TrimSpace(token), secret, time.Now())
+        if err != nil {
+            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+            return
+        }
+        
+        c.Set(StaffKey, subject)
+        c.Next()
+    }
+}
+
+// IssueToken signs an HS256 JWT for a staff member, valid for ttl. Staff get
+// theirs from the stafftoken command.
+func IssueToken(subject, secret string, ttl time.Duration, now time.Time) (string, error) {
+    if secret == "" {
+        return "", errors.New("JWT_SECRET is not set")
+    }
+    header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+    if err != nil {
+        return "", err
+    }
+    claims, err := json.Marshal(map[string]interface{}{
+        "sub": subject,
+        "iat": now.Unix(),
+        "exp": now.Add(ttl).Unix(),
+    })
+    if err != nil {
+        return "", err
+    }
+    
+    unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write([]byte(unsigned))
+    return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// verifyToken checks an HS256 JWT and returns its subject
+func verifyToken(token, secret string, now time.Time) (string, error) {
+    parts := strings.Split(token, ".")
+    if len(parts) != 3 {
+        return "", errInvalidToken
+    }
+    
+    var header struct {
+        Alg string `json:"alg"`
+    }
+    if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+        return "", errInvalidToken
+    }
+    
+    signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+    if err != nil {
+        return "", errInvalidToken
+    }
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write([]byte(parts[0] + "." + parts[1]))
+    if !hmac.Equal(signature, mac.Sum(nil)) {
+        return "", errInvalidToken
+    }
+    
+    var claims struct {
+        Subject   string `json:"sub"`
+        ExpiresAt *int64 `json:"exp"`
+        NotBefore *int64 `json:"nbf"`
+    }
+    if err := decodeSegment(parts[1], &claims); err != nil {
+        return "", errInvalidToken
+    }
+    // Tokens must expire
+    if claims.ExpiresAt == nil || now.Unix() >= *claims.ExpiresAt {
+        return "", errInvalidToken
+    }
+    if claims.NotBefore != nil && now.Unix() < *claims.NotBefore {
+        return "", errInvalidToken
+    }
+    return claims.Subject, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+    data, err := base64.RawURLEncoding.DecodeString(segment)
+    if err != nil {
+        return err
+    }
+    return json.Unmarshal(data, v)
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func signToken(t *testing.T, header, claims, secret string) string {
+	t.Helper()
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString([]byte(header)) + "." + enc.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyToken(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	hs256 := `{"alg":"HS256","typ":"JWT"}`
+
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr bool
+	}{
+		{"valid", signToken(t, hs256, `{"sub":"reception","exp":1800000060}`, "secret"), "reception", false},
+		{"expired", signToken(t, hs256, `{"sub":"reception","exp":1800000000}`, "secret"), "", true},
+		{"no expiry", signToken(t, hs256, `{"sub":"reception"}`, "secret"), "", true},
+		{"not yet valid", signToken(t, hs256, `{"sub":"reception","exp":1800000060,"nbf":1800000030}`, "secret"), "", true},
+		{"wrong secret", signToken(t, hs256, `{"sub":"reception","exp":1800000060}`, "other"), "", true},
+		{"alg none", signToken(t, `{"alg":"none"}`, `{"sub":"reception","exp":1800000060}`, "secret"), "", true},
+		{"malformed", "not.a-token", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifyToken(tt.token, "secret", now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verifyToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("verifyToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueToken(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	token, err := IssueToken("reception", "secret", time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := verifyToken(token, "secret", now.Add(59*time.Minute)); err != nil || got != "reception" {
+		t.Errorf("verifyToken() = %q, %v, want reception", got, err)
+	}
+	if _, err := verifyToken(token, "secret", now.Add(time.Hour)); err == nil {
+		t.Error("token accepted after it expired")
+	}
+}
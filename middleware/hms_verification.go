@@ -0,0 +1,48 @@
+// middleware/hms_verification.go
+package middleware
+
+import (
+    "bytes"
+    "crypto/hmac"
+    "io"
+    "log"
+    "net/http"
+    
+    "github.com/gin-gonic/gin"
+)
+
+// VerifyHMSSignature checks the X-HMS-Signature header HMS sends with its
+// webhook calls: "sha256=" and the hex HMAC-SHA256 of the body keyed with the
+// shared secret. Without a secret every call is rejected.
+func VerifyHMSSignature(secret string) gin.HandlerFunc {
+    if secret == "" {
+        log.Println("WARNING: HMS_WEBHOOK_SECRET is not set; HMS webhook calls will be rejected")
+    }
+    
+    return func(c *gin.Context) {
+        if secret == "" {
+            c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "HMS webhook is not configured"})
+            return
+        }
+        
+        signature := c.GetHeader("X-HMS-Signature")
+        if signature == "" {
+            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing signature"})
+            return
+        }
+        
+        body, err := io.ReadAll(c.Request.Body)
+        if err != nil {
+            c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read body"})
+            return
+        }
+        c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+        
+        if !hmac.Equal([]byte(signature), []byte("sha256="+calculateHMAC(body, secret))) {
+            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+            return
+        }
+        
+        c.Next()
+    }
+}
@@ -0,0 +1,58 @@
+// middleware/rate_limit.go
+package middleware
+
+import (
+    "net/http"
+    "sync"
+    "time"
+    
+    "github.com/gin-gonic/gin"
+)
+
+// RateLimit allows each client IP perMinute requests per minute and answers
+// 429 beyond that. A limit of zero or less disables it.
+func RateLimit(perMinute int) gin.HandlerFunc {
+    if perMinute <= 0 {
+        return func(c *gin.Context) { c.Next() }
+    }
+    
+    type window struct {
+        start time.Time
+        count int
+    }
+    var (
+        mu      sync.Mutex
+        clients = make(map[string]*window)
+        swept   time.Time
+    )
+    
+    return func(c *gin.Context) {
+        now := time.Now()
+        ip := c.ClientIP()
+        
+        mu.Lock()
+        // Drop clients whose window has passed, so the map doesn't grow
+        if now.Sub(swept) >= time.Minute {
+            for key, w := range clients {
+                if now.Sub(w.start) >= time.Minute {
+                    delete(clients, key)
+                }
+            }
+            swept = now
+        }
+        w, ok := clients[ip]
+        if !ok || now.Sub(w.start) >= time.Minute {
+            w = &window{start: now}
+            clients[ip] = w
+        }
+        w.count++
+        allowed := w.count <= perMinute
+        mu.Unlock()
+        
+        if !allowed {
+            c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+            return
+        }
+        c.Next()
+    }
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", RateLimit(2), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	get := func(ip string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if got := get("10.0.0.1"); got != want {
+			t.Errorf("request %d answered %d, want %d", i+1, got, want)
+		}
+	}
+	if got := get("10.0.0.2"); got != http.StatusOK {
+		t.Errorf("another client answered %d, want %d", got, http.StatusOK)
+	}
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CampaignStatus string
+
+const (
+	CampaignRunning   CampaignStatus = "running"
+	CampaignCompleted CampaignStatus = "completed"
+	CampaignCancelled CampaignStatus = "cancelled"
+)
+
+type RecipientStatus string
+
+const (
+	RecipientPending    RecipientStatus = "pending"
+	RecipientSent       RecipientStatus = "sent"
+	RecipientFailed     RecipientStatus = "failed"
+	RecipientSuppressed RecipientStatus = "suppressed"
+)
+
+// Campaign is a bulk template broadcast to a list of patients
+type Campaign struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Template    TemplateMessage    `bson:"template" json:"template"`
+	Source      string             `bson:"source" json:"source"` // manual, csv, hms
+	Status      CampaignStatus     `bson:"status" json:"status"`
+	Total       int                `bson:"total" json:"total"`
+	Sent        int                `bson:"sent" json:"sent"`
+	Failed      int                `bson:"failed" json:"failed"`
+	Suppressed  int                `bson:"suppressed" json:"suppressed"`
+	CreatedBy   string             `bson:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	StartedAt   *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// Pending returns the number of recipients not yet processed
+func (c Campaign) Pending() int {
+	return c.Total - c.Sent - c.Failed - c.Suppressed
+}
+
+// CampaignRecipient tracks delivery of a campaign to one phone number
+type CampaignRecipient struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CampaignID primitive.ObjectID `bson:"campaign_id" json:"campaign_id"`
+	Phone      string             `bson:"phone" json:"phone"`
+	Name       string             `bson:"name,omitempty" json:"name,omitempty"`
+	Params     []string           `bson:"params,omitempty" json:"params,omitempty"`
+	Status     RecipientStatus    `bson:"status" json:"status"`
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"`
+	SentAt     *time.Time         `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// CreateCampaignRequest starts a campaign from an explicit list or an HMS patient filter
+type CreateCampaignRequest struct {
+	Name       string              `json:"name" binding:"required"`
+	Template   TemplateMessage     `json:"template"`
+	Recipients []CampaignRecipient `json:"recipients"`
+	HMSFilter  *HMSPatientFilter   `json:"hms_filter,omitempty"`
+	CreatedBy  string              `json:"created_by"`
+}
+
+// HMSPatientFilter selects campaign recipients from the hospital system
+type HMSPatientFilter struct {
+	Search string `json:"search" binding:"required"` // name, patient code or phone fragment
+	MinAge int    `json:"min_age,omitempty"`
+	MaxAge int    `json:"max_age,omitempty"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ClinicProfile is the clinic's public information, edited by staff and used
+// by the chatbot and the WhatsApp menu. There is one profile per deployment.
+type ClinicProfile struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name         string             `bson:"name" json:"name" binding:"required"`
+	Addresses    []ClinicAddress    `bson:"addresses" json:"addresses"`
+	Phones       []ClinicPhone      `bson:"phones" json:"phones"`
+	Email        string             `bson:"email,omitempty" json:"email,omitempty"`
+	Website      string             `bson:"website,omitempty" json:"website,omitempty"`
+	Hours        []DayHours         `bson:"hours" json:"hours"`
+	StaffedHours []DayHours         `bson:"staffed_hours,omitempty" json:"staffed_hours,omitempty"` // when reception answers chats; empty means Hours
+	Holidays     []Holiday          `bson:"holidays,omitempty" json:"holidays,omitempty"`
+	Services     []string           `bson:"services" json:"services"`
+	Departments  []string           `bson:"departments" json:"departments"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ClinicAddress is one location of the clinic, e.g. the main building
+type ClinicAddress struct {
+	Label  string `bson:"label,omitempty" json:"label,omitempty"`
+	Line   string `bson:"line" json:"line"`
+	MapURL string `bson:"map_url,omitempty" json:"map_url,omitempty"`
+}
+
+// ClinicPhone is a published phone number, e.g. reception or pharmacy
+type ClinicPhone struct {
+	Label  string `bson:"label,omitempty" json:"label,omitempty"`
+	Number string `bson:"number" json:"number"`
+}
+
+// DayHours are the opening hours of one weekday. Times are HH:MM in the
+// clinic time zone; a weekday without an entry is closed. A Close before
+// Open is on the next day, e.g. 20:00-02:00.
+type DayHours struct {
+	Weekday string `bson:"weekday" json:"weekday"` // monday ... sunday
+	Open    string `bson:"open" json:"open"`
+	Close   string `bson:"close" json:"close"`
+}
+
+// Holiday is a date the clinic is closed
+type Holiday struct {
+	Date string `bson:"date" json:"date"` // YYYY-MM-DD
+	Name string `bson:"name" json:"name"`
+}
+
+// PrimaryAddress returns the first address, or an empty string
+func (p *ClinicProfile) PrimaryAddress() string {
+	if len(p.Addresses) == 0 {
+		return ""
+	}
+	return p.Addresses[0].Line
+}
+
+// PrimaryPhone returns the first phone number, or an empty string
+func (p *ClinicProfile) PrimaryPhone() string {
+	if len(p.Phones) == 0 {
+		return ""
+	}
+	return p.Phones[0].Number
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Contact is a WhatsApp user we have exchanged messages with
+type Contact struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Phone         string             `bson:"phone" json:"phone"`
+	LastInboundAt time.Time          `bson:"last_inbound_at" json:"last_inbound_at"`
+	OptedOut      bool               `bson:"opted_out" json:"opted_out"`
+	OptedOutAt    *time.Time         `bson:"opted_out_at,omitempty" json:"opted_out_at,omitempty"`
+	OptInSource   string             `bson:"opt_in_source,omitempty" json:"opt_in_source,omitempty"`
+	OptedInAt     *time.Time         `bson:"opted_in_at,omitempty" json:"opted_in_at,omitempty"`
+	ConsentLog    []ConsentEvent     `bson:"consent_log,omitempty" json:"consent_log,omitempty"`
+	// Whether the number has WhatsApp, cached from the contacts API or a
+	// rejected send
+	WhatsAppStatus    string     `bson:"whatsapp_status,omitempty" json:"whatsapp_status,omitempty"`
+	WhatsAppCheckedAt *time.Time `bson:"whatsapp_checked_at,omitempty" json:"whatsapp_checked_at,omitempty"`
+	CreatedAt         time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt         time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// Opt-in sources
+const (
+	OptInSourceInbound = "whatsapp_inbound" // user messaged us first
+	OptInSourceKeyword = "keyword"          // user sent START
+	OptInSourceAdmin   = "admin"            // recorded by staff (e.g. paper consent form)
+)
+
+// WhatsApp reachability statuses, as reported by the contacts API
+const (
+	WhatsAppValid   = "valid"
+	WhatsAppInvalid = "invalid"
+)
+
+// ConsentEvent is an audit entry for an opt-in or opt-out
+type ConsentEvent struct {
+	Action string    `bson:"action" json:"action"` // opt_in, opt_out
+	Source string    `bson:"source" json:"source"`
+	At     time.Time `bson:"at" json:"at"`
+}
+
+// UpdateConsentRequest is used by staff to record a consent change
+type UpdateConsentRequest struct {
+	Status string `json:"status" binding:"required,oneof=opted_in opted_out"`
+	Source string `json:"source"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CriticalMessageKind is the kind of patient message that must arrive even
+// when WhatsApp cannot deliver it
+type CriticalMessageKind string
+
+const (
+	CriticalConfirmation CriticalMessageKind = "confirmation"
+	CriticalReminder     CriticalMessageKind = "reminder"
+)
+
+// DeliveryState tracks a critical message across channels
+type DeliveryState string
+
+const (
+	DeliveryPending   DeliveryState = "pending"   // sent on WhatsApp, awaiting delivery
+	DeliveryDelivered DeliveryState = "delivered" // WhatsApp reported delivered or read
+	DeliverySMSSent   DeliveryState = "sms_sent"  // resent by SMS
+	DeliveryFailed    DeliveryState = "failed"    // no channel could deliver it
+)
+
+// CriticalMessage is a confirmation or reminder sent with SMS fallback
+type CriticalMessage struct {
+	ID                primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Kind              CriticalMessageKind `bson:"kind" json:"kind"`
+	To                string              `bson:"to" json:"to"`
+	Text              string              `bson:"text" json:"text"`
+	Status            DeliveryState       `bson:"status" json:"status"`
+	WhatsAppMessageID string              `bson:"whatsapp_message_id,omitempty" json:"whatsapp_message_id,omitempty"`
+	SMSMessageID      string              `bson:"sms_message_id,omitempty" json:"sms_message_id,omitempty"`
+	FallbackReason    string              `bson:"fallback_reason,omitempty" json:"fallback_reason,omitempty"`
+	Error             string              `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt         time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// AppointmentReminderRequest is sent by HMS when a patient should be reminded
+// of an appointment
+type AppointmentReminderRequest struct {
+	MobileNumber string `json:"mobileNumber" binding:"required"`
+	DoctorName   string `json:"doctorName" binding:"required"`
+	Date         string `json:"date" binding:"required"` // YYYY-MM-DD
+	Time         string `json:"time" binding:"required"` // as shown to the patient, e.g. "03:30 PM"
+}
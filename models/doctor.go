@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DoctorProfile is the public profile of an HMS doctor shown in the doctor
+// directory. HMS only knows names and departments; staff add the rest.
+type DoctorProfile struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EmployeeID     int                `bson:"employee_id" json:"employee_id" binding:"required"` // HMS employeeId
+	Name           string             `bson:"name" json:"name" binding:"required"`
+	DepartmentID   int                `bson:"department_id" json:"department_id" binding:"required"` // HMS departmentId
+	DepartmentName string             `bson:"department_name" json:"department_name"`
+	Qualifications []string           `bson:"qualifications,omitempty" json:"qualifications,omitempty"` // e.g. "MBBS", "MD (Cardiology)"
+	Languages      []string           `bson:"languages,omitempty" json:"languages,omitempty"`
+	ConsultingDays []string           `bson:"consulting_days,omitempty" json:"consulting_days,omitempty"` // monday ... sunday
+	Fee            float64            `bson:"fee,omitempty" json:"fee,omitempty"`
+	Currency       string             `bson:"currency,omitempty" json:"currency,omitempty"`   // e.g. "INR"
+	PhotoURL       string             `bson:"photo_url,omitempty" json:"photo_url,omitempty"` // public HTTPS image for WhatsApp cards
+	Bio            string             `bson:"bio,omitempty" json:"bio,omitempty"`
+	Hidden         bool               `bson:"hidden" json:"hidden"` // left out of the directory
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// DoctorDepartment is a department that has doctors in the directory
+type DoctorDepartment struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AlertStatus is the state of an emergency alert
+type AlertStatus string
+
+const (
+	AlertOpen         AlertStatus = "open"
+	AlertAcknowledged AlertStatus = "acknowledged"
+)
+
+// EmergencyAlert is raised when a patient message is classified as an
+// emergency. It is re-sent to on-call staff until someone acknowledges it.
+type EmergencyAlert struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Code            string             `bson:"code" json:"code"` // short code staff reply with, e.g. "ACK 3f2a1c"
+	Channel         MessageChannel     `bson:"channel" json:"channel"`
+	SessionID       string             `bson:"session_id" json:"session_id"`
+	Contact         string             `bson:"contact" json:"contact"` // patient phone or user ID
+	Message         string             `bson:"message" json:"message"`
+	Status          AlertStatus        `bson:"status" json:"status"`
+	EscalationLevel int                `bson:"escalation_level" json:"escalation_level"`
+	LastNotifiedAt  time.Time          `bson:"last_notified_at" json:"last_notified_at"`
+	Deliveries      []AlertDelivery    `bson:"deliveries,omitempty" json:"deliveries,omitempty"`
+	AcknowledgedBy  string             `bson:"acknowledged_by,omitempty" json:"acknowledged_by,omitempty"`
+	AcknowledgedAt  *time.Time         `bson:"acknowledged_at,omitempty" json:"acknowledged_at,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// AlertDelivery records one notification attempt for an alert
+type AlertDelivery struct {
+	Level     int       `bson:"level" json:"level"`
+	Channel   string    `bson:"channel" json:"channel"`
+	Recipient string    `bson:"recipient" json:"recipient"`
+	Error     string    `bson:"error,omitempty" json:"error,omitempty"`
+	At        time.Time `bson:"at" json:"at"`
+}
+
+type AcknowledgeAlertRequest struct {
+	Agent string `json:"agent" binding:"required"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// Time windows a patient can ask for ("tomorrow evening")
+const (
+	TimeWindowMorning   = "morning"
+	TimeWindowAfternoon = "afternoon"
+	TimeWindowEvening   = "evening"
+)
+
+// ExtractedEntities are booking details found in a free-text message
+type ExtractedEntities struct {
+	Department  string   `json:"department,omitempty"`  // canonical name, e.g. "Cardiology"
+	DoctorName  string   `json:"doctor_name,omitempty"` // as written, without the "Dr" prefix
+	Date        string   `json:"date,omitempty"`        // YYYY-MM-DD in clinic time
+	TimeWindow  string   `json:"time_window,omitempty"`
+	PatientName string   `json:"patient_name,omitempty"`
+	Symptoms    []string `json:"symptoms,omitempty"`
+}
+
+// IsEmpty reports whether nothing was extracted
+func (e ExtractedEntities) IsEmpty() bool {
+	return e.Department == "" && e.DoctorName == "" && e.Date == "" &&
+		e.TimeWindow == "" && e.PatientName == "" && len(e.Symptoms) == 0
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Household is the HMS patients a WhatsApp number books for, e.g. a parent
+// and their children. Patients are added as they are verified or registered.
+type Household struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Phone     string             `bson:"phone" json:"phone"` // WhatsApp number
+	Members   []HouseholdMember  `bson:"members" json:"members"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// HouseholdMember is a patient booked for from a household's number
+type HouseholdMember struct {
+	PatientID   int       `bson:"patient_id" json:"patient_id"` // HMS patientId
+	PatientCode string    `bson:"patient_code" json:"patient_code"`
+	Name        string    `bson:"name" json:"name"`
+	Relation    string    `bson:"relation,omitempty" json:"relation,omitempty"` // e.g. "daughter"; see i18n.Relations
+	DateOfBirth string    `bson:"date_of_birth,omitempty" json:"date_of_birth,omitempty"`
+	Address     string    `bson:"address,omitempty" json:"address,omitempty"`
+	PhoneNumber string    `bson:"phone_number,omitempty" json:"phone_number,omitempty"`
+	AddedAt     time.Time `bson:"added_at" json:"added_at"`
+}
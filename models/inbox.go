@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ConversationStatus is the staff-facing state of a conversation
+type ConversationStatus string
+
+const (
+	ConversationOpen     ConversationStatus = "open"
+	ConversationResolved ConversationStatus = "resolved"
+)
+
+// ConversationNote is an internal note left by reception staff
+type ConversationNote struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	Author    string             `bson:"author" json:"author"`
+	Text      string             `bson:"text" json:"text"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// InboxFilter narrows down the conversations listed in the staff inbox
+type InboxFilter struct {
+	Status        ConversationStatus
+	Channel       MessageChannel
+	Intent        MessageIntent
+	AssignedAgent string // agent name, or "none" for unassigned
+	UnreadOnly    bool
+	Tag           string
+	Page          int
+	Limit         int
+}
+
+// Inbox API request bodies
+type AssignConversationRequest struct {
+	Agent string `json:"agent" binding:"required"`
+}
+
+type AddNoteRequest struct {
+	Author string `json:"author" binding:"required"`
+	Text   string `json:"text" binding:"required"`
+}
+
+type AddTagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
+
+type ResolveConversationRequest struct {
+	Agent string `json:"agent"`
+}
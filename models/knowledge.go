@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// KnowledgeDocument is a FAQ or policy document staff uploaded for the
+// chatbot to answer from, e.g. accepted insurance or test preparation
+type KnowledgeDocument struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Title      string             `bson:"title" json:"title"`
+	Source     string             `bson:"source,omitempty" json:"source,omitempty"` // file name or URL shown when citing
+	Content    string             `bson:"content" json:"content"`
+	ChunkCount int                `bson:"chunk_count" json:"chunk_count"`
+	Embedded   bool               `bson:"embedded" json:"embedded"` // all chunks have embeddings
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// KnowledgeChunk is a passage of a document, the unit of retrieval
+type KnowledgeChunk struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	DocumentID primitive.ObjectID `bson:"document_id" json:"document_id"`
+	Title      string             `bson:"title" json:"title"`
+	Source     string             `bson:"source,omitempty" json:"source,omitempty"`
+	Index      int                `bson:"index" json:"index"`
+	Text       string             `bson:"text" json:"text"`
+	Embedding  []float32          `bson:"embedding,omitempty" json:"-"`
+}
+
+// CreateKnowledgeDocumentRequest adds a document from JSON
+type CreateKnowledgeDocumentRequest struct {
+	Title   string `json:"title" binding:"required"`
+	Source  string `json:"source"`
+	Content string `json:"content" binding:"required"`
+}
+
+// KnowledgePassage is a retrieved chunk with its relevance score
+type KnowledgePassage struct {
+	DocumentID primitive.ObjectID `json:"document_id"`
+	Title      string             `json:"title"`
+	Source     string             `json:"source,omitempty"`
+	Text       string             `json:"text"`
+	Score      float64            `json:"score"`
+}
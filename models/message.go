@@ -1,6 +1,7 @@
 package models
 
 import (
+    "encoding/json"
     "time"
     "go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -13,6 +14,7 @@ const (
     IntentClinicInfo     MessageIntent = "clinic_info"
     IntentEmergency      MessageIntent = "emergency"
     IntentGreeting       MessageIntent = "greeting"
+    IntentHandoff        MessageIntent = "handoff" // patient asked for clinic staff
     IntentUnknown        MessageIntent = "unknown"
 )
 
@@ -22,6 +24,7 @@ type MessageChannel string
 const (
     ChannelWeb      MessageChannel = "web"
     ChannelWhatsApp MessageChannel = "whatsapp"
+    ChannelSMS      MessageChannel = "sms"
 )
 
 // Update Message struct to include channel information
@@ -44,6 +47,7 @@ type ChatRequest struct {
     SessionID string                 `json:"session_id" binding:"required"`
     UserID    string                 `json:"user_id,omitempty"`
     Channel   MessageChannel         `json:"channel,omitempty"`
+    Locale    string                 `json:"locale,omitempty"`
     Metadata  map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -51,10 +55,12 @@ type ChatRequest struct {
 type ChatResponse struct {
     Response     string                 `json:"response"`
     Intent       MessageIntent          `json:"intent"`
+    IntentConfidence float64            `json:"intent_confidence,omitempty"`
     Actions      []Action               `json:"actions,omitempty"`
     Data         map[string]interface{} `json:"data,omitempty"`
     ResponseType ResponseType           `json:"response_type,omitempty"`
     Interactive  *InteractiveMessage    `json:"interactive,omitempty"`
+    Locale       string                 `json:"locale,omitempty"`
 }
 
 // ResponseType for different message types
@@ -116,6 +122,19 @@ type Media struct {
     Caption string `json:"caption,omitempty"`
 }
 
+// MarshalJSON puts media under the key WhatsApp expects for the header type,
+// e.g. {"type": "image", "image": {"link": "..."}}
+func (h MessageHeader) MarshalJSON() ([]byte, error) {
+    out := map[string]interface{}{"type": h.Type}
+    if h.Text != "" {
+        out["text"] = h.Text
+    }
+    if h.Media != nil && h.Type != "text" {
+        out[h.Type] = h.Media
+    }
+    return json.Marshal(out)
+}
+
 type InteractiveAction struct {
     Buttons  []InteractiveButton  `json:"buttons,omitempty"`
     Button   string              `json:"button,omitempty"` // For list messages
@@ -256,6 +275,21 @@ type ConversationSession struct {
     LastActivity     time.Time             `bson:"last_activity" json:"last_activity"`
     CreatedAt        time.Time             `bson:"created_at" json:"created_at"`
     ExpiresAt        time.Time             `bson:"expires_at" json:"expires_at"`
+
+    // Staff inbox fields
+    Status           ConversationStatus    `bson:"status" json:"status"`
+    AssignedAgent    string                `bson:"assigned_agent,omitempty" json:"assigned_agent,omitempty"`
+    Tags             []string              `bson:"tags,omitempty" json:"tags,omitempty"`
+    Notes            []ConversationNote    `bson:"notes,omitempty" json:"notes,omitempty"`
+    UnreadCount      int                   `bson:"unread_count" json:"unread_count"`
+    LastIntent       MessageIntent         `bson:"last_intent,omitempty" json:"last_intent,omitempty"`
+    LastMessage      string                `bson:"last_message,omitempty" json:"last_message,omitempty"`
+    ResolvedAt       *time.Time            `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+    ResolvedBy       string                `bson:"resolved_by,omitempty" json:"resolved_by,omitempty"`
+
+    // Conversation language; LocaleExplicit is set when the patient chose it
+    Locale           string                `bson:"locale,omitempty" json:"locale,omitempty"`
+    LocaleExplicit   bool                  `bson:"locale_explicit,omitempty" json:"locale_explicit,omitempty"`
 }
 
 // Appointment-related models for WhatsApp interactions
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SlotRef identifies an appointment slot of an HMS doctor
+type SlotRef struct {
+	DoctorID uint   `bson:"doctor_id" json:"doctor_id"`
+	Date     string `bson:"date" json:"date"` // YYYY-MM-DD
+	Time     string `bson:"time" json:"time"` // "03:04 PM"
+}
+
+// SlotHold keeps a slot out of other patients' slot lists while one patient
+// is choosing it. Mongo removes it once ExpiresAt has passed.
+type SlotHold struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SlotRef   `bson:",inline"`
+	HolderID  string    `bson:"holder_id" json:"holder_id"` // session ID, e.g. "whatsapp:<phone>"
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MessageTemplate mirrors a WhatsApp Business message template
+type MessageTemplate struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	TemplateID string              `bson:"template_id" json:"template_id"`
+	Name       string              `bson:"name" json:"name"`
+	Language   string              `bson:"language" json:"language"`
+	Category   string              `bson:"category" json:"category"`
+	Status     string              `bson:"status" json:"status"` // APPROVED, PENDING, REJECTED...
+	Components []TemplateComponent `bson:"components" json:"components"`
+	SyncedAt   time.Time           `bson:"synced_at" json:"synced_at"`
+}
+
+type TemplateComponent struct {
+	Type    string                 `bson:"type" json:"type"`                         // HEADER, BODY, FOOTER, BUTTONS
+	Format  string                 `bson:"format,omitempty" json:"format,omitempty"` // TEXT, IMAGE, VIDEO, DOCUMENT, LOCATION
+	Text    string                 `bson:"text,omitempty" json:"text,omitempty"`
+	Buttons []TemplateButton       `bson:"buttons,omitempty" json:"buttons,omitempty"`
+	Example map[string]interface{} `bson:"example,omitempty" json:"example,omitempty"`
+}
+
+type TemplateButton struct {
+	Type        string `bson:"type" json:"type"` // QUICK_REPLY, URL, PHONE_NUMBER
+	Text        string `bson:"text" json:"text"`
+	URL         string `bson:"url,omitempty" json:"url,omitempty"`
+	PhoneNumber string `bson:"phone_number,omitempty" json:"phone_number,omitempty"`
+}
+
+// CreateTemplateRequest is submitted to the WhatsApp Business Management API
+type CreateTemplateRequest struct {
+	Name       string              `json:"name" binding:"required"`
+	Language   string              `json:"language" binding:"required"`
+	Category   string              `json:"category" binding:"required"` // MARKETING, UTILITY, AUTHENTICATION
+	Components []TemplateComponent `json:"components" binding:"required"`
+}
+
+// TemplateMessage describes a template send with all its parameters
+type TemplateMessage struct {
+	Name       string                `json:"name"`
+	Language   string                `json:"language,omitempty"`
+	Header     *TemplateHeaderParam  `json:"header,omitempty"`
+	BodyParams []string              `json:"body_params,omitempty"`
+	Buttons    []TemplateButtonParam `json:"buttons,omitempty"`
+}
+
+// TemplateHeaderParam fills a text or media header
+type TemplateHeaderParam struct {
+	Type     string `json:"type"` // text, image, video, document
+	Text     string `json:"text,omitempty"`
+	Link     string `json:"link,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// TemplateButtonParam fills a dynamic button (URL suffix or quick reply payload)
+type TemplateButtonParam struct {
+	Index   int    `json:"index"`
+	SubType string `json:"sub_type"` // url, quick_reply
+	Param   string `json:"param"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WaitlistStatus is the state of a waitlist entry
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting WaitlistStatus = "waiting"
+	WaitlistOffered WaitlistStatus = "offered" // a slot is held for the patient
+	WaitlistBooked  WaitlistStatus = "booked"
+	WaitlistLeft    WaitlistStatus = "left"    // the patient left the waitlist
+	WaitlistExpired WaitlistStatus = "expired" // the date range passed without a slot
+)
+
+// WaitlistEntry is a patient waiting for a slot with a doctor, or with any
+// doctor of a department, between two dates. Patients are offered opened
+// slots in the order they joined.
+type WaitlistEntry struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Phone        string             `bson:"phone" json:"phone"` // WhatsApp number offers are sent to
+	Locale       string             `bson:"locale,omitempty" json:"locale,omitempty"`
+	DepartmentID uint               `bson:"department_id" json:"department_id"`
+	DoctorID     uint               `bson:"doctor_id" json:"doctor_id"` // 0 for any doctor in the department
+	DoctorName   string             `bson:"doctor_name,omitempty" json:"doctor_name,omitempty"`
+	FromDate     string             `bson:"from_date" json:"from_date"` // YYYY-MM-DD
+	ToDate       string             `bson:"to_date" json:"to_date"`
+	TimeWindow   string             `bson:"time_window,omitempty" json:"time_window,omitempty"`
+
+	// Patient the appointment is booked for
+	PatientID   int    `bson:"patient_id,omitempty" json:"patient_id,omitempty"`
+	PatientCode string `bson:"patient_code,omitempty" json:"patient_code,omitempty"`
+	PatientName string `bson:"patient_name" json:"patient_name"`
+	Address     string `bson:"address,omitempty" json:"address,omitempty"`
+	PhoneNumber string `bson:"phone_number,omitempty" json:"phone_number,omitempty"`
+	DateOfBirth string `bson:"date_of_birth,omitempty" json:"date_of_birth,omitempty"`
+
+	Status    WaitlistStatus `bson:"status" json:"status"`
+	Offer     *WaitlistOffer `bson:"offer,omitempty" json:"offer,omitempty"`
+	Passed    []SlotRef      `bson:"passed,omitempty" json:"passed,omitempty"` // offers skipped or let expire
+	CreatedAt time.Time      `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `bson:"updated_at" json:"updated_at"`
+}
+
+// WaitlistOffer is an opened slot held for a waitlisted patient until ExpiresAt
+type WaitlistOffer struct {
+	SlotRef        `bson:",inline"`
+	DoctorName     string    `bson:"doctor_name" json:"doctor_name"`
+	Token          uint      `bson:"token" json:"token"`
+	AvailabilityID int       `bson:"availability_id,omitempty" json:"availability_id,omitempty"`
+	OfferedAt      time.Time `bson:"offered_at" json:"offered_at"`
+	ExpiresAt      time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// SlotOpenedRequest is sent by HMS when an appointment is cancelled, so the
+// freed slot can be offered to the waitlist right away
+type SlotOpenedRequest struct {
+	DoctorID uint   `json:"doctorId" binding:"required"`
+	Date     string `json:"date" binding:"required"` // YYYY-MM-DD
+}
@@ -0,0 +1,39 @@
+// Package email sends email notifications using config.EmailConfig.
+package email
+
+import (
+	"context"
+	"strings"
+
+	"clinic-chatbot-backend/config"
+)
+
+// Email is a message with a plain-text body and an optional HTML
+// alternative
+type Email struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender delivers email through a provider
+type Sender interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// New returns the sender for cfg.Provider, or nil when the provider is not
+// configured. "sendgrid" needs an API key; "smtp" (the default) needs a host.
+func New(cfg config.EmailConfig) Sender {
+	switch strings.ToLower(cfg.Provider) {
+	case "sendgrid":
+		if sender := NewSendGridSender(cfg); sender != nil {
+			return sender
+		}
+	case "", "smtp":
+		if sender := NewSMTPSender(cfg); sender != nil {
+			return sender
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"clinic-chatbot-backend/config"
+)
+
+const sendGridAPIURL = "https://api.sendgrid.com/v3/mail/send"
+
+// SendGridSender sends email through the SendGrid v3 HTTP API
+type SendGridSender struct {
+	cfg        config.EmailConfig
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewSendGridSender returns nil when no API key is configured
+func NewSendGridSender(cfg config.EmailConfig) *SendGridSender {
+	if cfg.APIKey == "" {
+		return nil
+	}
+	return &SendGridSender{
+		cfg:        cfg,
+		endpoint:   sendGridAPIURL,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridRequest struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+}
+
+// Send implements Sender
+func (s *SendGridSender) Send(ctx context.Context, email Email) error {
+	if len(email.To) == 0 {
+		return fmt.Errorf("email has no recipients")
+	}
+
+	payload := sendGridRequest{
+		From:    sendGridAddress{Email: s.cfg.FromEmail, Name: s.cfg.FromName},
+		Subject: email.Subject,
+		// SendGrid requires text/plain before text/html
+		Content: []sendGridContent{{Type: "text/plain", Value: email.Text}},
+	}
+	if email.HTML != "" {
+		payload.Content = append(payload.Content, sendGridContent{Type: "text/html", Value: email.HTML})
+	}
+	var to sendGridPersonalization
+	for _, addr := range email.To {
+		to.To = append(to.To, sendGridAddress{Email: addr})
+	}
+	payload.Personalizations = []sendGridPersonalization{to}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.cfg.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sendgrid send failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("sendgrid API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
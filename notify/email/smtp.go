@@ -0,0 +1,176 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"clinic-chatbot-backend/config"
+)
+
+// SMTPSender sends email through an SMTP server. Port 465 uses implicit TLS;
+// other ports upgrade with STARTTLS when the server offers it, so a local
+// sink without TLS (e.g. MailHog on port 1025) works too.
+type SMTPSender struct {
+	cfg config.EmailConfig
+}
+
+// NewSMTPSender returns nil when no SMTP host is configured
+func NewSMTPSender(cfg config.EmailConfig) *SMTPSender {
+	if cfg.SMTPHost == "" {
+		return nil
+	}
+	return &SMTPSender{cfg: cfg}
+}
+
+// Send implements Sender
+func (s *SMTPSender) Send(ctx context.Context, email Email) error {
+	if len(email.To) == 0 {
+		return fmt.Errorf("email has no recipients")
+	}
+
+	msg, err := s.buildMessage(email)
+	if err != nil {
+		return err
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("smtp connect failed: %w", err)
+	}
+	defer client.Close()
+
+	if err := s.deliver(client, email.To, msg); err != nil {
+		return fmt.Errorf("smtp send failed: %w", err)
+	}
+	return client.Quit()
+}
+
+func (s *SMTPSender) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.cfg.SMTPHost, fmt.Sprint(s.cfg.SMTPPort))
+	dialer := &net.Dialer{Timeout: 15 * time.Second}
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.SMTPPort == 465 {
+		conn = tls.Client(conn, &tls.Config{ServerName: s.cfg.SMTPHost})
+	}
+	// Bound the whole conversation by the context deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+func (s *SMTPSender) deliver(client *smtp.Client, to []string, msg []byte) error {
+	if ok, _ := client.Extension("STARTTLS"); ok && s.cfg.SMTPPort != 465 {
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.SMTPHost}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.SMTPHost)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.cfg.FromEmail); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := client.Rcpt(addr); err != nil {
+			return fmt.Errorf("recipient %s: %w", addr, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// buildMessage renders the RFC 5322 message. With an HTML body it is sent
+// as multipart/alternative so clients without HTML show the text part.
+func (s *SMTPSender) buildMessage(email Email) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+
+	from := s.cfg.FromEmail
+	if s.cfg.FromName != "" {
+		from = fmt.Sprintf("%s <%s>", mime.QEncoding.Encode("utf-8", s.cfg.FromName), s.cfg.FromEmail)
+	}
+	header("From", from)
+	header("To", strings.Join(email.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+
+	if email.HTML == "" {
+		header("Content-Type", "text/plain; charset=UTF-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		return buf.Bytes(), writeQuotedPrintable(&buf, email.Text)
+	}
+
+	boundary, err := newBoundary()
+	if err != nil {
+		return nil, err
+	}
+	header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+	buf.WriteString("\r\n")
+
+	parts := []struct{ contentType, body string }{
+		{"text/plain", email.Text},
+		{"text/html", email.HTML},
+	}
+	for _, part := range parts {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=UTF-8\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, part.body); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(buf *bytes.Buffer, body string) error {
+	w := quotedprintable.NewWriter(buf)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func newBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
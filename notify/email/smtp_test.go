@@ -0,0 +1,197 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"clinic-chatbot-backend/config"
+)
+
+// smtpSink is a minimal in-process SMTP server that records one message,
+// standing in for a local sink such as MailHog
+type smtpSink struct {
+	listener net.Listener
+	from     string
+	to       []string
+	data     chan string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	sink := &smtpSink{listener: listener, data: make(chan string, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go sink.serve()
+	return sink
+}
+
+func (s *smtpSink) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpSink) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 sink ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimSpace(line)
+		switch upper := strings.ToUpper(cmd); {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.to = append(s.to, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 end with .")
+			var body strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				body.WriteString(strings.TrimPrefix(line, "."))
+			}
+			s.data <- body.String()
+			reply("250 queued")
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPSenderDeliversTemplatedEmail(t *testing.T) {
+	sink := newSMTPSink(t)
+	sender := NewSMTPSender(config.EmailConfig{
+		SMTPHost:  "127.0.0.1",
+		SMTPPort:  sink.port(),
+		FromEmail: "noreply@clinic.test",
+		FromName:  "Test Clinic",
+	})
+
+	msg, err := Render(TemplateAppointmentConfirmation, AppointmentConfirmation{
+		ClinicName:   "Test Clinic",
+		Source:       "WhatsApp",
+		PatientName:  "Asha <script>",
+		PatientPhone: "919876543210",
+		DoctorName:   "Dr Rao",
+		Date:         "2026-10-20",
+		TimeSlot:     "10:30 AM",
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	msg.To = []string{"frontdesk@clinic.test"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sender.Send(ctx, msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	var raw string
+	select {
+	case raw = <-sink.data:
+	case <-ctx.Done():
+		t.Fatal("sink received no message")
+	}
+
+	if sink.from != "noreply@clinic.test" || len(sink.to) != 1 || sink.to[0] != "frontdesk@clinic.test" {
+		t.Errorf("envelope = %s -> %v", sink.from, sink.to)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if want := "Appointment confirmed: Asha <script> with Dr Rao on 2026-10-20"; subject != want {
+		t.Errorf("subject = %q, want %q", subject, want)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q (%v)", mediaType, err)
+	}
+
+	parts := map[string]string{}
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		body, _ := io.ReadAll(part) // quoted-printable is decoded by NextPart
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(body)
+	}
+
+	if !strings.Contains(parts["text/plain"], "Time:     10:30 AM") {
+		t.Errorf("text part missing slot:\n%s", parts["text/plain"])
+	}
+	if !strings.Contains(parts["text/html"], "Asha &lt;script&gt;") {
+		t.Errorf("html part not escaped:\n%s", parts["text/html"])
+	}
+}
+
+func TestNewPicksConfiguredProvider(t *testing.T) {
+	cases := []struct {
+		cfg  config.EmailConfig
+		want string
+	}{
+		{config.EmailConfig{Provider: "smtp", SMTPHost: "localhost", SMTPPort: 1025}, "*email.SMTPSender"},
+		{config.EmailConfig{Provider: "sendgrid", APIKey: "key"}, "*email.SendGridSender"},
+		{config.EmailConfig{Provider: "sendgrid"}, "<nil>"},
+		{config.EmailConfig{Provider: "ses", SMTPHost: "localhost"}, "<nil>"},
+	}
+	for _, c := range cases {
+		if got := typeName(New(c.cfg)); got != c.want {
+			t.Errorf("New(%s) = %s, want %s", c.cfg.Provider, got, c.want)
+		}
+	}
+}
+
+func typeName(s Sender) string {
+	switch s.(type) {
+	case *SMTPSender:
+		return "*email.SMTPSender"
+	case *SendGridSender:
+		return "*email.SendGridSender"
+	case nil:
+		return "<nil>"
+	}
+	return "unknown"
+}
@@ -0,0 +1,72 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Template names
+const (
+	TemplateAppointmentConfirmation = "appointment_confirmation"
+	TemplateStaffAlert              = "staff_alert"
+)
+
+// Each template is a pair of files: <name>.txt, which also defines the
+// "subject" template, and <name>.html
+//
+//go:embed templates/*.txt templates/*.html
+var templateFS embed.FS
+
+// AppointmentConfirmation is the data for TemplateAppointmentConfirmation
+type AppointmentConfirmation struct {
+	ClinicName   string
+	Source       string // e.g. "WhatsApp"
+	PatientName  string
+	PatientCode  string
+	PatientPhone string
+	DoctorName   string
+	Date         string
+	TimeSlot     string
+	Symptoms     string
+}
+
+// StaffAlert is the data for TemplateStaffAlert
+type StaffAlert struct {
+	ClinicName string
+	Subject    string
+	Text       string
+}
+
+// Render executes a template and returns the email without recipients
+func Render(name string, data any) (Email, error) {
+	text, err := texttemplate.ParseFS(templateFS, "templates/"+name+".txt")
+	if err != nil {
+		return Email{}, fmt.Errorf("email template %s: %w", name, err)
+	}
+	html, err := htmltemplate.ParseFS(templateFS, "templates/"+name+".html")
+	if err != nil {
+		return Email{}, fmt.Errorf("email template %s: %w", name, err)
+	}
+
+	var subject, textBody, htmlBody bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Email{}, fmt.Errorf("email template %s subject: %w", name, err)
+	}
+	if err := text.Execute(&textBody, data); err != nil {
+		return Email{}, fmt.Errorf("email template %s text: %w", name, err)
+	}
+	if err := html.Execute(&htmlBody, data); err != nil {
+		return Email{}, fmt.Errorf("email template %s html: %w", name, err)
+	}
+
+	return Email{
+		// Header injection guard; subjects come from user-supplied data
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    textBody.String(),
+		HTML:    htmlBody.String(),
+	}, nil
+}
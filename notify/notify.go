@@ -0,0 +1,76 @@
+// Package notify delivers staff and patient notifications over pluggable
+// channels (WhatsApp, email, SMS).
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Channel names
+const (
+	ChannelWhatsApp = "whatsapp"
+	ChannelEmail    = "email"
+	ChannelSMS      = "sms"
+)
+
+// Message is a channel-neutral notification. Channels without a subject
+// line prepend it to the text.
+type Message struct {
+	Subject string
+	Text    string
+}
+
+// Notifier delivers a message to one address over a single channel
+type Notifier interface {
+	Channel() string
+	Notify(ctx context.Context, to string, msg Message) error
+}
+
+// Recipient is an address on a channel, e.g. {"email", "oncall@clinic.com"}
+type Recipient struct {
+	Channel string `bson:"channel" json:"channel"`
+	Address string `bson:"address" json:"address"`
+}
+
+// Dispatcher routes messages to the notifier of each recipient's channel
+type Dispatcher struct {
+	notifiers map[string]Notifier
+}
+
+// NewDispatcher registers notifiers by channel
+func NewDispatcher(notifiers ...Notifier) *Dispatcher {
+	d := &Dispatcher{notifiers: make(map[string]Notifier)}
+	for _, n := range notifiers {
+		d.notifiers[n.Channel()] = n
+	}
+	return d
+}
+
+// Has reports whether a notifier is registered for the channel
+func (d *Dispatcher) Has(channel string) bool {
+	_, ok := d.notifiers[channel]
+	return ok
+}
+
+// Send delivers the message to one recipient
+func (d *Dispatcher) Send(ctx context.Context, to Recipient, msg Message) error {
+	n, ok := d.notifiers[to.Channel]
+	if !ok {
+		return fmt.Errorf("no notifier configured for channel %q", to.Channel)
+	}
+	return n.Notify(ctx, to.Address, msg)
+}
+
+// SendAll delivers the message to every recipient and returns the failures
+// joined together; one failing channel does not stop the others
+func (d *Dispatcher) SendAll(ctx context.Context, recipients []Recipient, msg Message) error {
+	var errs []error
+	for _, to := range recipients {
+		if err := d.Send(ctx, to, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", to.Channel, to.Address, err))
+		}
+	}
+	return errors.Join(errs...)
+}
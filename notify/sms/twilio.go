@@ -0,0 +1,118 @@
+// Package sms sends SMS notifications using config.SMSConfig.
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"clinic-chatbot-backend/config"
+	"clinic-chatbot-backend/notify"
+)
+
+const twilioAPIURL = "https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json"
+
+// TwilioSender sends SMS through the Twilio Messages API
+type TwilioSender struct {
+	cfg        config.SMSConfig
+	httpClient *http.Client
+}
+
+// NewTwilioSender returns nil when Twilio credentials are missing
+func NewTwilioSender(cfg config.SMSConfig) *TwilioSender {
+	if cfg.AccountSID == "" || cfg.AuthToken == "" || cfg.FromNumber == "" {
+		return nil
+	}
+	return &TwilioSender{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Send delivers an SMS and returns the provider message ID. Numbers
+// without a leading + are taken to include the country code, as WhatsApp
+// IDs do.
+func (s *TwilioSender) Send(ctx context.Context, to, body string) (string, error) {
+	if !strings.HasPrefix(to, "+") {
+		to = "+" + to
+	}
+
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", s.cfg.FromNumber)
+	form.Set("Body", body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(twilioAPIURL, s.cfg.AccountSID), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(s.cfg.AccountSID, s.cfg.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("sms send failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("sms API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		SID string `json:"sid"`
+	}
+	_ = json.Unmarshal(respBody, &result)
+	return result.SID, nil
+}
+
+// Channel implements notify.Notifier
+func (s *TwilioSender) Channel() string {
+	return notify.ChannelSMS
+}
+
+// Notify implements notify.Notifier
+func (s *TwilioSender) Notify(ctx context.Context, to string, msg notify.Message) error {
+	text := msg.Text
+	if msg.Subject != "" {
+		text = msg.Subject + "\n" + text
+	}
+	_, err := s.Send(ctx, to, text)
+	return err
+}
+
+// ValidateSignature checks the X-Twilio-Signature of an inbound webhook: an
+// HMAC-SHA1 of the public webhook URL followed by the sorted POST parameters,
+// keyed with the auth token
+func (s *TwilioSender) ValidateSignature(webhookURL string, params url.Values, signature string) bool {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(webhookURL)
+	for _, k := range keys {
+		for _, v := range params[k] {
+			b.WriteString(k)
+			b.WriteString(v)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(s.cfg.AuthToken))
+	mac.Write([]byte(b.String()))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,165 @@
+// Package phone parses phone numbers with per-country rules and formats
+// them as E.164.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid is returned for input that is not a valid phone number
+var ErrInvalid = errors.New("invalid phone number")
+
+// country holds the numbering rules of one region
+type country struct {
+	code        string // calling code without +
+	trunk       string // national prefix dropped when dialling from abroad, e.g. "0"
+	lengths     []int  // valid national significant number lengths
+	firstDigits string // digits a national number may start with, empty for any
+}
+
+// countries are the regions the clinic expects to hear from. Numbers from
+// other calling codes are accepted with only the E.164 length check.
+var countries = map[string]country{
+	"IN": {code: "91", trunk: "0", lengths: []int{10}, firstDigits: "123456789"},
+	"US": {code: "1", trunk: "1", lengths: []int{10}, firstDigits: "23456789"},
+	"CA": {code: "1", trunk: "1", lengths: []int{10}, firstDigits: "23456789"},
+	"GB": {code: "44", trunk: "0", lengths: []int{10}, firstDigits: "123578"},
+	"AE": {code: "971", trunk: "0", lengths: []int{8, 9}, firstDigits: "2345679"},
+	"SA": {code: "966", trunk: "0", lengths: []int{9}, firstDigits: "15"},
+	"QA": {code: "974", lengths: []int{8}, firstDigits: "34567"},
+	"OM": {code: "968", lengths: []int{8}, firstDigits: "279"},
+	"KW": {code: "965", lengths: []int{8}, firstDigits: "124569"},
+	"BH": {code: "973", lengths: []int{8}, firstDigits: "136"},
+	"SG": {code: "65", lengths: []int{8}, firstDigits: "3689"},
+	"MY": {code: "60", trunk: "0", lengths: []int{9, 10}, firstDigits: "13456789"},
+	"LK": {code: "94", trunk: "0", lengths: []int{9}, firstDigits: "123456789"},
+	"AU": {code: "61", trunk: "0", lengths: []int{9}, firstDigits: "23478"},
+	"NZ": {code: "64", trunk: "0", lengths: []int{8, 9, 10}, firstDigits: "234679"},
+}
+
+// Number is a parsed phone number
+type Number struct {
+	CountryCode string // calling code without +, e.g. "91"; empty when not in the table
+	National    string // national significant number, or every digit when CountryCode is empty
+	Region      string // ISO country; +1 numbers are reported as US
+}
+
+// E164 formats the number as +<country code><national number>
+func (n Number) E164() string {
+	return "+" + n.Digits()
+}
+
+// Digits is E164 without the +, the form WhatsApp uses for IDs
+func (n Number) Digits() string {
+	return n.CountryCode + n.National
+}
+
+// Format returns the national number for callers in region, otherwise E164
+func (n Number) Format(region string) string {
+	if c, ok := countries[strings.ToUpper(region)]; ok && c.code == n.CountryCode {
+		return n.National
+	}
+	return n.E164()
+}
+
+// Parse reads a number written nationally ("098765 43210"), internationally
+// ("+91 98765 43210", "0091...") or as WhatsApp digits ("919876543210").
+// Numbers without a country code are read in defaultRegion.
+func Parse(raw, defaultRegion string) (Number, error) {
+	s := strings.TrimSpace(raw)
+	international := strings.HasPrefix(s, "+")
+
+	var digits strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' || r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+			// separators
+		default:
+			return Number{}, fmt.Errorf("%w: unexpected %q", ErrInvalid, r)
+		}
+	}
+	d := digits.String()
+	if strings.HasPrefix(d, "00") && !international {
+		d, international = d[2:], true
+	}
+
+	if international {
+		return parseInternational(d)
+	}
+
+	region := strings.ToUpper(defaultRegion)
+	c, ok := countries[region]
+	if !ok {
+		return Number{}, fmt.Errorf("%w: unknown region %q", ErrInvalid, defaultRegion)
+	}
+
+	national := d
+	if c.trunk != "" && strings.HasPrefix(national, c.trunk) && !c.valid(national) {
+		national = national[len(c.trunk):]
+	}
+	if c.valid(national) {
+		return Number{CountryCode: c.code, National: national, Region: region}, nil
+	}
+
+	// Already carries a country code but no + (WhatsApp IDs, copied numbers).
+	// Only known codes count: otherwise any mistyped local number would pass
+	// as an international one.
+	if n, err := parseInternational(d); err == nil && n.CountryCode != "" {
+		return n, nil
+	}
+	return Number{}, fmt.Errorf("%w: %q is not a valid %s number", ErrInvalid, raw, region)
+}
+
+// parseInternational splits digits that start with a calling code
+func parseInternational(d string) (Number, error) {
+	if len(d) < 8 || len(d) > 15 {
+		return Number{}, fmt.Errorf("%w: E.164 numbers have 8 to 15 digits", ErrInvalid)
+	}
+
+	// Calling codes are prefix-free, so at most one length matches
+	for size := 1; size <= 3; size++ {
+		code := d[:size]
+		region, ok := regionByCode[code]
+		if !ok {
+			continue
+		}
+		national := d[size:]
+		if !countries[region].valid(national) {
+			return Number{}, fmt.Errorf("%w: not a valid +%s number", ErrInvalid, code)
+		}
+		return Number{CountryCode: code, National: national, Region: region}, nil
+	}
+
+	// Calling code outside the table: trust the E.164 length alone
+	return Number{National: d}, nil
+}
+
+// regionByCode maps a calling code to its region. Regions sharing a code
+// have the same rules; the alphabetically last one wins (US over CA).
+var regionByCode = func() map[string]string {
+	m := make(map[string]string)
+	for region, c := range countries {
+		if region > m[c.code] {
+			m[c.code] = region
+		}
+	}
+	return m
+}()
+
+func (c country) valid(national string) bool {
+	lengthOK := false
+	for _, l := range c.lengths {
+		if len(national) == l {
+			lengthOK = true
+			break
+		}
+	}
+	if !lengthOK {
+		return false
+	}
+	return c.firstDigits == "" || strings.IndexByte(c.firstDigits, national[0]) >= 0
+}
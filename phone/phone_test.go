@@ -0,0 +1,77 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        string
+		region     string
+		wantE164   string
+		wantRegion string
+		wantErr    bool
+	}{
+		// National forms in the default region
+		{"local", "98765 43210", "IN", "+919876543210", "IN", false},
+		{"local with trunk 0", "098765-43210", "IN", "+919876543210", "IN", false},
+		{"UK trunk 0", "07700 900123", "GB", "+447700900123", "GB", false},
+		{"US punctuation", "(415) 555-0100", "US", "+14155550100", "US", false},
+		{"US trunk 1", "1 415 555 0100", "US", "+14155550100", "US", false},
+		{"region is case-insensitive", "9876543210", "in", "+919876543210", "IN", false},
+
+		// International forms
+		{"plus", "+91 98765 43210", "US", "+919876543210", "IN", false},
+		{"00 prefix", "0091 98765 43210", "US", "+919876543210", "IN", false},
+		{"WhatsApp digits", "919876543210", "IN", "+919876543210", "IN", false},
+		{"three-digit code", "+971 50 123 4567", "IN", "+971501234567", "AE", false},
+		{"shared +1 reported as US", "+1 416 555 0100", "IN", "+14165550100", "US", false},
+		{"unknown calling code", "+999 1234 5678", "IN", "+99912345678", "", false},
+
+		// Invalid input
+		{"too short", "12345", "IN", "", "", true},
+		{"too long", "98765432109", "IN", "", "", true},
+		{"bad first digit", "+91 09876 54321", "IN", "", "", true},
+		{"wrong length for code", "+91 98765 4321", "IN", "", "", true},
+		{"E.164 too short", "+999 1234", "IN", "", "", true},
+		{"E.164 too long", "+999 1234 5678 9012 3", "IN", "", "", true},
+		{"letters", "98765 4321O", "IN", "", "", true},
+		{"unknown region", "9876543210", "XX", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.raw, tt.region)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("Parse(%q, %q) = %+v, %v; want ErrInvalid", tt.raw, tt.region, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q, %q): %v", tt.raw, tt.region, err)
+			}
+			if got.E164() != tt.wantE164 || got.Region != tt.wantRegion {
+				t.Errorf("Parse(%q, %q) = %s (%s), want %s (%s)",
+					tt.raw, tt.region, got.E164(), got.Region, tt.wantE164, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestNumberFormat(t *testing.T) {
+	n, err := Parse("+91 98765 43210", "IN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := n.Format("IN"); got != "9876543210" {
+		t.Errorf("Format(IN) = %q, want the national number", got)
+	}
+	if got := n.Format("GB"); got != "+919876543210" {
+		t.Errorf("Format(GB) = %q, want E.164", got)
+	}
+	if got := n.Digits(); got != "919876543210" {
+		t.Errorf("Digits() = %q", got)
+	}
+}
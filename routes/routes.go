@@ -1,24 +1,77 @@
 package routes
 
 import (
+    "context"
+    
     "github.com/gin-gonic/gin"
+    "clinic-chatbot-backend/config"
     "clinic-chatbot-backend/controllers"
+    "clinic-chatbot-backend/middleware"
+    "clinic-chatbot-backend/notify"
+    "clinic-chatbot-backend/notify/sms"
     "clinic-chatbot-backend/services"
-    // "clinic-chatbot-backend/middleware"
     // "clinic-chatbot-backend/database"
-    // "clinic-chatbot-backend/config"
 )
 
 func SetupRoutes(router *gin.Engine) {
+    cfg := config.Get()
+    
     // Initialize services
     aiService := services.NewAIService()
-    chatbotService := services.NewChatbotService(aiService)
-    whatsappService := services.NewWhatsAppService()
+    inboxService := services.NewInboxService()
+    contactService := services.NewContactService()
+    whatsappService := services.NewWhatsAppService(contactService, cfg.Region)
+    mailService := services.NewMailService(cfg.Email)
+    
+    // Staff notification channels; email and SMS only when configured
+    notifiers := []notify.Notifier{services.NewWhatsAppNotifier(whatsappService, cfg.Emergency.StaffTemplate)}
+    if mailService.Enabled() {
+        notifiers = append(notifiers, mailService)
+    }
+    smsSender := sms.NewTwilioSender(cfg.SMS)
+    if smsSender != nil {
+        notifiers = append(notifiers, smsSender)
+    }
+    emergencyService := services.NewEmergencyService(cfg.Emergency, notify.NewDispatcher(notifiers...))
+    
+    clinicService := services.NewClinicService()
+    knowledgeService := services.NewKnowledgeService(aiService)
+    doctorService := services.NewDoctorService()
+    slotHoldService := services.NewSlotHoldService(cfg.Booking.HoldTTL)
+    waitlistService := services.NewWaitlistService()
+    householdService := services.NewHouseholdService()
+    chatbotService := services.NewChatbotService(aiService, inboxService, emergencyService, clinicService, knowledgeService, doctorService)
+    fallbackService := services.NewFallbackService(whatsappService, smsSender, cfg.SMS.FallbackAfter)
+    templateService := services.NewTemplateService(whatsappService)
+    campaignService := services.NewCampaignService(whatsappService, templateService, contactService)
+    
+    // Pick up campaigns interrupted by a restart
+    go campaignService.Resume(context.Background())
+    
+    // Re-send unacknowledged emergency alerts
+    go emergencyService.Run(context.Background())
+    
+    // Resend undelivered confirmations and reminders by SMS
+    go fallbackService.Run(context.Background())
     
     // Initialize controllers
     chatbotController := controllers.NewChatbotController(chatbotService)
     wsController := controllers.NewWebSocketController(chatbotService)
-    whatsappController := controllers.NewWhatsAppController(whatsappService, chatbotService)
+    whatsappController := controllers.NewWhatsAppController(whatsappService, chatbotService, inboxService, contactService, templateService, emergencyService, mailService, fallbackService, doctorService, slotHoldService, waitlistService, householdService, cfg.Booking)
+    inboxController := controllers.NewInboxController(inboxService)
+    templateController := controllers.NewTemplateController(templateService)
+    campaignController := controllers.NewCampaignController(campaignService, whatsappController)
+    contactController := controllers.NewContactController(contactService, whatsappService)
+    emergencyController := controllers.NewEmergencyController(emergencyService)
+    clinicController := controllers.NewClinicController(clinicService)
+    knowledgeController := controllers.NewKnowledgeController(knowledgeService)
+    doctorController := controllers.NewDoctorController(doctorService)
+    waitlistController := controllers.NewWaitlistController(waitlistService)
+    householdController := controllers.NewHouseholdController(householdService)
+    smsController := controllers.NewSMSController(chatbotService, smsSender, cfg.SMS.WebhookURL)
+    
+    // Offer slots that open up to waitlisted patients
+    go whatsappController.RunWaitlist(context.Background())
     
     // Public routes (no authentication required)
     public := router.Group("/api/v1")
@@ -28,6 +81,15 @@ func SetupRoutes(router *gin.Engine) {
         
         // WebSocket for real-time chat
         public.GET("/ws", wsController.HandleWebSocket)
+        
+        // Doctor directory
+        public.GET("/doctors", doctorController.ListDoctors)
+        public.GET("/doctors/departments", doctorController.ListDepartments)
+        public.GET("/doctors/:id", doctorController.GetDoctor)
+        
+        // Earliest open appointment slots in a department; each search can
+        // make many HMS requests, so clients are rate limited
+        public.GET("/slots/earliest", middleware.RateLimit(cfg.Security.RateLimitPerMin), whatsappController.EarliestSlots)
     }
     
     // WhatsApp routes
@@ -37,17 +99,90 @@ func SetupRoutes(router *gin.Engine) {
         whatsapp.GET("/webhook", whatsappController.VerifyWebhook)
         whatsapp.POST("/webhook", whatsappController.HandleWebhook)
         
-        // Admin endpoints (require auth) - commented out for now since middleware isn't available
-        // admin := whatsapp.Group("/admin")
-        // admin.Use(middleware.RequireAuth())
-        // {
-        //     admin.POST("/send", whatsappController.SendMessage)
-        //     admin.GET("/status", whatsappController.GetStatus)
-        // }
-        
-        // Temporarily add admin routes without auth for testing
-        whatsapp.POST("/admin/send", whatsappController.SendMessage)
-        whatsapp.GET("/admin/status", whatsappController.GetStatus)
+        // Admin endpoints; they send messages and show contacts, so a staff token is required
+        admin := whatsapp.Group("/admin")
+        admin.Use(middleware.RequireAuth(cfg.JWT.Secret))
+        admin.POST("/send", whatsappController.SendMessage)
+        admin.GET("/status", whatsappController.GetStatus)
+        
+        // Message templates
+        admin.GET("/templates", templateController.ListTemplates)
+        admin.POST("/templates", templateController.CreateTemplate)
+        admin.POST("/templates/sync", templateController.SyncTemplates)
+        
+        // Broadcast campaigns
+        admin.GET("/campaigns", campaignController.ListCampaigns)
+        admin.POST("/campaigns", campaignController.CreateCampaign)
+        admin.POST("/campaigns/upload", campaignController.UploadCampaign)
+        admin.GET("/campaigns/:id", campaignController.GetCampaign)
+        admin.GET("/campaigns/:id/recipients", campaignController.ListRecipients)
+        admin.POST("/campaigns/:id/cancel", campaignController.CancelCampaign)
+        
+        // Contact consent (opt-in / opt-out)
+        admin.GET("/contacts/:phone", contactController.GetContact)
+        admin.PUT("/contacts/:phone/consent", contactController.UpdateConsent)
+    }
+    
+    // SMS webhook (Twilio calls it for inbound messages)
+    router.POST("/api/sms/webhook", smsController.HandleWebhook)
+    
+    // HMS calls this when an appointment is cancelled, signed with the shared secret
+    router.POST("/api/hms/slot-opened", middleware.VerifyHMSSignature(cfg.Security.HMSWebhookSecret), whatsappController.SlotOpened)
+    
+    // HMS calls this to remind a patient of an appointment, signed the same way
+    router.POST("/api/hms/appointment-reminder", middleware.VerifyHMSSignature(cfg.Security.HMSWebhookSecret), whatsappController.AppointmentReminder)
+    
+    // Staff admin routes; they serve patient records, so a staff token is required
+    admin := router.Group("/api/admin")
+    admin.Use(middleware.RequireAuth(cfg.JWT.Secret))
+    {
+        // Staff inbox
+        inbox := admin.Group("/inbox")
+        inbox.GET("/conversations", inboxController.ListConversations)
+        inbox.GET("/conversations/:id", inboxController.GetConversation)
+        inbox.POST("/conversations/:id/assign", inboxController.AssignConversation)
+        inbox.POST("/conversations/:id/notes", inboxController.AddNote)
+        inbox.POST("/conversations/:id/tags", inboxController.AddTags)
+        inbox.DELETE("/conversations/:id/tags/:tag", inboxController.RemoveTag)
+        inbox.POST("/conversations/:id/read", inboxController.MarkRead)
+        inbox.POST("/conversations/:id/resolve", inboxController.ResolveConversation)
+        
+        // Emergency alerts
+        emergencies := admin.Group("/emergencies")
+        emergencies.GET("", emergencyController.ListAlerts)
+        emergencies.GET("/:id", emergencyController.GetAlert)
+        emergencies.POST("/:id/ack", emergencyController.AcknowledgeAlert)
+        
+        // Clinic profile shown by the chatbot
+        admin.GET("/clinic-profile", clinicController.GetProfile)
+        admin.POST("/clinic-profile", clinicController.CreateProfile)
+        admin.PUT("/clinic-profile", clinicController.UpdateProfile)
+        admin.DELETE("/clinic-profile", clinicController.DeleteProfile)
+        
+        // FAQ documents the chatbot answers from
+        knowledge := admin.Group("/knowledge")
+        knowledge.GET("/documents", knowledgeController.ListDocuments)
+        knowledge.POST("/documents", knowledgeController.CreateDocument)
+        knowledge.POST("/documents/upload", knowledgeController.UploadDocument)
+        knowledge.GET("/documents/:id", knowledgeController.GetDocument)
+        knowledge.DELETE("/documents/:id", knowledgeController.DeleteDocument)
+        knowledge.POST("/reindex", knowledgeController.Reindex)
+        knowledge.GET("/search", knowledgeController.Search)
+        
+        // Doctor directory profiles, keyed by HMS employee ID
+        doctors := admin.Group("/doctors")
+        doctors.GET("", doctorController.ListAllDoctors)
+        doctors.POST("", doctorController.CreateDoctor)
+        doctors.GET("/:id", doctorController.GetAnyDoctor)
+        doctors.PUT("/:id", doctorController.UpdateDoctor)
+        doctors.DELETE("/:id", doctorController.DeleteDoctor)
+        
+        // Patients waiting for a slot
+        admin.GET("/waitlist", waitlistController.ListEntries)
+        
+        // Patients each WhatsApp number books for
+        admin.GET("/households/:phone", householdController.GetHousehold)
+        admin.DELETE("/households/:phone/members/:patientId", householdController.RemoveMember)
     }
     
     // Static files (if serving from Go)
@@ -2,7 +2,9 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -11,22 +13,46 @@ import (
 )
 
 type AIService struct {
-	apiKey     string
-	apiURL     string
-	httpClient *http.Client
+	apiKey         string
+	apiURL         string
+	embeddingModel string
+	httpClient     *http.Client
 }
 
 func NewAIService() *AIService {
 	return &AIService{
-		apiKey: os.Getenv("GOOGLE_API_KEY"),
-		apiURL: "https://generativelanguage.googleapis.com/v1beta/models/gemini-1.5-flash:generateContent",
+		apiKey:         os.Getenv("GOOGLE_API_KEY"),
+		apiURL:         "https://generativelanguage.googleapis.com/v1beta/models/gemini-1.5-flash:generateContent",
+		embeddingModel: getEnvOrDefault("GEMINI_EMBEDDING_MODEL", "text-embedding-004"),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 }
 
+// ErrAIUnavailable is returned when no AI API key is configured
+var ErrAIUnavailable = errors.New("AI service is not configured")
+
 func (s *AIService) GenerateResponse(prompt string) (string, error) {
+	return s.generate(context.Background(), prompt, map[string]interface{}{
+		"temperature":     0.7,
+		"maxOutputTokens": 500,
+	})
+}
+
+// GenerateJSON asks the model for a deterministic JSON answer
+func (s *AIService) GenerateJSON(ctx context.Context, prompt string) (string, error) {
+	return s.generate(ctx, prompt, map[string]interface{}{
+		"temperature":      0,
+		"maxOutputTokens":  200,
+		"responseMimeType": "application/json",
+	})
+}
+
+func (s *AIService) generate(ctx context.Context, prompt string, generationConfig map[string]interface{}) (string, error) {
+	if s.apiKey == "" {
+		return "", ErrAIUnavailable
+	}
 	endpoint := fmt.Sprintf("%s?key=%s", s.apiURL, s.apiKey)
 
 	payload := map[string]interface{}{
@@ -37,10 +63,7 @@ func (s *AIService) GenerateResponse(prompt string) (string, error) {
 				},
 			},
 		},
-		"generationConfig": map[string]interface{}{
-			"temperature":     0.7,
-			"maxOutputTokens": 500,
-		},
+		"generationConfig": generationConfig,
 		"safetySettings": []map[string]interface{}{
 			{
 				"category":  "HARM_CATEGORY_HARASSMENT",
@@ -60,7 +83,7 @@ func (s *AIService) GenerateResponse(prompt string) (string, error) {
 
 	fmt.Println("json data", string(jsonData))
 
-	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", err
 	}
@@ -88,17 +111,87 @@ func (s *AIService) GenerateResponse(prompt string) (string, error) {
 		return "", err
 	}
 
-	// Extract text from response
-	candidates := result["candidates"].([]interface{})
+	// Extract text from response; blocked prompts come back without content
+	candidates, _ := result["candidates"].([]interface{})
 	if len(candidates) > 0 {
-		candidate := candidates[0].(map[string]interface{})
-		content := candidate["content"].(map[string]interface{})
-		parts := content["parts"].([]interface{})
+		candidate, _ := candidates[0].(map[string]interface{})
+		content, _ := candidate["content"].(map[string]interface{})
+		parts, _ := content["parts"].([]interface{})
 		if len(parts) > 0 {
-			part := parts[0].(map[string]interface{})
-			return part["text"].(string), nil
+			part, _ := parts[0].(map[string]interface{})
+			if text, ok := part["text"].(string); ok {
+				return text, nil
+			}
 		}
 	}
 
 	return "", fmt.Errorf("no response generated")
 }
+
+// Gemini accepts at most this many texts per batchEmbedContents call
+const maxEmbedBatch = 100
+
+// Embed returns one embedding vector per text
+func (s *AIService) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if s.apiKey == "" {
+		return nil, ErrAIUnavailable
+	}
+	endpoint := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:batchEmbedContents?key=%s", s.embeddingModel, s.apiKey)
+
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += maxEmbedBatch {
+		end := start + maxEmbedBatch
+		if end > len(texts) {
+			end = len(texts)
+		}
+
+		requests := make([]map[string]interface{}, 0, end-start)
+		for _, text := range texts[start:end] {
+			requests = append(requests, map[string]interface{}{
+				"model": "models/" + s.embeddingModel,
+				"content": map[string]interface{}{
+					"parts": []map[string]interface{}{{"text": text}},
+				},
+			})
+		}
+		jsonData, err := json.Marshal(map[string]interface{}{"requests": requests})
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("embedding API error: %s", string(body))
+		}
+
+		var result struct {
+			Embeddings []struct {
+				Values []float32 `json:"values"`
+			} `json:"embeddings"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		if len(result.Embeddings) != end-start {
+			return nil, fmt.Errorf("embedding API returned %d vectors for %d texts", len(result.Embeddings), end-start)
+		}
+		for _, e := range result.Embeddings {
+			vectors = append(vectors, e.Values)
+		}
+	}
+	return vectors, nil
+}
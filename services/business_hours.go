@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"clinic-chatbot-backend/models"
+)
+
+// How many days ahead NextOpening looks for an open day
+const openingLookaheadDays = 14
+
+// OpeningStatus says whether the clinic is open at a moment
+type OpeningStatus struct {
+	Open        bool
+	Until       time.Time       // closing time, when open
+	NextOpening time.Time       // when closed; zero if nothing opens within two weeks
+	Holiday     *models.Holiday // set when closed for a holiday that day
+}
+
+// OpenAt reports whether the clinic is open at t
+func (s *ClinicService) OpenAt(ctx context.Context, t time.Time) OpeningStatus {
+	profile := s.Profile(ctx)
+	return openingStatus(profile.Hours, profile.Holidays, t.In(ClinicLocation()))
+}
+
+// StaffedAt reports whether reception is answering chats at t
+func (s *ClinicService) StaffedAt(ctx context.Context, t time.Time) OpeningStatus {
+	profile := s.Profile(ctx)
+	hours := profile.StaffedHours
+	if len(hours) == 0 {
+		hours = profile.Hours
+	}
+	return openingStatus(hours, profile.Holidays, t.In(ClinicLocation()))
+}
+
+// HoursOn returns the opening hours on the day of t. ok is false when the
+// clinic is closed that day, with the holiday if that is the reason.
+func (s *ClinicService) HoursOn(ctx context.Context, t time.Time) (hours models.DayHours, holiday *models.Holiday, ok bool) {
+	profile := s.Profile(ctx)
+	return hoursOn(profile.Hours, profile.Holidays, t.In(ClinicLocation()))
+}
+
+// HolidayOn returns the holiday on a YYYY-MM-DD date, if any
+func (s *ClinicService) HolidayOn(ctx context.Context, date string) (*models.Holiday, bool) {
+	for _, h := range s.Profile(ctx).Holidays {
+		if h.Date == date {
+			return &h, true
+		}
+	}
+	return nil, false
+}
+
+func openingStatus(hours []models.DayHours, holidays []models.Holiday, t time.Time) OpeningStatus {
+	var status OpeningStatus
+
+	// Yesterday's hours may run past midnight
+	if yesterday, _, ok := hoursOn(hours, holidays, t.AddDate(0, 0, -1)); ok {
+		if _, close := session(t.AddDate(0, 0, -1), yesterday); t.Before(close) {
+			status.Open = true
+			status.Until = close
+			return status
+		}
+	}
+
+	today, holiday, ok := hoursOn(hours, holidays, t)
+	status.Holiday = holiday
+	if ok {
+		open, close := session(t, today)
+		if !t.Before(open) && t.Before(close) {
+			status.Open = true
+			status.Until = close
+			return status
+		}
+		if t.Before(open) {
+			status.NextOpening = open
+			return status
+		}
+	}
+
+	for i := 1; i <= openingLookaheadDays; i++ {
+		day := t.AddDate(0, 0, i)
+		if h, _, ok := hoursOn(hours, holidays, day); ok {
+			status.NextOpening = atClock(day, h.Open)
+			break
+		}
+	}
+	return status
+}
+
+// session returns when hours on a day open and close. Hours closing at or
+// before their opening time close the next day, e.g. 20:00-02:00.
+func session(day time.Time, h models.DayHours) (open, close time.Time) {
+	open, close = atClock(day, h.Open), atClock(day, h.Close)
+	if !close.After(open) {
+		close = atClock(day.AddDate(0, 0, 1), h.Close)
+	}
+	return open, close
+}
+
+func hoursOn(hours []models.DayHours, holidays []models.Holiday, day time.Time) (models.DayHours, *models.Holiday, bool) {
+	date := day.Format("2006-01-02")
+	for i := range holidays {
+		if holidays[i].Date == date {
+			return models.DayHours{}, &holidays[i], false
+		}
+	}
+
+	weekday := strings.ToLower(day.Weekday().String())
+	for _, h := range hours {
+		if h.Weekday == weekday {
+			return h, nil, true
+		}
+	}
+	return models.DayHours{}, nil, false
+}
+
+// atClock returns an HH:MM time on the day of t, in t's location
+func atClock(day time.Time, clock string) time.Time {
+	c, _ := time.Parse("15:04", clock)
+	return time.Date(day.Year(), day.Month(), day.Day(), c.Hour(), c.Minute(), 0, 0, day.Location())
+}
+
+// FormatOpeningTime formats a time for patients: "15:04" today, otherwise
+// "Mon 02 Jan 15:04"
+func FormatOpeningTime(t, now time.Time) string {
+	now = now.In(t.Location())
+	if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+		return t.Format("15:04")
+	}
+	return t.Format("Mon 02 Jan 15:04")
+}
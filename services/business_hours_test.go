@@ -0,0 +1,95 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"clinic-chatbot-backend/models"
+)
+
+func TestOpeningStatus(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2026-10-19 is a Monday
+	at := func(day int, clock string) time.Time {
+		return atClock(time.Date(2026, 10, day, 0, 0, 0, 0, loc), clock)
+	}
+
+	weekdays := []models.DayHours{
+		{Weekday: "monday", Open: "09:00", Close: "17:00"},
+		{Weekday: "tuesday", Open: "09:00", Close: "17:00"},
+		{Weekday: "wednesday", Open: "09:00", Close: "17:00"},
+		{Weekday: "friday", Open: "20:00", Close: "02:00"},
+	}
+	holidays := []models.Holiday{{Date: "2026-10-20", Name: "Diwali"}}
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		open        bool
+		until       time.Time
+		nextOpening time.Time
+		holiday     string
+	}{
+		{name: "open", now: at(19, "10:30"), open: true, until: at(19, "17:00")},
+		{name: "opening minute", now: at(19, "09:00"), open: true, until: at(19, "17:00")},
+		{name: "before opening", now: at(19, "07:45"), nextOpening: at(19, "09:00")},
+		{name: "closing minute skips the holiday", now: at(19, "17:00"), nextOpening: at(21, "09:00")},
+		{name: "holiday", now: at(20, "11:00"), nextOpening: at(21, "09:00"), holiday: "Diwali"},
+		{name: "closed weekday", now: at(22, "12:00"), nextOpening: at(23, "20:00")},
+		{name: "overnight before midnight", now: at(23, "23:30"), open: true, until: at(24, "02:00")},
+		{name: "overnight after midnight", now: at(24, "01:15"), open: true, until: at(24, "02:00")},
+		{name: "after overnight close", now: at(24, "02:00"), nextOpening: at(26, "09:00")},
+		{name: "weekend", now: at(25, "10:00"), nextOpening: at(26, "09:00")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := openingStatus(weekdays, holidays, tt.now)
+			if got.Open != tt.open || !got.Until.Equal(tt.until) || !got.NextOpening.Equal(tt.nextOpening) {
+				t.Errorf("openingStatus(%s) = open %v until %s next %s, want open %v until %s next %s",
+					tt.now.Format("Mon 15:04"), got.Open, got.Until, got.NextOpening, tt.open, tt.until, tt.nextOpening)
+			}
+			holiday := ""
+			if got.Holiday != nil {
+				holiday = got.Holiday.Name
+			}
+			if holiday != tt.holiday {
+				t.Errorf("holiday = %q, want %q", holiday, tt.holiday)
+			}
+		})
+	}
+}
+
+func TestOpeningStatusNothingOpens(t *testing.T) {
+	got := openingStatus(nil, nil, time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC))
+	if got.Open || !got.NextOpening.IsZero() {
+		t.Errorf("openingStatus with no hours = %+v, want closed with no next opening", got)
+	}
+}
+
+func TestFormatOpeningTime(t *testing.T) {
+	now := time.Date(2026, 10, 19, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC), "20:00"},
+		{time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC), "Tue 20 Oct 09:00"},
+	}
+	for _, tt := range tests {
+		if got := FormatOpeningTime(tt.t, now); got != tt.want {
+			t.Errorf("FormatOpeningTime(%s) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestValidateHoursOvernight(t *testing.T) {
+	if err := validateHours("hours", []models.DayHours{{Weekday: "Friday", Open: "20:00", Close: "02:00"}}); err != nil {
+		t.Errorf("overnight hours rejected: %v", err)
+	}
+	if err := validateHours("hours", []models.DayHours{{Weekday: "friday", Open: "09:00", Close: "09:00"}}); err == nil {
+		t.Error("hours opening and closing at the same time accepted")
+	}
+}
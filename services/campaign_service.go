@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCampaignNotFound is returned when a campaign ID does not exist
+var ErrCampaignNotFound = errors.New("campaign not found")
+
+type CampaignService struct {
+	campaigns       *mongo.Collection
+	recipients      *mongo.Collection
+	whatsappService *WhatsAppService
+	templateService *TemplateService
+	contactService  *ContactService
+
+	workers       int
+	ratePerSecond int
+
+	// Cancel functions of campaigns currently being sent
+	mu      sync.Mutex
+	running map[primitive.ObjectID]context.CancelFunc
+}
+
+func NewCampaignService(whatsappService *WhatsAppService, templateService *TemplateService, contactService *ContactService) *CampaignService {
+	workers, _ := strconv.Atoi(getEnvOrDefault("CAMPAIGN_WORKERS", "5"))
+	rate, _ := strconv.Atoi(getEnvOrDefault("CAMPAIGN_RATE_PER_SECOND", "10"))
+	if workers <= 0 {
+		workers = 5
+	}
+	if rate <= 0 {
+		rate = 10
+	}
+
+	db := database.GetMongoDB()
+	return &CampaignService{
+		campaigns:       db.Collection("campaigns"),
+		recipients:      db.Collection("campaign_recipients"),
+		whatsappService: whatsappService,
+		templateService: templateService,
+		contactService:  contactService,
+		workers:         workers,
+		ratePerSecond:   rate,
+		running:         make(map[primitive.ObjectID]context.CancelFunc),
+	}
+}
+
+// Create stores a campaign with its recipients and starts sending in the background
+func (s *CampaignService) Create(ctx context.Context, campaign models.Campaign, recipients []models.CampaignRecipient) (*models.Campaign, error) {
+	if campaign.Template.Language == "" {
+		campaign.Template.Language = s.whatsappService.templateLanguage
+	}
+
+	recipients = s.dedupeRecipients(recipients)
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("campaign has no valid recipients")
+	}
+
+	// Validate the template once with the first recipient's parameters
+	if err := s.templateService.Validate(ctx, s.messageFor(campaign, recipients[0])); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	campaign.ID = primitive.NewObjectID()
+	campaign.Status = models.CampaignRunning
+	campaign.Total = len(recipients)
+	campaign.CreatedAt = now
+	campaign.StartedAt = &now
+
+	docs := make([]interface{}, len(recipients))
+	for i := range recipients {
+		recipients[i].ID = primitive.NewObjectID()
+		recipients[i].CampaignID = campaign.ID
+		recipients[i].Status = models.RecipientPending
+		recipients[i].UpdatedAt = now
+		docs[i] = recipients[i]
+	}
+
+	if _, err := s.campaigns.InsertOne(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+	if _, err := s.recipients.InsertMany(ctx, docs); err != nil {
+		return nil, fmt.Errorf("failed to store campaign recipients: %w", err)
+	}
+
+	s.start(campaign)
+	return &campaign, nil
+}
+
+// Resume restarts campaigns that were interrupted by a server restart
+func (s *CampaignService) Resume(ctx context.Context) {
+	cursor, err := s.campaigns.Find(ctx, bson.M{"status": models.CampaignRunning})
+	if err != nil {
+		log.Println("failed to load running campaigns:", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var campaigns []models.Campaign
+	if err := cursor.All(ctx, &campaigns); err != nil {
+		log.Println("failed to decode running campaigns:", err)
+		return
+	}
+
+	for _, campaign := range campaigns {
+		log.Printf("Resuming campaign %s (%s)", campaign.Name, campaign.ID.Hex())
+		s.start(campaign)
+	}
+}
+
+// Get returns a campaign with its progress counters
+func (s *CampaignService) Get(ctx context.Context, id string) (*models.Campaign, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrCampaignNotFound
+	}
+
+	var campaign models.Campaign
+	err = s.campaigns.FindOne(ctx, bson.M{"_id": oid}).Decode(&campaign)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrCampaignNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch campaign: %w", err)
+	}
+	return &campaign, nil
+}
+
+// List returns campaigns, newest first
+func (s *CampaignService) List(ctx context.Context, limit int) ([]models.Campaign, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := s.campaigns.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	campaigns := []models.Campaign{}
+	if err := cursor.All(ctx, &campaigns); err != nil {
+		return nil, fmt.Errorf("failed to decode campaigns: %w", err)
+	}
+	return campaigns, nil
+}
+
+// ListRecipients returns per-recipient delivery status for a campaign
+func (s *CampaignService) ListRecipients(ctx context.Context, id string, status models.RecipientStatus, page, limit int) ([]models.CampaignRecipient, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrCampaignNotFound
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	query := bson.M{"campaign_id": oid}
+	if status != "" {
+		query["status"] = status
+	}
+
+	opts := options.Find().SetSkip(int64((page - 1) * limit)).SetLimit(int64(limit))
+	cursor, err := s.recipients.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recipients: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	recipients := []models.CampaignRecipient{}
+	if err := cursor.All(ctx, &recipients); err != nil {
+		return nil, fmt.Errorf("failed to decode recipients: %w", err)
+	}
+	return recipients, nil
+}
+
+// Cancel stops a running campaign; recipients already sent are unaffected
+func (s *CampaignService) Cancel(ctx context.Context, id string) (*models.Campaign, error) {
+	campaign, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if cancel, ok := s.running[campaign.ID]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	if campaign.Status == models.CampaignRunning {
+		s.finish(campaign.ID, models.CampaignCancelled)
+	}
+	return s.Get(ctx, id)
+}
+
+// start launches the sending loop for a campaign
+func (s *CampaignService) start(campaign models.Campaign) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	s.running[campaign.ID] = cancel
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, campaign.ID)
+			s.mu.Unlock()
+			cancel()
+		}()
+
+		s.run(ctx, campaign)
+	}()
+}
+
+// run feeds pending recipients to a pool of workers at the configured rate
+func (s *CampaignService) run(ctx context.Context, campaign models.Campaign) {
+	cursor, err := s.recipients.Find(ctx, bson.M{
+		"campaign_id": campaign.ID,
+		"status":      models.RecipientPending,
+	})
+	if err != nil {
+		log.Printf("campaign %s: failed to load recipients: %v", campaign.ID.Hex(), err)
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	jobs := make(chan models.CampaignRecipient)
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for recipient := range jobs {
+				s.deliver(campaign, recipient)
+			}
+		}()
+	}
+
+	throttle := time.NewTicker(time.Second / time.Duration(s.ratePerSecond))
+	defer throttle.Stop()
+
+dispatch:
+	for cursor.Next(ctx) {
+		var recipient models.CampaignRecipient
+		if err := cursor.Decode(&recipient); err != nil {
+			log.Printf("campaign %s: failed to decode recipient: %v", campaign.ID.Hex(), err)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case <-throttle.C:
+		}
+
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- recipient:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if ctx.Err() == nil {
+		s.finish(campaign.ID, models.CampaignCompleted)
+		log.Printf("Campaign %s (%s) completed", campaign.Name, campaign.ID.Hex())
+	}
+}
+
+// deliver sends the campaign template to one recipient and records the outcome
+func (s *CampaignService) deliver(campaign models.Campaign, recipient models.CampaignRecipient) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := s.contactService.CheckConsent(ctx, recipient.Phone); err != nil {
+		if errors.Is(err, ErrContactOptedOut) || errors.Is(err, ErrNoOptIn) {
+			s.recordOutcome(ctx, campaign.ID, recipient.ID, models.RecipientSuppressed, err.Error())
+			return
+		}
+		// Consent fails closed: a marketing message is never sent unchecked
+		log.Printf("campaign %s: consent lookup failed for %s: %v", campaign.ID.Hex(), recipient.Phone, err)
+		s.recordOutcome(ctx, campaign.ID, recipient.ID, models.RecipientFailed, "consent lookup failed")
+		return
+	}
+
+	if err := s.whatsappService.SendTemplate(recipient.Phone, s.messageFor(campaign, recipient)); err != nil {
+		s.recordOutcome(ctx, campaign.ID, recipient.ID, models.RecipientFailed, err.Error())
+		return
+	}
+	s.recordOutcome(ctx, campaign.ID, recipient.ID, models.RecipientSent, "")
+}
+
+// recordOutcome updates a recipient and the campaign counters
+func (s *CampaignService) recordOutcome(ctx context.Context, campaignID, recipientID primitive.ObjectID, status models.RecipientStatus, reason string) {
+	now := time.Now()
+	set := bson.M{"status": status, "updated_at": now}
+	if reason != "" {
+		set["error"] = reason
+	}
+	if status == models.RecipientSent {
+		set["sent_at"] = now
+	}
+
+	if _, err := s.recipients.UpdateByID(ctx, recipientID, bson.M{"$set": set}); err != nil {
+		log.Printf("campaign %s: failed to update recipient: %v", campaignID.Hex(), err)
+	}
+	// Counter fields are named after the recipient status (sent, failed, suppressed)
+	if _, err := s.campaigns.UpdateByID(ctx, campaignID, bson.M{"$inc": bson.M{string(status): 1}}); err != nil {
+		log.Printf("campaign %s: failed to update counters: %v", campaignID.Hex(), err)
+	}
+}
+
+// finish marks a campaign as done
+func (s *CampaignService) finish(id primitive.ObjectID, status models.CampaignStatus) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := s.campaigns.UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.CampaignRunning},
+		bson.M{"$set": bson.M{"status": status, "completed_at": time.Now()}},
+	)
+	if err != nil {
+		log.Printf("campaign %s: failed to update status: %v", id.Hex(), err)
+	}
+}
+
+// messageFor builds the template message for a recipient. Recipient params
+// override the campaign params, and "{name}" is replaced by the recipient name.
+func (s *CampaignService) messageFor(campaign models.Campaign, recipient models.CampaignRecipient) models.TemplateMessage {
+	msg := campaign.Template
+
+	params := campaign.Template.BodyParams
+	if len(recipient.Params) > 0 {
+		params = recipient.Params
+	}
+
+	msg.BodyParams = make([]string, len(params))
+	for i, param := range params {
+		msg.BodyParams[i] = strings.ReplaceAll(param, "{name}", recipient.Name)
+	}
+	return msg
+}
+
+// dedupeRecipients normalizes phone numbers and drops duplicates and blanks
+func (s *CampaignService) dedupeRecipients(recipients []models.CampaignRecipient) []models.CampaignRecipient {
+	seen := make(map[string]bool, len(recipients))
+	result := make([]models.CampaignRecipient, 0, len(recipients))
+
+	for _, r := range recipients {
+		r.Phone = s.whatsappService.NormalizePhone(r.Phone)
+		if len(r.Phone) < 8 || seen[r.Phone] {
+			continue
+		}
+		seen[r.Phone] = true
+		r.Name = strings.TrimSpace(r.Name)
+		result = append(result, r)
+	}
+	return result
+}
@@ -3,8 +3,14 @@ package services
 import (
     "context"
     "fmt"
+    "log"
+    "strconv"
     "strings"
+    "sync"
     "time"
+    "unicode"
+    "clinic-chatbot-backend/config"
+    "clinic-chatbot-backend/i18n"
     "clinic-chatbot-backend/models"
     "clinic-chatbot-backend/utils"
 )
@@ -12,28 +18,79 @@ import (
 type ChatbotService struct {
     aiService        *AIService
     // appointmentSvc   *AppointmentService
-    intentClassifier *utils.IntentClassifier
-    clinicInfo       map[string]string
+    intentClassifier utils.IntentClassifier
+    keywords         *utils.KeywordClassifier // emergency and handoff checks for prompt answers
+    entityExtractor  utils.EntityExtractor
+    inboxService     *InboxService
+    emergencyService *EmergencyService
+    clinicService    *ClinicService
+    knowledgeService *KnowledgeService
+    doctorService    *DoctorService
+    
+    // Sessions told the clinic is closed, until the time it reopens
+    afterHoursMu     sync.Mutex
+    afterHoursSent   map[string]time.Time
 }
 
-func NewChatbotService(aiService *AIService) *ChatbotService {
+// How many clinic FAQ passages are given to the AI model per question
+const knowledgePassageLimit = 3
+
+func NewChatbotService(aiService *AIService, inboxService *InboxService, emergencyService *EmergencyService, clinicService *ClinicService, knowledgeService *KnowledgeService, doctorService *DoctorService) *ChatbotService {
     return &ChatbotService{
         aiService:        aiService,
+        inboxService:     inboxService,
+        emergencyService: emergencyService,
+        clinicService:    clinicService,
+        knowledgeService: knowledgeService,
+        doctorService:    doctorService,
+        afterHoursSent:   make(map[string]time.Time),
         // appointmentSvc:   appointmentSvc,
-        intentClassifier: utils.NewIntentClassifier(),
-        clinicInfo: map[string]string{
-            "name":     "HealthCare Clinic",
-            "address":  "123 Medical Center, Downtown",
-            "phone":    "+1-234-567-8900",
-            "hours":    "Mon-Fri: 9AM-6PM, Sat: 9AM-2PM",
-            "services": "General Medicine, Pediatrics, Cardiology, Dermatology",
-        },
+        intentClassifier: newIntentClassifier(aiService),
+        keywords:         utils.NewKeywordClassifier(),
+        entityExtractor:  utils.NewRuleEntityExtractor(ClinicLocation()),
+    }
+}
+
+// newIntentClassifier builds the classifier selected by INTENT_CLASSIFIER.
+// "llm" uses the AI model and falls back to keywords when the model is
+// unavailable or below INTENT_MIN_CONFIDENCE; anything else uses keywords only.
+func newIntentClassifier(aiService *AIService) utils.IntentClassifier {
+    keyword := utils.NewKeywordClassifier()
+    if getEnvOrDefault("INTENT_CLASSIFIER", "keyword") != "llm" {
+        return keyword
     }
+    
+    minConfidence, err := strconv.ParseFloat(getEnvOrDefault("INTENT_MIN_CONFIDENCE", "0.6"), 64)
+    if err != nil {
+        minConfidence = 0.6
+    }
+    return utils.NewFallbackClassifier(NewLLMIntentClassifier(aiService), keyword, minConfidence)
+}
+
+// ClinicProfile returns the clinic profile shown to patients
+func (s *ChatbotService) ClinicProfile(ctx context.Context) models.ClinicProfile {
+    return s.clinicService.Profile(ctx)
+}
+
+// ClinicLocation returns the clinic time zone, loaded with the configuration
+func ClinicLocation() *time.Location {
+    return config.Get().Location
 }
 
 func (s *ChatbotService) ProcessMessage(ctx context.Context, req models.ChatRequest) (*models.ChatResponse, error) {
     // Classify intent
-    intent := s.intentClassifier.ClassifyIntent(req.Message)
+    classification := s.classify(ctx, req.Message)
+    intent := classification.Intent
+    
+    // Track the conversation in the staff inbox
+    channel := req.Channel
+    if channel == "" {
+        channel = models.ChannelWeb
+    }
+    if err := s.inboxService.RecordInbound(ctx, channel, req.SessionID, req.UserID, req.Message, intent); err != nil {
+        log.Println("inbox recording error", err)
+    }
+    locale := s.resolveLocale(ctx, req)
     
     // Create message record
     message := &models.Message{
@@ -47,25 +104,53 @@ func (s *ChatbotService) ProcessMessage(ctx context.Context, req models.ChatRequ
     var response *models.ChatResponse
     var err error
     
-    // Handle based on intent
-    switch intent {
-    case models.IntentEmergency:
-        response, err = s.handleEmergency()
-    // case models.IntentAppointment:
-    //     response, err = s.handleAppointment(req)
-    case models.IntentClinicInfo:
-        response, err = s.handleClinicInfo(req.Message)
-    case models.IntentMedicalQuery:
-        response, err = s.handleMedicalQuery(req)
-    case models.IntentGreeting:
-        response, err = s.handleGreeting()
-    default:
-        response, err = s.handleUnknown(req)
+    // Handle based on intent; "our doctors" is answered from the directory
+    if s.isDirectoryRequest(intent, req.Message) {
+        response, err = s.handleDoctorDirectory(ctx, req.Message, locale)
+    } else {
+        switch intent {
+        case models.IntentEmergency:
+            if _, alertErr := s.emergencyService.Raise(ctx, channel, req.SessionID, req.UserID, req.Message); alertErr != nil {
+                log.Println("emergency alert error", alertErr)
+            }
+            response, err = s.handleEmergency(locale)
+        // case models.IntentAppointment:
+        //     response, err = s.handleAppointment(req)
+        case models.IntentClinicInfo:
+            response, err = s.handleClinicInfo(ctx, req.Message, locale)
+        case models.IntentMedicalQuery:
+            response, err = s.handleMedicalQuery(ctx, req, locale)
+        case models.IntentGreeting:
+            response, err = s.handleGreeting(ctx, locale)
+        case models.IntentHandoff:
+            response, err = s.handleHandoff(ctx, req.SessionID, locale)
+        default:
+            response, err = s.handleUnknown(ctx, req, locale)
+        }
     }
     
     if err != nil {
         return nil, err
     }
+    response.Locale = string(locale)
+    response.IntentConfidence = classification.Confidence
+    
+    // Outside opening hours, say when the clinic reopens
+    if intent != models.IntentEmergency && intent != models.IntentHandoff {
+        if notice, ok := s.AfterHoursNotice(ctx, req.SessionID, locale); ok {
+            response.Response = notice + "\n\n" + response.Response
+        }
+    }
+    
+    // Booking details found in the message let the client prefill its form
+    if intent == models.IntentAppointment || intent == models.IntentMedicalQuery {
+        if entities := s.ExtractEntities(ctx, req.Message); !entities.IsEmpty() {
+            if response.Data == nil {
+                response.Data = map[string]interface{}{}
+            }
+            response.Data["entities"] = entities
+        }
+    }
     
     // Save message to database
     message.BotResponse = response.Response
@@ -77,26 +162,98 @@ func (s *ChatbotService) ProcessMessage(ctx context.Context, req models.ChatRequ
     return response, nil
 }
 
+// ClassifyIntent exposes intent classification to other channels
+func (s *ChatbotService) ClassifyIntent(ctx context.Context, message string) models.MessageIntent {
+    return s.classify(ctx, message).Intent
+}
+
+// ClassifyReply classifies an answer typed at a booking prompt, where only an
+// emergency or a request for staff changes course. It uses keywords alone, so
+// answering a prompt never waits on the AI model.
+func (s *ChatbotService) ClassifyReply(message string) models.MessageIntent {
+    return s.withHandoff(message, utils.IntentResult{Intent: s.keywords.ClassifyIntent(message)}).Intent
+}
+
+// ExtractEntities pulls department, doctor, date, time window, patient name
+// and symptoms out of a free-text message
+func (s *ChatbotService) ExtractEntities(ctx context.Context, message string) models.ExtractedEntities {
+    entities, err := s.entityExtractor.Extract(ctx, message)
+    if err != nil {
+        log.Println("entity extraction error", err)
+    }
+    return entities
+}
+
+func (s *ChatbotService) classify(ctx context.Context, message string) utils.IntentResult {
+    result, err := s.intentClassifier.Classify(ctx, message)
+    if err != nil {
+        log.Println("intent classification error", err)
+        result = utils.IntentResult{Intent: models.IntentUnknown}
+    }
+    return s.withHandoff(message, result)
+}
+
+// withHandoff lets asking for a person override the topic, but never an emergency
+func (s *ChatbotService) withHandoff(message string, result utils.IntentResult) utils.IntentResult {
+    if result.Intent != models.IntentEmergency && i18n.IsHandoffRequest(message) {
+        return utils.IntentResult{Intent: models.IntentHandoff, Confidence: 1}
+    }
+    return result
+}
+
+// resolveLocale picks the reply language: an explicit locale on the request
+// wins, then a locale the patient chose earlier, then the script of the message,
+// then whatever was detected before. The result is stored on the session.
+func (s *ChatbotService) resolveLocale(ctx context.Context, req models.ChatRequest) i18n.Locale {
+    if locale, ok := i18n.Parse(req.Locale); ok {
+        if err := s.inboxService.SetLocale(ctx, req.SessionID, string(locale), true); err != nil {
+            log.Println("locale update error", err)
+        }
+        return locale
+    }
+
+    stored, explicit, err := s.inboxService.GetLocale(ctx, req.SessionID)
+    if err != nil {
+        log.Println("locale lookup error", err)
+    }
+    storedLocale, hasStored := i18n.Parse(stored)
+    if hasStored && explicit {
+        return storedLocale
+    }
+
+    if locale, ok := i18n.Detect(req.Message); ok {
+        if locale != storedLocale {
+            if err := s.inboxService.SetLocale(ctx, req.SessionID, string(locale), false); err != nil {
+                log.Println("locale update error", err)
+            }
+        }
+        return locale
+    }
+    if hasStored {
+        return storedLocale
+    }
+    return i18n.Default
+}
+
 // All handler methods now return (*models.ChatResponse, error)
 
-func (s *ChatbotService) handleEmergency() (*models.ChatResponse, error) {
+func (s *ChatbotService) handleEmergency(locale i18n.Locale) (*models.ChatResponse, error) {
     return &models.ChatResponse{
-        Response: "🚨 EMERGENCY DETECTED! Please call 911 immediately or visit the nearest emergency room. " +
-                 "For immediate assistance, call our emergency line: +1-234-567-8999",
+        Response: i18n.T(locale, "chat.emergency", s.emergencyService.PublicNumber(), s.emergencyService.ClinicLine()),
         Intent: models.IntentEmergency,
         Actions: []models.Action{
             {
                 Type:  "call",
-                Label: "Call Emergency",
+                Label: i18n.T(locale, "chat.action.call_emergency"),
                 Payload: map[string]interface{}{
-                    "number": "911",
+                    "number": s.emergencyService.PublicNumber(),
                 },
             },
             {
                 Type:  "call",
-                Label: "Call Clinic Emergency",
+                Label: i18n.T(locale, "chat.action.call_clinic_er"),
                 Payload: map[string]interface{}{
-                    "number": "+1-234-567-8999",
+                    "number": s.emergencyService.ClinicLine(),
                 },
             },
         },
@@ -192,48 +349,56 @@ func (s *ChatbotService) handleEmergency() (*models.ChatResponse, error) {
 //     }, nil
 // }
 
-func (s *ChatbotService) handleClinicInfo(message string) (*models.ChatResponse, error) {
+func (s *ChatbotService) handleClinicInfo(ctx context.Context, message string, locale i18n.Locale) (*models.ChatResponse, error) {
+    if isOpeningQuestion(message) {
+        return s.handleOpeningQuestion(ctx, message, locale)
+    }
+    
+    clinic := s.clinicService.Profile(ctx)
+    address := FormatClinicAddresses(clinic.Addresses)
+    phones := FormatClinicPhones(clinic.Phones)
+    hours := FormatClinicHours(clinic.Hours)
+    services := strings.Join(clinic.Services, ", ")
+    
     message = strings.ToLower(message)
-    response := fmt.Sprintf("Here's information about %s:\n\n", s.clinicInfo["name"])
+    response := i18n.T(locale, "chat.clinic_intro", clinic.Name)
     
     // Build response based on what user is asking
     infoRequested := false
     
     if strings.Contains(message, "address") || strings.Contains(message, "location") || strings.Contains(message, "where") {
-        response += fmt.Sprintf("📍 Address: %s\n", s.clinicInfo["address"])
+        response += i18n.T(locale, "chat.clinic_address", address)
         infoRequested = true
     }
     
     if strings.Contains(message, "phone") || strings.Contains(message, "contact") || strings.Contains(message, "call") {
-        response += fmt.Sprintf("📞 Phone: %s\n", s.clinicInfo["phone"])
+        response += i18n.T(locale, "chat.clinic_phone", phones)
         infoRequested = true
     }
     
     if strings.Contains(message, "hours") || strings.Contains(message, "timing") || strings.Contains(message, "open") {
-        response += fmt.Sprintf("🕐 Hours: %s\n", s.clinicInfo["hours"])
+        response += i18n.T(locale, "chat.clinic_hours", hours)
         infoRequested = true
     }
     
-    if strings.Contains(message, "services") || strings.Contains(message, "specialization") || strings.Contains(message, "department") {
-        response += fmt.Sprintf("🏥 Services: %s\n", s.clinicInfo["services"])
+    if strings.Contains(message, "services") || strings.Contains(message, "specialization") {
+        response += i18n.T(locale, "chat.clinic_services", services)
+        infoRequested = true
+    }
+    
+    if strings.Contains(message, "department") && len(clinic.Departments) > 0 {
+        response += i18n.T(locale, "chat.clinic_departments", strings.Join(clinic.Departments, ", "))
         infoRequested = true
     }
     
     // If no specific info requested, show all
     if !infoRequested {
-        response = fmt.Sprintf(
-            "Here's information about %s:\n\n"+
-            "📍 Address: %s\n"+
-            "📞 Phone: %s\n"+
-            "🕐 Hours: %s\n"+
-            "🏥 Services: %s\n\n"+
-            "Is there anything specific you'd like to know?",
-            s.clinicInfo["name"],
-            s.clinicInfo["address"],
-            s.clinicInfo["phone"],
-            s.clinicInfo["hours"],
-            s.clinicInfo["services"],
-        )
+        response = i18n.T(locale, "chat.clinic_intro", clinic.Name) +
+            i18n.T(locale, "chat.clinic_address", address) +
+            i18n.T(locale, "chat.clinic_phone", phones) +
+            i18n.T(locale, "chat.clinic_hours", hours) +
+            i18n.T(locale, "chat.clinic_services", services) +
+            i18n.T(locale, "chat.clinic_anything_else")
     }
     
     return &models.ChatResponse{
@@ -242,120 +407,423 @@ func (s *ChatbotService) handleClinicInfo(message string) (*models.ChatResponse,
         Actions: []models.Action{
             {
                 Type:  "show_map",
-                Label: "Show on Map",
+                Label: i18n.T(locale, "chat.action.show_map"),
                 Payload: map[string]interface{}{
-                    "address": s.clinicInfo["address"],
+                    "address": clinic.PrimaryAddress(),
                 },
             },
             {
                 Type:  "call",
-                Label: "Call Clinic",
+                Label: i18n.T(locale, "chat.action.call_clinic"),
                 Payload: map[string]interface{}{
-                    "number": s.clinicInfo["phone"],
+                    "number": clinic.PrimaryPhone(),
                 },
             },
             {
                 Type:  "book_appointment",
-                Label: "Book an Appointment",
+                Label: i18n.T(locale, "chat.action.book"),
             },
         },
     }, nil // Added nil error return
 }
 
-func (s *ChatbotService) handleMedicalQuery(req models.ChatRequest) (*models.ChatResponse, error) {
+// AnswerClinicInfo answers a clinic information question for channels that
+// only send text, such as WhatsApp
+func (s *ChatbotService) AnswerClinicInfo(ctx context.Context, message string, locale i18n.Locale) string {
+    response, _ := s.handleClinicInfo(ctx, message, locale)
+    return response.Response
+}
+
+// openingWords mark questions like "are you open on Sunday?"
+var openingWords = map[string]bool{
+    "open": true, "opened": true, "opening": true, "closed": true, "closing": true,
+    "holiday": true, "holidays": true,
+}
+
+func isOpeningQuestion(message string) bool {
+    for _, w := range strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+        return !unicode.IsLetter(r)
+    }) {
+        if openingWords[w] {
+            return true
+        }
+    }
+    return false
+}
+
+// handleOpeningQuestion answers whether the clinic is open now, or on the
+// day the message mentions
+func (s *ChatbotService) handleOpeningQuestion(ctx context.Context, message string, locale i18n.Locale) (*models.ChatResponse, error) {
+    now := time.Now().In(ClinicLocation())
+    date := s.ExtractEntities(ctx, message).Date
+    
+    var response string
+    if day, err := time.ParseInLocation("2006-01-02", date, ClinicLocation()); err == nil && date != now.Format("2006-01-02") {
+        label := day.Format("Mon 02 Jan")
+        hours, holiday, open := s.clinicService.HoursOn(ctx, day)
+        switch {
+        case open:
+            response = i18n.T(locale, "hours.open_on", label, hours.Open, hours.Close)
+        case holiday != nil:
+            response = i18n.T(locale, "hours.holiday_on", label, holiday.Name)
+        default:
+            response = i18n.T(locale, "hours.closed_on", label)
+        }
+    } else {
+        status := s.clinicService.OpenAt(ctx, now)
+        switch {
+        case status.Open:
+            response = i18n.T(locale, "hours.open_now", status.Until.Format("15:04"))
+        case status.NextOpening.IsZero():
+            response = i18n.T(locale, "hours.closed_now_unknown")
+        case status.Holiday != nil:
+            response = i18n.T(locale, "hours.holiday_now", status.Holiday.Name, FormatOpeningTime(status.NextOpening, now))
+        default:
+            response = i18n.T(locale, "hours.closed_now", FormatOpeningTime(status.NextOpening, now))
+        }
+    }
+    response += "\n\n" + i18n.T(locale, "chat.clinic_hours", FormatClinicHours(s.clinicService.Profile(ctx).Hours))
+    
+    return &models.ChatResponse{
+        Response: response,
+        Intent:   models.IntentClinicInfo,
+        Actions: []models.Action{
+            {
+                Type:  "book_appointment",
+                Label: i18n.T(locale, "chat.action.book"),
+            },
+        },
+    }, nil
+}
+
+// AfterHoursNotice returns the reply prefix telling a patient the clinic is
+// closed and when it reopens. Each session gets it once per closure.
+func (s *ChatbotService) AfterHoursNotice(ctx context.Context, sessionID string, locale i18n.Locale) (string, bool) {
+    now := time.Now()
+    status := s.clinicService.OpenAt(ctx, now)
+    if status.Open {
+        return "", false
+    }
+    
+    s.afterHoursMu.Lock()
+    defer s.afterHoursMu.Unlock()
+    if reopens, sent := s.afterHoursSent[sessionID]; sent && now.Before(reopens) {
+        return "", false
+    }
+    for id, reopens := range s.afterHoursSent {
+        if !now.Before(reopens) {
+            delete(s.afterHoursSent, id)
+        }
+    }
+    
+    if status.NextOpening.IsZero() {
+        s.afterHoursSent[sessionID] = now.Add(24 * time.Hour)
+        return i18n.T(locale, "hours.after_hours_unknown", s.emergencyService.PublicNumber()), true
+    }
+    s.afterHoursSent[sessionID] = status.NextOpening
+    return i18n.T(locale, "hours.after_hours", FormatOpeningTime(status.NextOpening, now), s.emergencyService.PublicNumber()), true
+}
+
+// RequestHandoff flags the conversation for reception in the staff inbox
+// while reception is staffed. Outside staffed hours nobody would answer, so
+// the patient is told when staff are back instead. It returns the reply
+// and whether the conversation was handed off.
+func (s *ChatbotService) RequestHandoff(ctx context.Context, sessionID string, locale i18n.Locale) (string, bool) {
+    now := time.Now()
+    status := s.clinicService.StaffedAt(ctx, now)
+    if !status.Open {
+        if status.NextOpening.IsZero() {
+            return i18n.T(locale, "hours.handoff_unavailable"), false
+        }
+        return i18n.T(locale, "hours.handoff_closed", FormatOpeningTime(status.NextOpening, now)), false
+    }
+    
+    if err := s.inboxService.TagSession(ctx, sessionID, handoffTag); err != nil {
+        log.Println("handoff tagging error", err)
+    }
+    return i18n.T(locale, "hours.handoff"), true
+}
+
+// handoffTag marks inbox conversations waiting for reception
+const handoffTag = "handoff"
+
+func (s *ChatbotService) handleHandoff(ctx context.Context, sessionID string, locale i18n.Locale) (*models.ChatResponse, error) {
+    reply, handedOff := s.RequestHandoff(ctx, sessionID, locale)
+    response := &models.ChatResponse{
+        Response: reply,
+        Intent:   models.IntentHandoff,
+        Data:     map[string]interface{}{"handed_off": handedOff},
+    }
+    if !handedOff {
+        response.Actions = []models.Action{
+            {
+                Type:  "book_appointment",
+                Label: i18n.T(locale, "chat.action.book"),
+            },
+        }
+    }
+    return response, nil
+}
+
+// ClinicHoliday returns the holiday the clinic is closed for on a
+// YYYY-MM-DD date, if any
+func (s *ChatbotService) ClinicHoliday(ctx context.Context, date string) (*models.Holiday, bool) {
+    return s.clinicService.HolidayOn(ctx, date)
+}
+
+func (s *ChatbotService) handleMedicalQuery(ctx context.Context, req models.ChatRequest, locale i18n.Locale) (*models.ChatResponse, error) {
+    return s.answerMedicalQuery(req, locale, s.searchKnowledge(ctx, req.Message))
+}
+
+// answerMedicalQuery asks the AI model, grounding the answer in any clinic
+// FAQ passages found for the question
+func (s *ChatbotService) answerMedicalQuery(req models.ChatRequest, locale i18n.Locale, passages []models.KnowledgePassage) (*models.ChatResponse, error) {
     // Add medical disclaimer
     prompt := fmt.Sprintf(
         "You are a medical assistant AI for a clinic. "+
         "IMPORTANT: Always remind users that this is not a replacement for professional medical advice. "+
         "User query: %s\n\n"+
         "Provide helpful general information while encouraging them to consult with a healthcare provider. "+
-        "Keep the response concise and informative.",
+        "Keep the response concise and informative. "+
+        "Respond in %s.",
         req.Message,
-    )
+        locale.EnglishName(),
+    ) + knowledgeContext(passages)
     fmt.Println("prompt", prompt)
     aiResponse, err := s.aiService.GenerateResponse(prompt)
 
+    actions := []models.Action{
+        {
+            Type:  "book_consultation",
+            Label: i18n.T(locale, "chat.action.consultation"),
+        },
+        {
+            Type:  "call_nurse",
+            Label: i18n.T(locale, "chat.action.nurse"),
+        },
+    }
+
     if err != nil {
         fmt.Println("error", err)
+        if len(passages) > 0 {
+            return s.quoteKnowledge(models.IntentMedicalQuery, passages, locale, actions), nil
+        }
         // Fallback response if AI fails
         return &models.ChatResponse{
-            Response: "I apologize, but I'm having trouble processing your medical query right now. " +
-                     "For medical concerns, it's always best to consult with our healthcare providers directly. " +
-                     "Would you like to book an appointment?",
+            Response: i18n.T(locale, "chat.medical_unavailable"),
             Intent: models.IntentMedicalQuery,
-            Actions: []models.Action{
-                {
-                    Type:  "book_consultation",
-                    Label: "Book a Consultation",
-                },
-                {
-                    Type:  "call_nurse",
-                    Label: "Speak to a Nurse",
-                },
-            },
+            Actions: actions,
         }, nil
     }
     
     return &models.ChatResponse{
-        Response: aiResponse + "\n\n⚠️ Note: This information is for educational purposes only. " +
-                 "Please consult with our healthcare providers for personalized medical advice.",
+        Response: aiResponse + citeKnowledge(passages, locale) + i18n.T(locale, "chat.medical_disclaimer"),
         Intent: models.IntentMedicalQuery,
-        Actions: []models.Action{
-            {
-                Type:  "book_consultation",
-                Label: "Book a Consultation",
-            },
-            {
-                Type:  "call_nurse",
-                Label: "Speak to a Nurse",
-            },
-            {
-                Type:  "view_doctors",
-                Label: "View Our Doctors",
-            },
+        Actions: append(actions, models.Action{
+            Type:  "view_doctors",
+            Label: i18n.T(locale, "chat.action.view_doctors"),
+        }),
+        Data: knowledgeData(passages),
+    }, nil
+}
+
+// searchKnowledge finds clinic FAQ passages for a question; errors only cost
+// the answer its grounding
+func (s *ChatbotService) searchKnowledge(ctx context.Context, question string) []models.KnowledgePassage {
+    passages, err := s.knowledgeService.Search(ctx, question, knowledgePassageLimit)
+    if err != nil {
+        log.Println("knowledge search error", err)
+    }
+    return passages
+}
+
+// answerFromKnowledge answers a non-medical question, such as parking or
+// accepted insurance, from clinic FAQ passages
+func (s *ChatbotService) answerFromKnowledge(req models.ChatRequest, locale i18n.Locale, passages []models.KnowledgePassage) *models.ChatResponse {
+    prompt := fmt.Sprintf(
+        "You are the assistant of a clinic answering a patient's question. "+
+        "Answer only from the clinic information below. If it does not answer the question, "+
+        "say so and suggest contacting the clinic. "+
+        "Question: %s\n\n"+
+        "Keep the response short. Respond in %s.",
+        req.Message,
+        locale.EnglishName(),
+    ) + knowledgeContext(passages)
+
+    actions := []models.Action{
+        {
+            Type:  "call",
+            Label: i18n.T(locale, "chat.action.call_clinic"),
+        },
+    }
+
+    aiResponse, err := s.aiService.GenerateResponse(prompt)
+    if err != nil {
+        log.Println("knowledge answer error", err)
+        return s.quoteKnowledge(models.IntentUnknown, passages, locale, actions)
+    }
+    
+    return &models.ChatResponse{
+        Response: aiResponse + citeKnowledge(passages, locale),
+        Intent:   models.IntentUnknown,
+        Actions:  actions,
+        Data:     knowledgeData(passages),
+    }
+}
+
+// quoteKnowledge replies with the best passage verbatim when the AI model
+// is unavailable
+func (s *ChatbotService) quoteKnowledge(intent models.MessageIntent, passages []models.KnowledgePassage, locale i18n.Locale, actions []models.Action) *models.ChatResponse {
+    return &models.ChatResponse{
+        Response: i18n.T(locale, "chat.knowledge_quote", passages[0].Text) + citeKnowledge(passages[:1], locale),
+        Intent:   intent,
+        Actions:  actions,
+        Data:     knowledgeData(passages[:1]),
+    }
+}
+
+// knowledgeContext numbers passages for the prompt so the model can cite them
+func knowledgeContext(passages []models.KnowledgePassage) string {
+    if len(passages) == 0 {
+        return ""
+    }
+    
+    var b strings.Builder
+    b.WriteString("\n\nClinic information (cite the numbers you use, like [1]):\n")
+    for i, p := range passages {
+        fmt.Fprintf(&b, "[%d] %s: %s\n", i+1, p.Title, p.Text)
+    }
+    return b.String()
+}
+
+// citeKnowledge lists the documents behind the numbered passages
+func citeKnowledge(passages []models.KnowledgePassage, locale i18n.Locale) string {
+    if len(passages) == 0 {
+        return ""
+    }
+    
+    lines := make([]string, len(passages))
+    for i, p := range passages {
+        lines[i] = fmt.Sprintf("[%d] %s", i+1, p.Title)
+        if p.Source != "" {
+            lines[i] += " (" + p.Source + ")"
+        }
+    }
+    return i18n.T(locale, "chat.sources", strings.Join(lines, "\n"))
+}
+
+func knowledgeData(passages []models.KnowledgePassage) map[string]interface{} {
+    if len(passages) == 0 {
+        return nil
+    }
+    return map[string]interface{}{"sources": passages}
+}
+
+// isDirectoryRequest reports whether a message asks to see the doctors,
+// rather than to book with one
+func (s *ChatbotService) isDirectoryRequest(intent models.MessageIntent, message string) bool {
+    switch intent {
+    case models.IntentEmergency, models.IntentHandoff, models.IntentAppointment:
+        return false
+    }
+    return i18n.IsDoctorsRequest(message)
+}
+
+// handleDoctorDirectory lists the doctors, of one department when the message
+// names it, each with a "Book with doctor" action
+func (s *ChatbotService) handleDoctorDirectory(ctx context.Context, message string, locale i18n.Locale) (*models.ChatResponse, error) {
+    departmentID, departmentName := 0, ""
+    if name := s.ExtractEntities(ctx, message).Department; name != "" {
+        departments, err := s.doctorService.Departments(ctx)
+        if err != nil {
+            log.Println("doctor directory error", err)
+        }
+        for _, d := range departments {
+            if strings.Contains(strings.ToLower(d.Name), strings.ToLower(name)) {
+                departmentID, departmentName = d.ID, d.Name
+                break
+            }
+        }
+    }
+    
+    doctors, err := s.doctorService.List(ctx, departmentID, false)
+    if err != nil {
+        log.Println("doctor directory error", err)
+    }
+    if len(doctors) == 0 {
+        return &models.ChatResponse{
+            Response: i18n.T(locale, "doctors.empty"),
+            Intent:   models.IntentClinicInfo,
+        }, nil
+    }
+    
+    text := i18n.T(locale, "doctors.body_all")
+    if departmentName != "" {
+        text = i18n.T(locale, "doctors.body_department", departmentName)
+    }
+    actions := []models.Action{}
+    for i, d := range doctors {
+        text += "\n• " + d.Name
+        if len(d.Qualifications) > 0 {
+            text += " (" + strings.Join(d.Qualifications, ", ") + ")"
+        }
+        if i < 10 {
+            // The client opens its booking form at the date step
+            actions = append(actions, models.Action{
+                Type:  "book_with_doctor",
+                Label: i18n.T(locale, "doctors.book") + ": " + d.Name,
+                Payload: map[string]interface{}{
+                    "doctor_id":     d.EmployeeID,
+                    "doctor_name":   d.Name,
+                    "department_id": d.DepartmentID,
+                },
+            })
+        }
+    }
+    
+    return &models.ChatResponse{
+        Response: text,
+        Intent:   models.IntentClinicInfo,
+        Actions:  actions,
+        Data: map[string]interface{}{
+            "doctors": doctors,
         },
     }, nil
 }
 
-func (s *ChatbotService) handleGreeting() (*models.ChatResponse, error) {
+func (s *ChatbotService) handleGreeting(ctx context.Context, locale i18n.Locale) (*models.ChatResponse, error) {
     currentHour := time.Now().Hour()
-    greeting := "Hello"
+    var greeting string
     
     if currentHour < 12 {
-        greeting = "Good morning"
+        greeting = i18n.T(locale, "chat.good_morning")
     } else if currentHour < 18 {
-        greeting = "Good afternoon"
+        greeting = i18n.T(locale, "chat.good_afternoon")
     } else {
-        greeting = "Good evening"
+        greeting = i18n.T(locale, "chat.good_evening")
     }
     
     return &models.ChatResponse{
-        Response: fmt.Sprintf("%s! Welcome to %s. I'm here to help you with:\n\n", greeting, s.clinicInfo["name"]) +
-                 "• 📅 Booking appointments\n" +
-                 "• 🏥 Clinic information\n" +
-                 "• 💊 General health queries\n" +
-                 "• 🚨 Emergency assistance\n" +
-                 "• 📋 Managing your appointments\n\n" +
-                 "How can I assist you today?",
+        Response: i18n.T(locale, "chat.greeting", greeting, s.clinicService.Profile(ctx).Name),
         Intent: models.IntentGreeting,
         Actions: []models.Action{
             {
                 Type:  "quick_action",
-                Label: "Book Appointment",
+                Label: i18n.T(locale, "chat.action.quick_book"),
                 Payload: map[string]interface{}{
                     "action": "book_appointment",
                 },
             },
             {
                 Type:  "quick_action",
-                Label: "Clinic Hours",
+                Label: i18n.T(locale, "chat.action.clinic_hours"),
                 Payload: map[string]interface{}{
                     "action": "clinic_hours",
                 },
             },
             {
                 Type:  "quick_action",
-                Label: "Emergency Help",
+                Label: i18n.T(locale, "chat.action.emergency_help"),
                 Payload: map[string]interface{}{
                     "action": "emergency",
                 },
@@ -364,45 +832,47 @@ func (s *ChatbotService) handleGreeting() (*models.ChatResponse, error) {
     }, nil // Added nil error return
 }
 
-func (s *ChatbotService) handleUnknown(req models.ChatRequest) (*models.ChatResponse, error) {
+func (s *ChatbotService) handleUnknown(ctx context.Context, req models.ChatRequest, locale i18n.Locale) (*models.ChatResponse, error) {
+    // Questions the clinic FAQ covers are answered from it
+    if passages := s.searchKnowledge(ctx, req.Message); len(passages) > 0 {
+        return s.answerFromKnowledge(req, locale, passages), nil
+    }
     // Try to use AI for unknown queries
-    return s.handleMedicalQuery(req)
+    return s.answerMedicalQuery(req, locale, nil)
 }
 
 // Additional handler methods
 
-func (s *ChatbotService) handleAppointmentCancellation(req models.ChatRequest) (*models.ChatResponse, error) {
+func (s *ChatbotService) handleAppointmentCancellation(req models.ChatRequest, locale i18n.Locale) (*models.ChatResponse, error) {
     // In a real implementation, you would extract appointment ID from context or ask user
     return &models.ChatResponse{
-        Response: "I can help you cancel your appointment. Please provide your appointment ID or " +
-                 "let me look up your upcoming appointments.",
+        Response: i18n.T(locale, "chat.cancel_prompt"),
         Intent: models.IntentAppointment,
         Actions: []models.Action{
             {
                 Type:  "lookup_appointments",
-                Label: "View My Appointments",
+                Label: i18n.T(locale, "chat.action.my_appointments"),
             },
             {
                 Type:  "enter_appointment_id",
-                Label: "Enter Appointment ID",
+                Label: i18n.T(locale, "chat.action.enter_appt_id"),
             },
         },
     }, nil
 }
 
-func (s *ChatbotService) handleAppointmentReschedule(req models.ChatRequest) (*models.ChatResponse, error) {
+func (s *ChatbotService) handleAppointmentReschedule(req models.ChatRequest, locale i18n.Locale) (*models.ChatResponse, error) {
     return &models.ChatResponse{
-        Response: "I can help you reschedule your appointment. First, let me find your current appointment. " +
-                 "Please provide your appointment ID or shall I look up your appointments?",
+        Response: i18n.T(locale, "chat.reschedule_prompt"),
         Intent: models.IntentAppointment,
         Actions: []models.Action{
             {
                 Type:  "lookup_appointments",
-                Label: "View My Appointments",
+                Label: i18n.T(locale, "chat.action.my_appointments"),
             },
             {
                 Type:  "enter_appointment_id",
-                Label: "Enter Appointment ID",
+                Label: i18n.T(locale, "chat.action.enter_appt_id"),
             },
         },
     }, nil
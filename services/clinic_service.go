@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrClinicProfileNotFound is returned when no profile has been created
+	ErrClinicProfileNotFound = errors.New("clinic profile not found")
+	// ErrClinicProfileExists is returned when creating a second profile
+	ErrClinicProfileExists = errors.New("clinic profile already exists")
+)
+
+// ClinicValidationError describes an invalid clinic profile
+type ClinicValidationError struct {
+	Reason string
+}
+
+func (e *ClinicValidationError) Error() string {
+	return "invalid clinic profile: " + e.Reason
+}
+
+// Weekdays in display order, as stored in DayHours.Weekday
+var clinicWeekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// clinicProfileID is the _id of the one clinic profile. The unique _id index
+// makes the second of two concurrent creates fail.
+var clinicProfileID = primitive.ObjectID{11: 1}
+
+// How long the chatbot reuses a loaded profile; other instances see admin
+// edits after at most this long
+const clinicProfileCacheTTL = time.Minute
+
+type ClinicService struct {
+	collection *mongo.Collection
+
+	mu       sync.RWMutex
+	cached   *models.ClinicProfile
+	cachedAt time.Time
+}
+
+func NewClinicService() *ClinicService {
+	return &ClinicService{
+		collection: database.GetMongoDB().Collection("clinic_profile"),
+	}
+}
+
+// defaultClinicProfile is shown until staff create a profile
+func defaultClinicProfile() models.ClinicProfile {
+	weekday := func(day, open, close string) models.DayHours {
+		return models.DayHours{Weekday: day, Open: open, Close: close}
+	}
+	return models.ClinicProfile{
+		Name:      "HealthCare Clinic",
+		Addresses: []models.ClinicAddress{{Line: "123 Medical Center, Downtown"}},
+		Phones:    []models.ClinicPhone{{Label: "Reception", Number: "+91-98765-43210"}},
+		Hours: []models.DayHours{
+			weekday("monday", "09:00", "18:00"),
+			weekday("tuesday", "09:00", "18:00"),
+			weekday("wednesday", "09:00", "18:00"),
+			weekday("thursday", "09:00", "18:00"),
+			weekday("friday", "09:00", "18:00"),
+			weekday("saturday", "09:00", "14:00"),
+		},
+		Services:    []string{"General Medicine", "Pediatrics", "Cardiology", "Dermatology"},
+		Departments: []string{"General Medicine", "Pediatrics", "Cardiology", "Dermatology"},
+	}
+}
+
+// Get returns the stored profile
+func (s *ClinicService) Get(ctx context.Context) (*models.ClinicProfile, error) {
+	var profile models.ClinicProfile
+	err := s.collection.FindOne(ctx, bson.M{}).Decode(&profile)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrClinicProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch clinic profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// Profile returns the profile for display: the stored one, or the default
+// when none exists or Mongo is unavailable. The default is only cached when
+// there is no profile; after a failed lookup the next call tries again.
+func (s *ClinicService) Profile(ctx context.Context) models.ClinicProfile {
+	s.mu.RLock()
+	cached, cachedAt := s.cached, s.cachedAt
+	s.mu.RUnlock()
+	if cached != nil && time.Since(cachedAt) < clinicProfileCacheTTL {
+		return *cached
+	}
+
+	profile, err := s.Get(ctx)
+	if errors.Is(err, ErrClinicProfileNotFound) {
+		fallback := defaultClinicProfile()
+		profile = &fallback
+	} else if err != nil {
+		log.Println("clinic profile lookup error", err)
+		if cached != nil {
+			return *cached
+		}
+		return defaultClinicProfile()
+	}
+
+	s.mu.Lock()
+	s.cached, s.cachedAt = profile, time.Now()
+	s.mu.Unlock()
+	return *profile
+}
+
+// Create stores the clinic profile; only one may exist
+func (s *ClinicService) Create(ctx context.Context, profile models.ClinicProfile) (*models.ClinicProfile, error) {
+	if err := validateClinicProfile(&profile); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	profile.ID = clinicProfileID
+	profile.CreatedAt = now
+	profile.UpdatedAt = now
+	if _, err := s.collection.InsertOne(ctx, profile); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrClinicProfileExists
+		}
+		return nil, fmt.Errorf("failed to save clinic profile: %w", err)
+	}
+
+	s.invalidate()
+	return &profile, nil
+}
+
+// Update replaces the stored profile
+func (s *ClinicService) Update(ctx context.Context, profile models.ClinicProfile) (*models.ClinicProfile, error) {
+	if err := validateClinicProfile(&profile); err != nil {
+		return nil, err
+	}
+
+	var updated models.ClinicProfile
+	err := s.collection.FindOneAndUpdate(ctx,
+		bson.M{},
+		bson.M{"$set": bson.M{
+			"name":          profile.Name,
+			"addresses":     profile.Addresses,
+			"phones":        profile.Phones,
+			"email":         profile.Email,
+			"website":       profile.Website,
+			"hours":         profile.Hours,
+			"staffed_hours": profile.StaffedHours,
+			"holidays":      profile.Holidays,
+			"services":      profile.Services,
+			"departments":   profile.Departments,
+			"updated_at":    time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrClinicProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update clinic profile: %w", err)
+	}
+
+	s.invalidate()
+	return &updated, nil
+}
+
+// Delete removes the profile; the chatbot falls back to the default
+func (s *ClinicService) Delete(ctx context.Context) error {
+	result, err := s.collection.DeleteMany(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to delete clinic profile: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrClinicProfileNotFound
+	}
+
+	s.invalidate()
+	return nil
+}
+
+func (s *ClinicService) invalidate() {
+	s.mu.Lock()
+	s.cached = nil
+	s.mu.Unlock()
+}
+
+// validateClinicProfile checks hours and holidays and normalizes weekday names
+func validateClinicProfile(profile *models.ClinicProfile) error {
+	if strings.TrimSpace(profile.Name) == "" {
+		return &ClinicValidationError{Reason: "name is required"}
+	}
+
+	if err := validateHours("hours", profile.Hours); err != nil {
+		return err
+	}
+	if err := validateHours("staffed_hours", profile.StaffedHours); err != nil {
+		return err
+	}
+
+	for _, holiday := range profile.Holidays {
+		if _, err := time.Parse("2006-01-02", holiday.Date); err != nil {
+			return &ClinicValidationError{Reason: fmt.Sprintf("holiday date %q must be YYYY-MM-DD", holiday.Date)}
+		}
+	}
+	return nil
+}
+
+// validateHours checks one weekly schedule and normalizes weekday names
+func validateHours(field string, hours []models.DayHours) error {
+	seen := make(map[string]bool)
+	for i, h := range hours {
+		day := strings.ToLower(strings.TrimSpace(h.Weekday))
+		if !isClinicWeekday(day) {
+			return &ClinicValidationError{Reason: fmt.Sprintf("%s: unknown weekday %q", field, h.Weekday)}
+		}
+		if seen[day] {
+			return &ClinicValidationError{Reason: fmt.Sprintf("%s: %s listed twice", field, day)}
+		}
+		seen[day] = true
+
+		open, err1 := time.Parse("15:04", h.Open)
+		close, err2 := time.Parse("15:04", h.Close)
+		if err1 != nil || err2 != nil {
+			return &ClinicValidationError{Reason: fmt.Sprintf("%s: %s hours must be HH:MM", field, day)}
+		}
+		// Closing before opening means after midnight
+		if open.Equal(close) {
+			return &ClinicValidationError{Reason: fmt.Sprintf("%s: %s opens and closes at the same time", field, day)}
+		}
+		hours[i].Weekday = day
+	}
+	return nil
+}
+
+func isClinicWeekday(day string) bool {
+	for _, d := range clinicWeekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatClinicHours summarizes opening hours, grouping consecutive days with
+// the same hours: "Mon-Fri: 09:00-18:00, Sat: 09:00-14:00"
+func FormatClinicHours(hours []models.DayHours) string {
+	byDay := make(map[string]models.DayHours)
+	for _, h := range hours {
+		byDay[h.Weekday] = h
+	}
+
+	short := func(day string) string {
+		return strings.ToUpper(day[:1]) + day[1:3]
+	}
+
+	var parts []string
+	for i := 0; i < len(clinicWeekdays); {
+		h, open := byDay[clinicWeekdays[i]]
+		if !open {
+			i++
+			continue
+		}
+
+		j := i
+		for j+1 < len(clinicWeekdays) {
+			next, ok := byDay[clinicWeekdays[j+1]]
+			if !ok || next.Open != h.Open || next.Close != h.Close {
+				break
+			}
+			j++
+		}
+
+		days := short(clinicWeekdays[i])
+		if j > i {
+			days += "-" + short(clinicWeekdays[j])
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s-%s", days, h.Open, h.Close))
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatClinicAddresses joins the addresses, prefixing labels when there is
+// more than one location
+func FormatClinicAddresses(addresses []models.ClinicAddress) string {
+	parts := make([]string, 0, len(addresses))
+	for _, a := range addresses {
+		if a.Label != "" && len(addresses) > 1 {
+			parts = append(parts, a.Label+": "+a.Line)
+		} else {
+			parts = append(parts, a.Line)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FormatClinicPhones joins the phone numbers with their labels
+func FormatClinicPhones(phones []models.ClinicPhone) string {
+	parts := make([]string, 0, len(phones))
+	for _, p := range phones {
+		if p.Label != "" && len(phones) > 1 {
+			parts = append(parts, p.Label+": "+p.Number)
+		} else {
+			parts = append(parts, p.Number)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// useUnreachableMongo points services at a client with no server behind it,
+// so every Mongo call fails quickly
+func useUnreachableMongo(t *testing.T) {
+	t.Helper()
+	client, err := mongo.Connect(context.Background(), options.Client().
+		ApplyURI("mongodb://127.0.0.1:1").
+		SetServerSelectionTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("mongo client: %v", err)
+	}
+	database.UseMongoDB(client.Database("test"))
+}
+
+func TestClinicProfileLookupErrorIsNotCached(t *testing.T) {
+	useUnreachableMongo(t)
+	s := NewClinicService()
+
+	if got := s.Profile(context.Background()); got.Name != defaultClinicProfile().Name {
+		t.Errorf("Profile() = %q, want the default profile", got.Name)
+	}
+	if s.cached != nil {
+		t.Fatal("the default profile was cached after a failed lookup")
+	}
+
+	// A profile loaded earlier is kept through a failed lookup
+	stored := models.ClinicProfile{Name: "Sunrise Clinic"}
+	s.cached, s.cachedAt = &stored, time.Now().Add(-2*clinicProfileCacheTTL)
+	if got := s.Profile(context.Background()); got.Name != "Sunrise Clinic" {
+		t.Errorf("Profile() = %q, want the last loaded profile", got.Name)
+	}
+}
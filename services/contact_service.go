@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ServiceWindow is how long after a user's last inbound message
+// WhatsApp accepts free-form (non-template) messages
+const ServiceWindow = 24 * time.Hour
+
+type ContactService struct {
+	collection *mongo.Collection
+
+	// When set, proactive sends need a recorded opt-in, not just the absence of STOP
+	requireOptIn bool
+
+	// Cache of last inbound timestamps so bot replies don't hit Mongo
+	mu          sync.RWMutex
+	lastInbound map[string]time.Time
+}
+
+func NewContactService() *ContactService {
+	return &ContactService{
+		collection:   database.GetMongoDB().Collection("contacts"),
+		requireOptIn: getEnvOrDefault("WHATSAPP_REQUIRE_OPT_IN", "false") == "true",
+		lastInbound:  make(map[string]time.Time),
+	}
+}
+
+// RecordInbound stores the time of the latest message received from a contact
+func (s *ContactService) RecordInbound(ctx context.Context, phone string, at time.Time) error {
+	s.mu.Lock()
+	if at.After(s.lastInbound[phone]) {
+		s.lastInbound[phone] = at
+	}
+	s.mu.Unlock()
+
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"phone": phone},
+		bson.M{
+			"$max":         bson.M{"last_inbound_at": at},
+			"$set":         bson.M{"updated_at": time.Now()},
+			"$setOnInsert": bson.M{"created_at": time.Now()},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record inbound message: %w", err)
+	}
+	return nil
+}
+
+// LastInbound returns when the contact last wrote to us (zero if never)
+func (s *ContactService) LastInbound(ctx context.Context, phone string) (time.Time, error) {
+	s.mu.RLock()
+	at, ok := s.lastInbound[phone]
+	s.mu.RUnlock()
+	if ok {
+		return at, nil
+	}
+
+	var contact models.Contact
+	err := s.collection.FindOne(ctx, bson.M{"phone": phone}).Decode(&contact)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, fmt.Errorf("failed to fetch contact: %w", err)
+	}
+
+	s.mu.Lock()
+	s.lastInbound[phone] = contact.LastInboundAt
+	s.mu.Unlock()
+
+	return contact.LastInboundAt, nil
+}
+
+// IsWithinServiceWindow reports whether free-form messages can be sent to phone
+func (s *ContactService) IsWithinServiceWindow(ctx context.Context, phone string) (bool, time.Time, error) {
+	at, err := s.LastInbound(ctx, phone)
+	if err != nil {
+		return false, at, err
+	}
+	return !at.IsZero() && time.Since(at) < ServiceWindow, at, nil
+}
+
+// ErrContactOptedOut is returned for proactive sends to contacts who sent STOP
+var ErrContactOptedOut = errors.New("contact has opted out of WhatsApp messages")
+
+// ErrNoOptIn is returned for proactive sends when explicit opt-in is required
+// and the contact has never opted in
+var ErrNoOptIn = errors.New("contact has not opted in to WhatsApp messages")
+
+// Get returns a contact by phone number
+func (s *ContactService) Get(ctx context.Context, phone string) (*models.Contact, error) {
+	var contact models.Contact
+	err := s.collection.FindOne(ctx, bson.M{"phone": phone}).Decode(&contact)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contact: %w", err)
+	}
+	return &contact, nil
+}
+
+// OptIn records consent to receive proactive messages
+func (s *ContactService) OptIn(ctx context.Context, phone, source string) (*models.Contact, error) {
+	now := time.Now()
+	return s.updateConsent(ctx, phone, bson.M{
+		"$set": bson.M{
+			"opted_out":     false,
+			"opt_in_source": source,
+			"opted_in_at":   now,
+			"updated_at":    now,
+		},
+		"$unset":       bson.M{"opted_out_at": ""},
+		"$push":        bson.M{"consent_log": models.ConsentEvent{Action: "opt_in", Source: source, At: now}},
+		"$setOnInsert": bson.M{"created_at": now},
+	})
+}
+
+// OptOut records that the contact no longer wants proactive messages
+func (s *ContactService) OptOut(ctx context.Context, phone, source string) (*models.Contact, error) {
+	now := time.Now()
+	return s.updateConsent(ctx, phone, bson.M{
+		"$set": bson.M{
+			"opted_out":    true,
+			"opted_out_at": now,
+			"updated_at":   now,
+		},
+		"$push":        bson.M{"consent_log": models.ConsentEvent{Action: "opt_out", Source: source, At: now}},
+		"$setOnInsert": bson.M{"created_at": now},
+	})
+}
+
+// RecordImplicitOptIn marks a contact who messaged us as opted in, unless they
+// already have a consent record
+func (s *ContactService) RecordImplicitOptIn(ctx context.Context, phone string) error {
+	now := time.Now()
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"phone": phone, "opted_in_at": bson.M{"$exists": false}, "opted_out": bson.M{"$ne": true}},
+		bson.M{
+			"$set":  bson.M{"opt_in_source": models.OptInSourceInbound, "opted_in_at": now},
+			"$push": bson.M{"consent_log": models.ConsentEvent{Action: "opt_in", Source: models.OptInSourceInbound, At: now}},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record opt-in: %w", err)
+	}
+	return nil
+}
+
+// CheckConsent returns nil if proactive messages may be sent to phone
+func (s *ContactService) CheckConsent(ctx context.Context, phone string) error {
+	contact, err := s.Get(ctx, phone)
+	if err != nil {
+		return err
+	}
+	if contact != nil && contact.OptedOut {
+		return ErrContactOptedOut
+	}
+	if s.requireOptIn && (contact == nil || contact.OptedInAt == nil) {
+		return ErrNoOptIn
+	}
+	return nil
+}
+
+// RecordWhatsAppStatus caches whether phone has a WhatsApp account
+func (s *ContactService) RecordWhatsAppStatus(ctx context.Context, phone, status string) error {
+	now := time.Now()
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"phone": phone},
+		bson.M{
+			"$set":         bson.M{"whatsapp_status": status, "whatsapp_checked_at": now, "updated_at": now},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record WhatsApp status: %w", err)
+	}
+	return nil
+}
+
+func (s *ContactService) updateConsent(ctx context.Context, phone string, update bson.M) (*models.Contact, error) {
+	var contact models.Contact
+	err := s.collection.FindOneAndUpdate(ctx,
+		bson.M{"phone": phone},
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&contact)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update consent: %w", err)
+	}
+	return &contact, nil
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/i18n"
+	"clinic-chatbot-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrDoctorNotFound is returned for employee IDs without a profile
+	ErrDoctorNotFound = errors.New("doctor profile not found")
+	// ErrDoctorExists is returned when an employee already has a profile
+	ErrDoctorExists = errors.New("doctor profile already exists")
+)
+
+// DoctorValidationError describes an invalid doctor profile
+type DoctorValidationError struct {
+	Reason string
+}
+
+func (e *DoctorValidationError) Error() string {
+	return "invalid doctor profile: " + e.Reason
+}
+
+// DoctorService keeps the doctor directory: profiles of HMS doctors with the
+// qualifications, languages, consulting days and fees patients ask about
+type DoctorService struct {
+	collection *mongo.Collection
+}
+
+func NewDoctorService() *DoctorService {
+	return &DoctorService{
+		collection: database.GetMongoDB().Collection("doctor_profiles"),
+	}
+}
+
+// List returns doctors sorted by name, optionally in one department.
+// Hidden profiles are only included for staff.
+func (s *DoctorService) List(ctx context.Context, departmentID int, includeHidden bool) ([]models.DoctorProfile, error) {
+	filter := bson.M{}
+	if departmentID != 0 {
+		filter["department_id"] = departmentID
+	}
+	if !includeHidden {
+		filter["hidden"] = bson.M{"$ne": true}
+	}
+
+	cursor, err := s.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list doctors: %w", err)
+	}
+
+	doctors := []models.DoctorProfile{}
+	if err := cursor.All(ctx, &doctors); err != nil {
+		return nil, fmt.Errorf("failed to decode doctors: %w", err)
+	}
+	return doctors, nil
+}
+
+// Departments returns the departments that have visible doctors, by name
+func (s *DoctorService) Departments(ctx context.Context) ([]models.DoctorDepartment, error) {
+	doctors, err := s.List(ctx, 0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	departments := []models.DoctorDepartment{}
+	for _, d := range doctors {
+		if !seen[d.DepartmentID] {
+			seen[d.DepartmentID] = true
+			departments = append(departments, models.DoctorDepartment{ID: d.DepartmentID, Name: d.DepartmentName})
+		}
+	}
+	sort.Slice(departments, func(i, j int) bool { return departments[i].Name < departments[j].Name })
+	return departments, nil
+}
+
+// Get returns the profile of an HMS employee
+func (s *DoctorService) Get(ctx context.Context, employeeID int) (*models.DoctorProfile, error) {
+	var doctor models.DoctorProfile
+	err := s.collection.FindOne(ctx, bson.M{"employee_id": employeeID}).Decode(&doctor)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrDoctorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch doctor: %w", err)
+	}
+	return &doctor, nil
+}
+
+// Create adds a doctor to the directory
+func (s *DoctorService) Create(ctx context.Context, doctor models.DoctorProfile) (*models.DoctorProfile, error) {
+	if err := validateDoctorProfile(&doctor); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	doctor.CreatedAt = now
+	doctor.UpdatedAt = now
+	doctor.ID = primitive.NewObjectID()
+	_, err := s.collection.InsertOne(ctx, doctor)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrDoctorExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save doctor: %w", err)
+	}
+	return &doctor, nil
+}
+
+// Update replaces the profile of an HMS employee
+func (s *DoctorService) Update(ctx context.Context, employeeID int, doctor models.DoctorProfile) (*models.DoctorProfile, error) {
+	doctor.EmployeeID = employeeID
+	if err := validateDoctorProfile(&doctor); err != nil {
+		return nil, err
+	}
+
+	var updated models.DoctorProfile
+	err := s.collection.FindOneAndUpdate(ctx,
+		bson.M{"employee_id": employeeID},
+		bson.M{"$set": bson.M{
+			"name":            doctor.Name,
+			"department_id":   doctor.DepartmentID,
+			"department_name": doctor.DepartmentName,
+			"qualifications":  doctor.Qualifications,
+			"languages":       doctor.Languages,
+			"consulting_days": doctor.ConsultingDays,
+			"fee":             doctor.Fee,
+			"currency":        doctor.Currency,
+			"photo_url":       doctor.PhotoURL,
+			"bio":             doctor.Bio,
+			"hidden":          doctor.Hidden,
+			"updated_at":      time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrDoctorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update doctor: %w", err)
+	}
+	return &updated, nil
+}
+
+// Delete removes a doctor from the directory
+func (s *DoctorService) Delete(ctx context.Context, employeeID int) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"employee_id": employeeID})
+	if err != nil {
+		return fmt.Errorf("failed to delete doctor: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrDoctorNotFound
+	}
+	return nil
+}
+
+func validateDoctorProfile(doctor *models.DoctorProfile) error {
+	doctor.Name = strings.TrimSpace(doctor.Name)
+	switch {
+	case doctor.EmployeeID <= 0:
+		return &DoctorValidationError{Reason: "employee_id must be the HMS employee ID"}
+	case doctor.Name == "":
+		return &DoctorValidationError{Reason: "name is required"}
+	case doctor.DepartmentID <= 0:
+		return &DoctorValidationError{Reason: "department_id must be the HMS department ID"}
+	case doctor.Fee < 0:
+		return &DoctorValidationError{Reason: "fee cannot be negative"}
+	case doctor.PhotoURL != "" && !strings.HasPrefix(doctor.PhotoURL, "https://"):
+		// WhatsApp only fetches header images over HTTPS
+		return &DoctorValidationError{Reason: "photo_url must be an https:// URL"}
+	}
+
+	for i, day := range doctor.ConsultingDays {
+		day = strings.ToLower(strings.TrimSpace(day))
+		if !isClinicWeekday(day) {
+			return &DoctorValidationError{Reason: fmt.Sprintf("unknown consulting day %q", doctor.ConsultingDays[i])}
+		}
+		doctor.ConsultingDays[i] = day
+	}
+	return nil
+}
+
+// DoctorCardText is the body of a doctor profile card: qualifications,
+// languages, consulting days and fee, each line only when known
+func DoctorCardText(doctor models.DoctorProfile, locale i18n.Locale) string {
+	var lines []string
+	if doctor.DepartmentName != "" {
+		lines = append(lines, "🏥 "+doctor.DepartmentName)
+	}
+	if len(doctor.Qualifications) > 0 {
+		lines = append(lines, i18n.T(locale, "doctors.qualifications", strings.Join(doctor.Qualifications, ", ")))
+	}
+	if len(doctor.Languages) > 0 {
+		lines = append(lines, i18n.T(locale, "doctors.languages", strings.Join(doctor.Languages, ", ")))
+	}
+	if len(doctor.ConsultingDays) > 0 {
+		days := make([]string, len(doctor.ConsultingDays))
+		for i, day := range doctor.ConsultingDays {
+			days[i] = strings.ToUpper(day[:1]) + day[1:3]
+		}
+		lines = append(lines, i18n.T(locale, "doctors.days", strings.Join(days, ", ")))
+	}
+	if doctor.Fee > 0 {
+		lines = append(lines, i18n.T(locale, "doctors.fee", FormatFee(doctor.Fee, doctor.Currency)))
+	}
+	if doctor.Bio != "" {
+		lines = append(lines, "", doctor.Bio)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatFee formats a consultation fee, e.g. "₹500" or "USD 40.50"
+func FormatFee(fee float64, currency string) string {
+	amount := strconv.FormatFloat(fee, 'f', -1, 64)
+	if strings.Contains(amount, ".") {
+		amount = strconv.FormatFloat(fee, 'f', 2, 64)
+	}
+	switch strings.ToUpper(currency) {
+	case "", "INR":
+		return "₹" + amount
+	default:
+		return strings.ToUpper(currency) + " " + amount
+	}
+}
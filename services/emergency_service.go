@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"clinic-chatbot-backend/config"
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/notify"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrAlertNotFound is returned when an alert ID or code does not exist
+	ErrAlertNotFound = errors.New("emergency alert not found")
+	// ErrAlertAcknowledged is returned when acknowledging an alert twice
+	ErrAlertAcknowledged = errors.New("emergency alert already acknowledged")
+)
+
+// publicEmergencyNumbers are national emergency numbers by region
+var publicEmergencyNumbers = map[string]string{
+	"IN": "112",
+	"US": "911",
+	"CA": "911",
+	"GB": "999",
+	"AE": "999",
+	"SG": "995",
+	"AU": "000",
+	"NZ": "111",
+}
+
+// How often unacknowledged alerts are checked for escalation
+const escalationInterval = 30 * time.Second
+
+type EmergencyService struct {
+	collection  *mongo.Collection
+	dispatcher  *notify.Dispatcher
+	cfg         config.EmergencyConfig
+	staffPhones map[string]bool // digits only
+}
+
+func NewEmergencyService(cfg config.EmergencyConfig, dispatcher *notify.Dispatcher) *EmergencyService {
+	staffPhones := make(map[string]bool)
+	for _, phone := range append(append([]string{}, cfg.StaffWhatsApp...), cfg.StaffSMS...) {
+		staffPhones[digitsOnly(phone)] = true
+	}
+	if len(cfg.StaffWhatsApp) > 0 && cfg.StaffTemplate == "" {
+		log.Println("WARNING: ONCALL_WHATSAPP_TEMPLATE is not set; staff outside the 24-hour window may not receive WhatsApp alerts")
+	}
+
+	return &EmergencyService{
+		collection:  database.GetMongoDB().Collection("emergency_alerts"),
+		dispatcher:  dispatcher,
+		cfg:         cfg,
+		staffPhones: staffPhones,
+	}
+}
+
+// PublicNumber returns the emergency number patients should call
+func (s *EmergencyService) PublicNumber() string {
+	if s.cfg.Number != "" {
+		return s.cfg.Number
+	}
+	if number, ok := publicEmergencyNumbers[strings.ToUpper(s.cfg.Region)]; ok {
+		return number
+	}
+	return "112" // reachable from mobile phones in most countries
+}
+
+// ClinicLine returns the clinic's emergency line, or the public number when
+// the clinic has none
+func (s *EmergencyService) ClinicLine() string {
+	if s.cfg.ClinicLine != "" {
+		return s.cfg.ClinicLine
+	}
+	return s.PublicNumber()
+}
+
+// IsStaff reports whether a phone number belongs to on-call staff
+func (s *EmergencyService) IsStaff(phone string) bool {
+	return s.staffPhones[digitsOnly(phone)]
+}
+
+// Raise records an emergency alert and notifies on-call staff. Further
+// messages from a session with an open alert do not raise a new one.
+func (s *EmergencyService) Raise(ctx context.Context, channel models.MessageChannel, sessionID, contact, message string) (*models.EmergencyAlert, error) {
+	var existing models.EmergencyAlert
+	err := s.collection.FindOne(ctx, bson.M{"session_id": sessionID, "status": models.AlertOpen}).Decode(&existing)
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("failed to look up open alert: %w", err)
+	}
+
+	now := time.Now()
+	alert := models.EmergencyAlert{
+		ID:             primitive.NewObjectID(),
+		Channel:        channel,
+		SessionID:      sessionID,
+		Contact:        contact,
+		Message:        message,
+		Status:         models.AlertOpen,
+		LastNotifiedAt: now,
+		CreatedAt:      now,
+	}
+	// The trailing counter bytes of the ObjectID keep codes unique
+	alert.Code = alert.ID.Hex()[18:]
+
+	if _, err := s.collection.InsertOne(ctx, alert); err != nil {
+		return nil, fmt.Errorf("failed to save emergency alert: %w", err)
+	}
+
+	go s.dispatch(alert, 0)
+	return &alert, nil
+}
+
+// Acknowledge marks an alert handled and stops escalation
+func (s *EmergencyService) Acknowledge(ctx context.Context, id, agent string) (*models.EmergencyAlert, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrAlertNotFound
+	}
+	return s.acknowledge(ctx, bson.M{"_id": oid}, agent)
+}
+
+// AcknowledgeByCode acknowledges an alert by the short code sent to staff
+func (s *EmergencyService) AcknowledgeByCode(ctx context.Context, code, agent string) (*models.EmergencyAlert, error) {
+	return s.acknowledge(ctx, bson.M{"code": strings.ToLower(code)}, agent)
+}
+
+func (s *EmergencyService) acknowledge(ctx context.Context, filter bson.M, agent string) (*models.EmergencyAlert, error) {
+	now := time.Now()
+	openFilter := bson.M{"status": models.AlertOpen}
+	for k, v := range filter {
+		openFilter[k] = v
+	}
+
+	var alert models.EmergencyAlert
+	err := s.collection.FindOneAndUpdate(ctx,
+		openFilter,
+		bson.M{"$set": bson.M{
+			"status":          models.AlertAcknowledged,
+			"acknowledged_by": agent,
+			"acknowledged_at": now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&alert)
+	if err == nil {
+		return &alert, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("failed to acknowledge alert: %w", err)
+	}
+
+	// Distinguish a missing alert from one already acknowledged
+	count, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acknowledge alert: %w", err)
+	}
+	if count > 0 {
+		return nil, ErrAlertAcknowledged
+	}
+	return nil, ErrAlertNotFound
+}
+
+// Get returns a single alert
+func (s *EmergencyService) Get(ctx context.Context, id string) (*models.EmergencyAlert, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrAlertNotFound
+	}
+
+	var alert models.EmergencyAlert
+	if err := s.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&alert); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrAlertNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch alert: %w", err)
+	}
+	return &alert, nil
+}
+
+// List returns alerts, newest first, optionally filtered by status
+func (s *EmergencyService) List(ctx context.Context, status models.AlertStatus, limit int) ([]models.EmergencyAlert, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	cursor, err := s.collection.Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	alerts := []models.EmergencyAlert{}
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to decode alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// Run escalates unacknowledged alerts until ctx is cancelled
+func (s *EmergencyService) Run(ctx context.Context) {
+	ticker := time.NewTicker(escalationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.escalateDue(ctx)
+		}
+	}
+}
+
+// escalateDue re-sends every open alert whose acknowledgement timeout has
+// passed
+func (s *EmergencyService) escalateDue(ctx context.Context) {
+	for {
+		var alert models.EmergencyAlert
+		// Claim one alert at a time so concurrent instances don't double-send
+		err := s.collection.FindOneAndUpdate(ctx,
+			bson.M{
+				"status":           models.AlertOpen,
+				"escalation_level": bson.M{"$lt": s.cfg.MaxEscalations},
+				"last_notified_at": bson.M{"$lte": time.Now().Add(-s.cfg.AckTimeout)},
+			},
+			bson.M{
+				"$inc": bson.M{"escalation_level": 1},
+				"$set": bson.M{"last_notified_at": time.Now()},
+			},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&alert)
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				log.Println("emergency escalation error", err)
+			}
+			return
+		}
+
+		log.Printf("Escalating emergency alert %s to level %d", alert.Code, alert.EscalationLevel)
+		s.dispatch(alert, alert.EscalationLevel)
+	}
+}
+
+// dispatch notifies the staff for an escalation level and records each delivery
+func (s *EmergencyService) dispatch(alert models.EmergencyAlert, level int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	msg := s.alertMessage(alert, level)
+	deliveries := []models.AlertDelivery{}
+	for _, to := range s.recipients() {
+		delivery := models.AlertDelivery{Level: level, Channel: to.Channel, Recipient: to.Address, At: time.Now()}
+		if err := s.dispatcher.Send(ctx, to, msg); err != nil {
+			log.Printf("Emergency alert %s to %s %s failed: %v", alert.Code, to.Channel, to.Address, err)
+			delivery.Error = err.Error()
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if len(deliveries) == 0 {
+		log.Printf("Emergency alert %s has no on-call recipients configured", alert.Code)
+		return
+	}
+
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": alert.ID},
+		bson.M{"$push": bson.M{"deliveries": bson.M{"$each": deliveries}}},
+	)
+	if err != nil {
+		log.Println("failed to record alert deliveries", err)
+	}
+}
+
+// recipients returns who to alert. Every configured channel is used from the
+// first alert: a WhatsApp send can be accepted and still never reach a phone,
+// so SMS and email are not held back until the acknowledgement timeout.
+func (s *EmergencyService) recipients() []notify.Recipient {
+	var recipients []notify.Recipient
+	add := func(channel string, addresses []string) {
+		if !s.dispatcher.Has(channel) {
+			return
+		}
+		for _, address := range addresses {
+			if address = strings.TrimSpace(address); address != "" {
+				recipients = append(recipients, notify.Recipient{Channel: channel, Address: address})
+			}
+		}
+	}
+
+	add(notify.ChannelWhatsApp, s.cfg.StaffWhatsApp)
+	add(notify.ChannelSMS, s.cfg.StaffSMS)
+	add(notify.ChannelEmail, s.cfg.StaffEmails)
+	return recipients
+}
+
+func (s *EmergencyService) alertMessage(alert models.EmergencyAlert, level int) notify.Message {
+	subject := "🚨 Emergency alert"
+	if level > 0 {
+		subject = fmt.Sprintf("🚨 ESCALATION %d: emergency alert not acknowledged", level)
+	}
+
+	return notify.Message{
+		Subject: subject,
+		Text: fmt.Sprintf(
+			"Patient: %s (%s)\nMessage: %q\nRaised: %s\n\nReply ACK %s on WhatsApp to acknowledge.",
+			alert.Contact, alert.Channel, alert.Message,
+			alert.CreatedAt.In(ClinicLocation()).Format("02 Jan 15:04"), alert.Code,
+		),
+	}
+}
+
+func digitsOnly(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/notify/sms"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// How often pending critical messages are checked for SMS fallback
+const fallbackCheckInterval = time.Minute
+
+// FallbackService sends critical patient messages (confirmations, reminders)
+// on WhatsApp and resends them by SMS when the recipient is not a WhatsApp
+// user or the message stays undelivered for too long
+type FallbackService struct {
+	collection      *mongo.Collection
+	whatsappService *WhatsAppService
+	smsSender       *sms.TwilioSender
+	fallbackAfter   time.Duration
+}
+
+// NewFallbackService disables the SMS fallback when smsSender is nil
+func NewFallbackService(whatsappService *WhatsAppService, smsSender *sms.TwilioSender, fallbackAfter time.Duration) *FallbackService {
+	return &FallbackService{
+		collection:      database.GetMongoDB().Collection("critical_messages"),
+		whatsappService: whatsappService,
+		smsSender:       smsSender,
+		fallbackAfter:   fallbackAfter,
+	}
+}
+
+// SendCritical sends a message on WhatsApp, falling back to SMS right away
+// when WhatsApp cannot carry it. Delivery is tracked so Run can fall back
+// later if WhatsApp never reports it delivered. The message is recorded as
+// pending before it is sent, so a status update or a crash right after the
+// send still finds it.
+func (s *FallbackService) SendCritical(ctx context.Context, kind models.CriticalMessageKind, to, text string) error {
+	if s.smsSender == nil {
+		return s.whatsappService.SendTextMessage(to, text)
+	}
+
+	now := time.Now()
+	msg := models.CriticalMessage{
+		Kind:      kind,
+		To:        s.whatsappService.CleanPhoneNumber(to),
+		Text:      text,
+		Status:    models.DeliveryPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if res, err := s.collection.InsertOne(ctx, msg); err != nil {
+		// Still send; only the fallback is lost
+		log.Println("failed to track critical message", err)
+	} else if id, ok := res.InsertedID.(primitive.ObjectID); ok {
+		msg.ID = id
+	}
+
+	// Numbers known not to have WhatsApp go straight to SMS
+	if ok, err := s.whatsappService.ValidatePhoneNumber(msg.To); err == nil && !ok {
+		msg.FallbackReason = ErrNotWhatsAppUser.Error()
+		return s.sendSMS(ctx, &msg)
+	}
+
+	messageID, err := s.whatsappService.SendTextMessageWithID(msg.To, text)
+	switch {
+	case errors.Is(err, ErrNotWhatsAppUser), errors.Is(err, ErrOutsideServiceWindow):
+		msg.FallbackReason = err.Error()
+		return s.sendSMS(ctx, &msg)
+	case err != nil:
+		s.record(ctx, &msg, bson.M{"status": models.DeliveryFailed, "error": err.Error()})
+		return err
+	}
+
+	s.record(ctx, &msg, bson.M{"whatsapp_message_id": messageID})
+	return nil
+}
+
+// HandleWhatsAppStatus applies a delivery status webhook to the tracked
+// message, falling back to SMS as soon as WhatsApp reports a failure
+func (s *FallbackService) HandleWhatsAppStatus(ctx context.Context, status models.WhatsAppStatus) {
+	filter := bson.M{"whatsapp_message_id": status.ID, "status": models.DeliveryPending}
+
+	switch status.Status {
+	case "delivered", "read":
+		_, err := s.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+			"status":     models.DeliveryDelivered,
+			"updated_at": time.Now(),
+		}})
+		if err != nil {
+			log.Println("failed to record critical message delivery", err)
+		}
+
+	case "failed":
+		reason := "WhatsApp delivery failed"
+		if len(status.Errors) > 0 {
+			reason = fmt.Sprintf("WhatsApp error %d: %s", status.Errors[0].Code, status.Errors[0].Title)
+		}
+		if msg, ok := s.claim(ctx, filter, reason); ok {
+			s.resend(ctx, msg)
+		}
+	}
+}
+
+// Run resends critical messages by SMS once they have been pending longer
+// than the fallback delay, until ctx is cancelled
+func (s *FallbackService) Run(ctx context.Context) {
+	if s.smsSender == nil {
+		return
+	}
+
+	ticker := time.NewTicker(fallbackCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.fallbackOverdue(ctx)
+		}
+	}
+}
+
+func (s *FallbackService) fallbackOverdue(ctx context.Context) {
+	reason := fmt.Sprintf("undelivered on WhatsApp after %s", s.fallbackAfter)
+	for {
+		msg, ok := s.claim(ctx, bson.M{
+			"status":     models.DeliveryPending,
+			"created_at": bson.M{"$lte": time.Now().Add(-s.fallbackAfter)},
+		}, reason)
+		if !ok {
+			return
+		}
+		s.resend(ctx, msg)
+	}
+}
+
+// claim atomically moves one pending message to sms_sent so concurrent
+// status webhooks and the ticker never send the SMS twice
+func (s *FallbackService) claim(ctx context.Context, filter bson.M, reason string) (*models.CriticalMessage, bool) {
+	var msg models.CriticalMessage
+	err := s.collection.FindOneAndUpdate(ctx,
+		filter,
+		bson.M{"$set": bson.M{
+			"status":          models.DeliverySMSSent,
+			"fallback_reason": reason,
+			"updated_at":      time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&msg)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			log.Println("failed to claim critical message", err)
+		}
+		return nil, false
+	}
+	return &msg, true
+}
+
+// resend sends a claimed message by SMS and records the outcome
+func (s *FallbackService) resend(ctx context.Context, msg *models.CriticalMessage) {
+	log.Printf("Resending %s to %s by SMS: %s", msg.Kind, msg.To, msg.FallbackReason)
+
+	set := bson.M{"updated_at": time.Now()}
+	if sid, err := s.smsSender.Send(ctx, msg.To, msg.Text); err != nil {
+		log.Printf("SMS fallback to %s failed: %v", msg.To, err)
+		set["status"] = models.DeliveryFailed
+		set["error"] = err.Error()
+	} else {
+		set["sms_message_id"] = sid
+	}
+
+	if _, err := s.collection.UpdateByID(ctx, msg.ID, bson.M{"$set": set}); err != nil {
+		log.Println("failed to record SMS fallback", err)
+	}
+}
+
+// sendSMS sends a message that never reached WhatsApp and records it
+func (s *FallbackService) sendSMS(ctx context.Context, msg *models.CriticalMessage) error {
+	log.Printf("Sending %s to %s by SMS: %s", msg.Kind, msg.To, msg.FallbackReason)
+
+	set := bson.M{"status": models.DeliverySMSSent, "fallback_reason": msg.FallbackReason}
+	sid, err := s.smsSender.Send(ctx, msg.To, msg.Text)
+	if err != nil {
+		set["status"] = models.DeliveryFailed
+		set["error"] = err.Error()
+	} else {
+		set["sms_message_id"] = sid
+	}
+
+	s.record(ctx, msg, set)
+	return err
+}
+
+// record updates a tracked message after its send
+func (s *FallbackService) record(ctx context.Context, msg *models.CriticalMessage, set bson.M) {
+	if msg.ID.IsZero() {
+		return
+	}
+	set["updated_at"] = time.Now()
+	if _, err := s.collection.UpdateByID(ctx, msg.ID, bson.M{"$set": set}); err != nil {
+		log.Println("failed to track critical message", err)
+	}
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrHouseholdNotFound is returned when a number has no saved patients
+	ErrHouseholdNotFound = errors.New("household not found")
+	// ErrMemberNotFound is returned when a patient is not in the household
+	ErrMemberNotFound = errors.New("household member not found")
+)
+
+// HouseholdService remembers the patients each WhatsApp number books for
+type HouseholdService struct {
+	collection *mongo.Collection
+}
+
+func NewHouseholdService() *HouseholdService {
+	return &HouseholdService{
+		collection: database.GetMongoDB().Collection("households"),
+	}
+}
+
+// Get returns the household of a WhatsApp number
+func (s *HouseholdService) Get(ctx context.Context, phone string) (*models.Household, error) {
+	var household models.Household
+	err := s.collection.FindOne(ctx, bson.M{"phone": phone}).Decode(&household)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrHouseholdNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch household: %w", err)
+	}
+	return &household, nil
+}
+
+// AddMember saves a patient to a number's household. A patient already in it
+// gets their details refreshed; a known relation is not cleared.
+func (s *HouseholdService) AddMember(ctx context.Context, phone string, member models.HouseholdMember) error {
+	now := time.Now()
+	if _, err := s.collection.UpdateOne(ctx,
+		bson.M{"phone": phone},
+		bson.M{
+			"$set":         bson.M{"updated_at": now},
+			"$setOnInsert": bson.M{"members": []models.HouseholdMember{}, "created_at": now},
+		},
+		options.Update().SetUpsert(true),
+	); err != nil {
+		return fmt.Errorf("failed to save household: %w", err)
+	}
+
+	set := bson.M{
+		"members.$.patient_code":  member.PatientCode,
+		"members.$.name":          member.Name,
+		"members.$.date_of_birth": member.DateOfBirth,
+		"members.$.address":       member.Address,
+		"members.$.phone_number":  member.PhoneNumber,
+	}
+	if member.Relation != "" {
+		set["members.$.relation"] = member.Relation
+	}
+	res, err := s.collection.UpdateOne(ctx,
+		bson.M{"phone": phone, "members.patient_id": member.PatientID},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update household member: %w", err)
+	}
+	if res.MatchedCount > 0 {
+		return nil
+	}
+
+	member.AddedAt = now
+	if _, err := s.collection.UpdateOne(ctx,
+		bson.M{"phone": phone, "members.patient_id": bson.M{"$ne": member.PatientID}},
+		bson.M{"$push": bson.M{"members": member}},
+	); err != nil {
+		return fmt.Errorf("failed to add household member: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember unlinks a patient from a number; the HMS record is kept
+func (s *HouseholdService) RemoveMember(ctx context.Context, phone string, patientID int) error {
+	res, err := s.collection.UpdateOne(ctx,
+		bson.M{"phone": phone, "members.patient_id": patientID},
+		bson.M{
+			"$pull": bson.M{"members": bson.M{"patient_id": patientID}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove household member: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
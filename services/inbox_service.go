@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrConversationNotFound is returned when a conversation ID does not exist
+var ErrConversationNotFound = errors.New("conversation not found")
+
+// Sessions without activity for this long are considered expired
+const sessionTTL = 24 * time.Hour
+
+type InboxService struct {
+	collection *mongo.Collection
+}
+
+func NewInboxService() *InboxService {
+	return &InboxService{
+		collection: database.GetMongoDB().Collection("conversations"),
+	}
+}
+
+// RecordInbound upserts the conversation for an incoming user message.
+// A resolved conversation is reopened when the user writes again.
+func (s *InboxService) RecordInbound(ctx context.Context, channel models.MessageChannel, sessionID, userID, text string, intent models.MessageIntent) error {
+	now := time.Now()
+
+	set := bson.M{
+		"user_id":       userID,
+		"channel":       channel,
+		"status":        models.ConversationOpen,
+		"last_message":  text,
+		"last_activity": now,
+		"expires_at":    now.Add(sessionTTL),
+	}
+	if intent != "" {
+		set["last_intent"] = intent
+	}
+
+	update := bson.M{
+		"$set": set,
+		"$inc": bson.M{"unread_count": 1},
+		"$setOnInsert": bson.M{
+			"session_id": sessionID,
+			"state":      "",
+			"context":    bson.M{},
+			"created_at": now,
+		},
+		"$unset": bson.M{"resolved_at": "", "resolved_by": ""},
+	}
+
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"session_id": sessionID},
+		update,
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record inbound message: %w", err)
+	}
+	return nil
+}
+
+// ListConversations returns conversations matching the filter, most recent first
+func (s *InboxService) ListConversations(ctx context.Context, filter models.InboxFilter) ([]models.ConversationSession, int64, error) {
+	query := bson.M{}
+
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Channel != "" {
+		query["channel"] = filter.Channel
+	}
+	if filter.Intent != "" {
+		query["last_intent"] = filter.Intent
+	}
+	if filter.Tag != "" {
+		query["tags"] = filter.Tag
+	}
+	if filter.UnreadOnly {
+		query["unread_count"] = bson.M{"$gt": 0}
+	}
+	switch filter.AssignedAgent {
+	case "":
+	case "none":
+		query["assigned_agent"] = bson.M{"$in": []interface{}{nil, ""}}
+	default:
+		query["assigned_agent"] = filter.AssignedAgent
+	}
+
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 50
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	total, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count conversations: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "last_activity", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+
+	cursor, err := s.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	conversations := []models.ConversationSession{}
+	if err := cursor.All(ctx, &conversations); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode conversations: %w", err)
+	}
+
+	return conversations, total, nil
+}
+
+// GetConversation returns a single conversation by its ID
+func (s *InboxService) GetConversation(ctx context.Context, id string) (*models.ConversationSession, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrConversationNotFound
+	}
+
+	var conversation models.ConversationSession
+	if err := s.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&conversation); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrConversationNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch conversation: %w", err)
+	}
+	return &conversation, nil
+}
+
+// Assign hands a conversation to a staff member
+func (s *InboxService) Assign(ctx context.Context, id, agent string) (*models.ConversationSession, error) {
+	return s.update(ctx, id, bson.M{"$set": bson.M{"assigned_agent": strings.TrimSpace(agent)}})
+}
+
+// AddNote appends an internal note to a conversation
+func (s *InboxService) AddNote(ctx context.Context, id, author, text string) (*models.ConversationSession, error) {
+	note := models.ConversationNote{
+		ID:        primitive.NewObjectID(),
+		Author:    strings.TrimSpace(author),
+		Text:      strings.TrimSpace(text),
+		CreatedAt: time.Now(),
+	}
+	return s.update(ctx, id, bson.M{"$push": bson.M{"notes": note}})
+}
+
+// AddTags adds tags to a conversation, ignoring duplicates
+func (s *InboxService) AddTags(ctx context.Context, id string, tags []string) (*models.ConversationSession, error) {
+	cleaned := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	return s.update(ctx, id, bson.M{"$addToSet": bson.M{"tags": bson.M{"$each": cleaned}}})
+}
+
+// TagSession adds tags to the conversation of a session, e.g. to flag it for
+// reception from the bot
+func (s *InboxService) TagSession(ctx context.Context, sessionID string, tags ...string) error {
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"session_id": sessionID},
+		bson.M{"$addToSet": bson.M{"tags": bson.M{"$each": tags}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to tag conversation: %w", err)
+	}
+	return nil
+}
+
+// NoteSession appends an internal note to the conversation of a session,
+// e.g. to explain a tag the bot added
+func (s *InboxService) NoteSession(ctx context.Context, sessionID, author, text string) error {
+	note := models.ConversationNote{
+		ID:        primitive.NewObjectID(),
+		Author:    author,
+		Text:      strings.TrimSpace(text),
+		CreatedAt: time.Now(),
+	}
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"session_id": sessionID},
+		bson.M{"$push": bson.M{"notes": note}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add conversation note: %w", err)
+	}
+	return nil
+}
+
+// RemoveTag removes a single tag from a conversation
+func (s *InboxService) RemoveTag(ctx context.Context, id, tag string) (*models.ConversationSession, error) {
+	return s.update(ctx, id, bson.M{"$pull": bson.M{"tags": strings.ToLower(tag)}})
+}
+
+// MarkRead clears the unread counter once staff have seen the conversation
+func (s *InboxService) MarkRead(ctx context.Context, id string) (*models.ConversationSession, error) {
+	return s.update(ctx, id, bson.M{"$set": bson.M{"unread_count": 0}})
+}
+
+// Resolve closes a conversation
+func (s *InboxService) Resolve(ctx context.Context, id, agent string) (*models.ConversationSession, error) {
+	return s.update(ctx, id, bson.M{"$set": bson.M{
+		"status":       models.ConversationResolved,
+		"unread_count": 0,
+		"resolved_at":  time.Now(),
+		"resolved_by":  agent,
+	}})
+}
+
+// GetLocale returns the stored language of a session and whether the patient
+// chose it explicitly. An unknown session has no locale.
+func (s *InboxService) GetLocale(ctx context.Context, sessionID string) (string, bool, error) {
+	var conversation models.ConversationSession
+	err := s.collection.FindOne(ctx,
+		bson.M{"session_id": sessionID},
+		options.FindOne().SetProjection(bson.M{"locale": 1, "locale_explicit": 1}),
+	).Decode(&conversation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to get session locale: %w", err)
+	}
+	return conversation.Locale, conversation.LocaleExplicit, nil
+}
+
+// SetLocale stores the language of a session. A detected (non-explicit)
+// locale never overrides one the patient chose.
+func (s *InboxService) SetLocale(ctx context.Context, sessionID, locale string, explicit bool) error {
+	filter := bson.M{"session_id": sessionID}
+	if !explicit {
+		filter["locale_explicit"] = bson.M{"$ne": true}
+	}
+
+	_, err := s.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"locale":          locale,
+		"locale_explicit": explicit,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to set session locale: %w", err)
+	}
+	return nil
+}
+
+// update applies an update to a conversation and returns the new document
+func (s *InboxService) update(ctx context.Context, id string, update bson.M) (*models.ConversationSession, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrConversationNotFound
+	}
+
+	var conversation models.ConversationSession
+	err = s.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": oid},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&conversation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrConversationNotFound
+		}
+		return nil, fmt.Errorf("failed to update conversation: %w", err)
+	}
+	return &conversation, nil
+}
+
+// WhatsAppSessionID derives the inbox session ID for a WhatsApp contact
+func WhatsAppSessionID(phone string) string {
+	return "whatsapp:" + phone
+}
+
+// SMSSessionID derives the inbox session ID for an SMS contact
+func SMSSessionID(phone string) string {
+	return "sms:" + phone
+}
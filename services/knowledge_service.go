@@ -0,0 +1,481 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrDocumentNotFound is returned for unknown knowledge document IDs
+var ErrDocumentNotFound = errors.New("knowledge document not found")
+
+const (
+	// Target chunk size in characters; paragraphs are packed up to this size
+	knowledgeChunkSize = 800
+	// How long the in-memory index is trusted before reloading from Mongo,
+	// so documents ingested on another instance show up
+	knowledgeIndexTTL = 5 * time.Minute
+	// Keyword matches must cover at least this share of the query's weight
+	minKeywordScore = 0.3
+)
+
+// KnowledgeService ingests clinic FAQ and policy documents and retrieves the
+// passages most relevant to a patient question. Chunks are embedded with the
+// AI service and searched in an in-process vector index; when embeddings are
+// unavailable it falls back to keyword search over the same chunks.
+type KnowledgeService struct {
+	documents     *mongo.Collection
+	chunks        *mongo.Collection
+	aiService     *AIService
+	minSimilarity float64
+
+	mu       sync.RWMutex
+	index    []models.KnowledgeChunk
+	loadedAt time.Time
+}
+
+func NewKnowledgeService(aiService *AIService) *KnowledgeService {
+	minSimilarity, err := strconv.ParseFloat(getEnvOrDefault("RAG_MIN_SIMILARITY", "0.55"), 64)
+	if err != nil {
+		minSimilarity = 0.55
+	}
+
+	db := database.GetMongoDB()
+	return &KnowledgeService{
+		documents:     db.Collection("knowledge_documents"),
+		chunks:        db.Collection("knowledge_chunks"),
+		aiService:     aiService,
+		minSimilarity: minSimilarity,
+	}
+}
+
+// Ingest stores a document, splits it into chunks and embeds them. A failed
+// embedding call still stores the chunks for keyword search; Reindex embeds
+// them later.
+func (s *KnowledgeService) Ingest(ctx context.Context, title, source, content string) (*models.KnowledgeDocument, error) {
+	texts := chunkText(content, knowledgeChunkSize)
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("document %q has no text", title)
+	}
+
+	now := time.Now()
+	doc := models.KnowledgeDocument{
+		ID:         primitive.NewObjectID(),
+		Title:      title,
+		Source:     source,
+		Content:    content,
+		ChunkCount: len(texts),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	embeddings, err := s.aiService.Embed(ctx, texts)
+	if err != nil {
+		log.Printf("embedding %q failed, keyword search only: %v", title, err)
+		embeddings = nil
+	}
+	doc.Embedded = embeddings != nil
+
+	chunks := make([]interface{}, len(texts))
+	for i, text := range texts {
+		chunk := models.KnowledgeChunk{
+			DocumentID: doc.ID,
+			Title:      title,
+			Source:     source,
+			Index:      i,
+			Text:       text,
+		}
+		if embeddings != nil {
+			chunk.Embedding = embeddings[i]
+		}
+		chunks[i] = chunk
+	}
+
+	if _, err := s.documents.InsertOne(ctx, doc); err != nil {
+		return nil, fmt.Errorf("failed to save document: %w", err)
+	}
+	if _, err := s.chunks.InsertMany(ctx, chunks); err != nil {
+		// Don't leave the document behind without its chunks, or with only
+		// the ones inserted before the failure
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		if _, delErr := s.documents.DeleteOne(cleanupCtx, bson.M{"_id": doc.ID}); delErr != nil {
+			log.Printf("failed to remove document %s after a failed ingest: %v", doc.ID.Hex(), delErr)
+		}
+		if _, delErr := s.chunks.DeleteMany(cleanupCtx, bson.M{"document_id": doc.ID}); delErr != nil {
+			log.Printf("failed to remove chunks of document %s after a failed ingest: %v", doc.ID.Hex(), delErr)
+		}
+		return nil, fmt.Errorf("failed to save document chunks: %w", err)
+	}
+
+	s.invalidate()
+	return &doc, nil
+}
+
+// Reindex embeds chunks stored without an embedding, e.g. documents uploaded
+// before an API key was configured. It returns the number of chunks embedded.
+func (s *KnowledgeService) Reindex(ctx context.Context) (int, error) {
+	cursor, err := s.chunks.Find(ctx, bson.M{"embedding": bson.M{"$exists": false}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load chunks: %w", err)
+	}
+	var pending []models.KnowledgeChunk
+	if err := cursor.All(ctx, &pending); err != nil {
+		return 0, fmt.Errorf("failed to decode chunks: %w", err)
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	texts := make([]string, len(pending))
+	for i, chunk := range pending {
+		texts[i] = chunk.Text
+	}
+	embeddings, err := s.aiService.Embed(ctx, texts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to embed chunks: %w", err)
+	}
+
+	documentIDs := make(map[primitive.ObjectID]bool)
+	for i, chunk := range pending {
+		if _, err := s.chunks.UpdateByID(ctx, chunk.ID, bson.M{"$set": bson.M{"embedding": embeddings[i]}}); err != nil {
+			return i, fmt.Errorf("failed to save embedding: %w", err)
+		}
+		documentIDs[chunk.DocumentID] = true
+	}
+	for id := range documentIDs {
+		if _, err := s.documents.UpdateByID(ctx, id, bson.M{"$set": bson.M{"embedded": true, "updated_at": time.Now()}}); err != nil {
+			log.Println("failed to mark document embedded", err)
+		}
+	}
+
+	s.invalidate()
+	return len(pending), nil
+}
+
+// List returns documents, newest first, without their content
+func (s *KnowledgeService) List(ctx context.Context) ([]models.KnowledgeDocument, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetProjection(bson.M{"content": 0})
+	cursor, err := s.documents.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	docs := []models.KnowledgeDocument{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode documents: %w", err)
+	}
+	return docs, nil
+}
+
+// Get returns a document with its content
+func (s *KnowledgeService) Get(ctx context.Context, id string) (*models.KnowledgeDocument, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrDocumentNotFound
+	}
+
+	var doc models.KnowledgeDocument
+	err = s.documents.FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch document: %w", err)
+	}
+	return &doc, nil
+}
+
+// Delete removes a document and its chunks
+func (s *KnowledgeService) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrDocumentNotFound
+	}
+
+	result, err := s.documents.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrDocumentNotFound
+	}
+	if _, err := s.chunks.DeleteMany(ctx, bson.M{"document_id": objectID}); err != nil {
+		return fmt.Errorf("failed to delete document chunks: %w", err)
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// Search returns up to limit passages relevant to query, best first. It
+// uses embeddings when the query can be embedded and the index has them,
+// and keyword matching otherwise.
+func (s *KnowledgeService) Search(ctx context.Context, query string, limit int) ([]models.KnowledgePassage, error) {
+	index, err := s.loadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(index) == 0 {
+		return nil, nil
+	}
+
+	if hasEmbeddings(index) {
+		vectors, err := s.aiService.Embed(ctx, []string{query})
+		if err == nil {
+			// Chunks not embedded yet can still match by keyword
+			if passages := s.vectorSearch(index, vectors[0], limit); len(passages) > 0 {
+				return passages, nil
+			}
+			return keywordSearch(index, query, limit), nil
+		}
+		if !errors.Is(err, ErrAIUnavailable) {
+			log.Println("query embedding failed, using keyword search", err)
+		}
+	}
+	return keywordSearch(index, query, limit), nil
+}
+
+func (s *KnowledgeService) vectorSearch(index []models.KnowledgeChunk, query []float32, limit int) []models.KnowledgePassage {
+	var passages []models.KnowledgePassage
+	for _, chunk := range index {
+		if len(chunk.Embedding) != len(query) {
+			continue
+		}
+		if score := cosineSimilarity(chunk.Embedding, query); score >= s.minSimilarity {
+			passages = append(passages, passageOf(chunk, score))
+		}
+	}
+	return topPassages(passages, limit)
+}
+
+// loadIndex returns every chunk, reloading from Mongo when the cached copy
+// is stale
+func (s *KnowledgeService) loadIndex(ctx context.Context) ([]models.KnowledgeChunk, error) {
+	s.mu.RLock()
+	index, loadedAt := s.index, s.loadedAt
+	s.mu.RUnlock()
+	if !loadedAt.IsZero() && time.Since(loadedAt) < knowledgeIndexTTL {
+		return index, nil
+	}
+
+	cursor, err := s.chunks.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load knowledge index: %w", err)
+	}
+	var chunks []models.KnowledgeChunk
+	if err := cursor.All(ctx, &chunks); err != nil {
+		return nil, fmt.Errorf("failed to decode knowledge index: %w", err)
+	}
+
+	s.mu.Lock()
+	s.index, s.loadedAt = chunks, time.Now()
+	s.mu.Unlock()
+	return chunks, nil
+}
+
+func (s *KnowledgeService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func hasEmbeddings(index []models.KnowledgeChunk) bool {
+	for _, chunk := range index {
+		if len(chunk.Embedding) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// keywordSearch scores chunks by the IDF-weighted share of query terms they
+// contain, so rare words like "parking" count more than common ones
+func keywordSearch(index []models.KnowledgeChunk, query string, limit int) []models.KnowledgePassage {
+	terms := keywordTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	chunkTerms := make([]map[string]bool, len(index))
+	docFreq := make(map[string]int)
+	for i, chunk := range index {
+		set := make(map[string]bool)
+		for _, t := range keywordTerms(chunk.Title + " " + chunk.Text) {
+			set[t] = true
+		}
+		chunkTerms[i] = set
+		for t := range set {
+			docFreq[t]++
+		}
+	}
+
+	idf := make(map[string]float64, len(terms))
+	var total float64
+	for _, t := range terms {
+		idf[t] = math.Log(1 + float64(len(index))/float64(1+docFreq[t]))
+		total += idf[t]
+	}
+
+	var passages []models.KnowledgePassage
+	for i, chunk := range index {
+		var matched float64
+		for _, t := range terms {
+			if chunkTerms[i][t] {
+				matched += idf[t]
+			}
+		}
+		if score := matched / total; score >= minKeywordScore {
+			passages = append(passages, passageOf(chunk, score))
+		}
+	}
+	return topPassages(passages, limit)
+}
+
+// keywordStopwords are too common to tell passages apart
+var keywordStopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "you": true, "your": true,
+	"can": true, "what": true, "when": true, "where": true, "how": true, "does": true,
+	"with": true, "have": true, "there": true, "any": true, "this": true, "that": true,
+	"from": true, "will": true, "should": true, "clinic": true, "please": true,
+}
+
+// keywordTerms returns the distinct lowercase words of text, stemmed so
+// "tests" matches "test" and "parking" matches "park"
+func keywordTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	seen := make(map[string]bool)
+	var terms []string
+	for _, w := range words {
+		if len([]rune(w)) < 3 || keywordStopwords[w] {
+			continue
+		}
+		w = stem(w)
+		if !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// stem strips common English suffixes
+func stem(w string) string {
+	for _, suffix := range []string{"ing", "ed", "s"} {
+		if strings.HasSuffix(w, suffix) && len(w)-len(suffix) >= 3 {
+			return strings.TrimSuffix(w, suffix)
+		}
+	}
+	return w
+}
+
+func passageOf(chunk models.KnowledgeChunk, score float64) models.KnowledgePassage {
+	return models.KnowledgePassage{
+		DocumentID: chunk.DocumentID,
+		Title:      chunk.Title,
+		Source:     chunk.Source,
+		Text:       chunk.Text,
+		Score:      score,
+	}
+}
+
+func topPassages(passages []models.KnowledgePassage, limit int) []models.KnowledgePassage {
+	sort.SliceStable(passages, func(i, j int) bool {
+		return passages[i].Score > passages[j].Score
+	})
+	if limit > 0 && len(passages) > limit {
+		passages = passages[:limit]
+	}
+	return passages
+}
+
+// chunkText packs paragraphs into chunks of about size characters. Longer
+// paragraphs are split between sentences, or between words as a last resort.
+func chunkText(text string, size int) []string {
+	var pieces []string
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		para = strings.Join(strings.Fields(para), " ")
+		if para == "" {
+			continue
+		}
+		pieces = append(pieces, splitLong(para, size)...)
+	}
+
+	var chunks []string
+	var current strings.Builder
+	for _, piece := range pieces {
+		if current.Len() > 0 && current.Len()+len(piece)+2 > size {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(piece)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// splitLong breaks a paragraph longer than size into pieces of at most size
+func splitLong(para string, size int) []string {
+	if len(para) <= size {
+		return []string{para}
+	}
+
+	var pieces []string
+	for len(para) > size {
+		cut := strings.LastIndex(para[:size], ". ")
+		if cut > 0 {
+			cut++ // keep the full stop
+		} else if cut = strings.LastIndex(para[:size], " "); cut <= 0 {
+			cut = size
+			for cut > 0 && !utf8.RuneStart(para[cut]) {
+				cut--
+			}
+		}
+		pieces = append(pieces, strings.TrimSpace(para[:cut]))
+		para = strings.TrimSpace(para[cut:])
+	}
+	if para != "" {
+		pieces = append(pieces, para)
+	}
+	return pieces
+}
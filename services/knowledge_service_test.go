@@ -0,0 +1,83 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"clinic-chatbot-backend/models"
+)
+
+func TestChunkText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		size int
+		want []string
+	}{
+		{"empty", " \n\n \n", 50, nil},
+		{"paragraphs packed together", "First para.\n\nSecond para.", 50, []string{"First para.\n\nSecond para."}},
+		{"paragraphs split at size", "First para.\r\n\r\nSecond para.", 20, []string{"First para.", "Second para."}},
+		{"whitespace collapsed", "One  line\nwrapped   here", 50, []string{"One line wrapped here"}},
+		{"long paragraph split between sentences", "Aaaa bbbb. Cccc dddd. Eeee.", 12, []string{"Aaaa bbbb.", "Cccc dddd.", "Eeee."}},
+		{"long sentence split between words", "aaaa bbbb cccc dddd", 10, []string{"aaaa bbbb", "cccc dddd"}},
+		{"unbroken word split on rune boundaries", "ééééé", 3, []string{"é", "é", "é", "é", "é"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chunkText(tt.text, tt.size)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("chunkText(%q, %d) = %q, want %q", tt.text, tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := map[string]string{
+		"parking": "park",
+		"tests":   "test",
+		"closed":  "clos",
+		"bus":     "bus", // too short to strip
+		"sing":    "sing",
+		"open":    "open",
+	}
+	for word, want := range tests {
+		if got := stem(word); got != want {
+			t.Errorf("stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestKeywordSearch(t *testing.T) {
+	index := []models.KnowledgeChunk{
+		{Title: "Parking", Text: "Free parking is available behind the clinic building."},
+		{Title: "Lab tests", Text: "Blood tests are done from 8am. Fasting tests need 10 hours without food."},
+		{Title: "Payments", Text: "We accept cash, cards and UPI. Insurance claims are handled at the front desk."},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string // titles, best first
+	}{
+		{"stemmed match", "where can I park?", []string{"Parking"}},
+		{"plural query", "do you do blood test", []string{"Lab tests"}},
+		{"stopwords only", "what can you do for me", nil},
+		{"no match", "visiting hours for the ward", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range keywordSearch(index, tt.query, 3) {
+				got = append(got, p.Title)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("keywordSearch(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	if got := keywordSearch(index, "tests parking insurance", 2); len(got) != 2 {
+		t.Errorf("keywordSearch limit 2 returned %d passages", len(got))
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/utils"
+)
+
+// intentDescriptions tells the model what each intent means
+var intentDescriptions = map[models.MessageIntent]string{
+	models.IntentAppointment:  "booking, viewing, cancelling or rescheduling an appointment",
+	models.IntentMedicalQuery: "questions about symptoms, illnesses, medicines or treatment",
+	models.IntentClinicInfo:   "clinic address, hours, phone number, services, insurance or payment",
+	models.IntentEmergency:    "a medical emergency that needs immediate help",
+	models.IntentGreeting:     "greetings and small talk",
+	models.IntentUnknown:      "anything else",
+}
+
+// LLMIntentClassifier classifies intents with the generative AI model
+type LLMIntentClassifier struct {
+	aiService *AIService
+}
+
+func NewLLMIntentClassifier(aiService *AIService) *LLMIntentClassifier {
+	return &LLMIntentClassifier{
+		aiService: aiService,
+	}
+}
+
+// Classify implements utils.IntentClassifier
+func (c *LLMIntentClassifier) Classify(ctx context.Context, message string) (utils.IntentResult, error) {
+	var labels strings.Builder
+	for _, intent := range []models.MessageIntent{
+		models.IntentAppointment, models.IntentMedicalQuery, models.IntentClinicInfo,
+		models.IntentEmergency, models.IntentGreeting, models.IntentUnknown,
+	} {
+		fmt.Fprintf(&labels, "- %s: %s\n", intent, intentDescriptions[intent])
+	}
+
+	prompt := fmt.Sprintf(
+		"Classify the intent of a patient's message to a clinic chatbot. "+
+			"The message may be in English, Hindi, Malayalam or Tamil.\n\n"+
+			"Intents:\n%s\n"+
+			"Message: %q\n\n"+
+			`Reply with JSON only: {"intent": "<intent>", "confidence": <number between 0 and 1>}`,
+		labels.String(), message,
+	)
+
+	raw, err := c.aiService.GenerateJSON(ctx, prompt)
+	if err != nil {
+		return utils.IntentResult{}, err
+	}
+
+	var parsed struct {
+		Intent     string  `json:"intent"`
+		Confidence float64 `json:"confidence"`
+	}
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.Trim(raw, "` \n")
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return utils.IntentResult{}, fmt.Errorf("invalid intent response %q: %w", raw, err)
+	}
+
+	intent := models.MessageIntent(parsed.Intent)
+	if _, ok := intentDescriptions[intent]; !ok {
+		return utils.IntentResult{}, fmt.Errorf("unknown intent %q from model", parsed.Intent)
+	}
+	if parsed.Confidence < 0 || parsed.Confidence > 1 {
+		parsed.Confidence = 0
+	}
+
+	return utils.IntentResult{Intent: intent, Confidence: parsed.Confidence, Source: "llm"}, nil
+}
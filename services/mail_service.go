@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"clinic-chatbot-backend/config"
+	"clinic-chatbot-backend/notify"
+	"clinic-chatbot-backend/notify/email"
+)
+
+// MailService sends templated email: appointment confirmations to the front
+// desk and staff alerts as a notify.Notifier
+type MailService struct {
+	sender     email.Sender
+	clinicName string
+	bookingTo  []string
+}
+
+func NewMailService(cfg config.EmailConfig) *MailService {
+	var bookingTo []string
+	for _, addr := range cfg.BookingNotify {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			bookingTo = append(bookingTo, addr)
+		}
+	}
+
+	return &MailService{
+		sender:     email.New(cfg),
+		clinicName: cfg.FromName,
+		bookingTo:  bookingTo,
+	}
+}
+
+// Enabled reports whether an email provider is configured
+func (s *MailService) Enabled() bool {
+	return s.sender != nil
+}
+
+// SendAppointmentConfirmation emails a new booking to the front desk. It is
+// a no-op when email or BOOKING_NOTIFY_EMAILS is not configured.
+func (s *MailService) SendAppointmentConfirmation(ctx context.Context, booking email.AppointmentConfirmation) error {
+	if !s.Enabled() || len(s.bookingTo) == 0 {
+		return nil
+	}
+	if booking.ClinicName == "" {
+		booking.ClinicName = s.clinicName
+	}
+
+	msg, err := email.Render(email.TemplateAppointmentConfirmation, booking)
+	if err != nil {
+		return err
+	}
+	msg.To = s.bookingTo
+	return s.sender.Send(ctx, msg)
+}
+
+// Channel implements notify.Notifier
+func (s *MailService) Channel() string {
+	return notify.ChannelEmail
+}
+
+// Notify implements notify.Notifier using the staff alert template
+func (s *MailService) Notify(ctx context.Context, to string, n notify.Message) error {
+	msg, err := email.Render(email.TemplateStaffAlert, email.StaffAlert{
+		ClinicName: s.clinicName,
+		Subject:    n.Subject,
+		Text:       n.Text,
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = []string{to}
+	return s.sender.Send(ctx, msg)
+}
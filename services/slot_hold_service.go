@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SlotHoldService holds the slots a patient is looking at so nobody else is
+// offered them until the patient books, moves on or the hold expires
+type SlotHoldService struct {
+	collection *mongo.Collection
+	ttl        time.Duration
+}
+
+func NewSlotHoldService(ttl time.Duration) *SlotHoldService {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	return &SlotHoldService{
+		collection: database.GetMongoDB().Collection("slot_holds"),
+		ttl:        ttl,
+	}
+}
+
+// Hold replaces the holder's holds with the given slots and returns those
+// it got; slots already held by someone else are left out
+func (s *SlotHoldService) Hold(ctx context.Context, holderID string, slots []models.SlotRef) ([]models.SlotRef, error) {
+	return s.HoldFor(ctx, holderID, slots, s.ttl)
+}
+
+// HoldFor is Hold with its own expiry, for slots held longer than a browse
+func (s *SlotHoldService) HoldFor(ctx context.Context, holderID string, slots []models.SlotRef, ttl time.Duration) ([]models.SlotRef, error) {
+	if err := s.Release(ctx, holderID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	held := make([]models.SlotRef, 0, len(slots))
+	for _, slot := range slots {
+		// Takes over our own or expired holds; the unique index rejects
+		// the insert when another holder's hold is still live
+		_, err := s.collection.UpdateOne(ctx,
+			bson.M{
+				"doctor_id": slot.DoctorID,
+				"date":      slot.Date,
+				"time":      slot.Time,
+				"$or": []bson.M{
+					{"holder_id": holderID},
+					{"expires_at": bson.M{"$lte": now}},
+				},
+			},
+			bson.M{"$set": bson.M{
+				"holder_id":  holderID,
+				"created_at": now,
+				"expires_at": now.Add(ttl),
+			}},
+			options.Update().SetUpsert(true),
+		)
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return held, fmt.Errorf("failed to hold slot: %w", err)
+		}
+		held = append(held, slot)
+	}
+	return held, nil
+}
+
+// Release drops every hold of a holder
+func (s *SlotHoldService) Release(ctx context.Context, holderID string) error {
+	if _, err := s.collection.DeleteMany(ctx, bson.M{"holder_id": holderID}); err != nil {
+		return fmt.Errorf("failed to release slot holds: %w", err)
+	}
+	return nil
+}
+
+// HeldByOthers returns the slots between two YYYY-MM-DD dates, inclusive,
+// that holders other than holderID are keeping
+func (s *SlotHoldService) HeldByOthers(ctx context.Context, holderID, from, to string) (map[models.SlotRef]bool, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{
+		"holder_id":  bson.M{"$ne": holderID},
+		"date":       bson.M{"$gte": from, "$lte": to},
+		"expires_at": bson.M{"$gt": time.Now()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list slot holds: %w", err)
+	}
+
+	var holds []models.SlotHold
+	if err := cursor.All(ctx, &holds); err != nil {
+		return nil, fmt.Errorf("failed to decode slot holds: %w", err)
+	}
+
+	held := make(map[models.SlotRef]bool, len(holds))
+	for _, h := range holds {
+		held[h.SlotRef] = true
+	}
+	return held, nil
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrTemplateNotFound is returned when a template/language pair is not synced
+var ErrTemplateNotFound = errors.New("template not found; sync templates first")
+
+// TemplateValidationError describes a template send that doesn't match its definition
+type TemplateValidationError struct {
+	Template string
+	Reason   string
+}
+
+func (e *TemplateValidationError) Error() string {
+	return fmt.Sprintf("invalid parameters for template %s: %s", e.Template, e.Reason)
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([^}\s]+)\s*\}\}`)
+
+type TemplateService struct {
+	collection      *mongo.Collection
+	whatsappService *WhatsAppService
+}
+
+func NewTemplateService(whatsappService *WhatsAppService) *TemplateService {
+	return &TemplateService{
+		collection:      database.GetMongoDB().Collection("message_templates"),
+		whatsappService: whatsappService,
+	}
+}
+
+// Sync pulls all templates from the business account into Mongo
+func (s *TemplateService) Sync(ctx context.Context) (int, error) {
+	templates, err := s.whatsappService.ListTemplates()
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch templates: %w", err)
+	}
+
+	now := time.Now()
+	for _, t := range templates {
+		t.SyncedAt = now
+		_, err := s.collection.UpdateOne(ctx,
+			bson.M{"name": t.Name, "language": t.Language},
+			bson.M{"$set": t},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to store template %s: %w", t.Name, err)
+		}
+	}
+
+	// Drop templates that were deleted on the business account
+	if _, err := s.collection.DeleteMany(ctx, bson.M{"synced_at": bson.M{"$lt": now}}); err != nil {
+		return 0, fmt.Errorf("failed to prune templates: %w", err)
+	}
+
+	return len(templates), nil
+}
+
+// List returns the synced templates, optionally filtered by status and language
+func (s *TemplateService) List(ctx context.Context, status, language string) ([]models.MessageTemplate, error) {
+	query := bson.M{}
+	if status != "" {
+		query["status"] = strings.ToUpper(status)
+	}
+	if language != "" {
+		query["language"] = language
+	}
+
+	cursor, err := s.collection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	templates := []models.MessageTemplate{}
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode templates: %w", err)
+	}
+	return templates, nil
+}
+
+// Create submits a template for approval and stores it as pending
+func (s *TemplateService) Create(ctx context.Context, req models.CreateTemplateRequest) (*models.MessageTemplate, error) {
+	id, status, err := s.whatsappService.CreateTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = "PENDING"
+	}
+
+	template := models.MessageTemplate{
+		TemplateID: id,
+		Name:       req.Name,
+		Language:   req.Language,
+		Category:   req.Category,
+		Status:     status,
+		Components: req.Components,
+		SyncedAt:   time.Now(),
+	}
+
+	_, err = s.collection.UpdateOne(ctx,
+		bson.M{"name": template.Name, "language": template.Language},
+		bson.M{"$set": template},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store template: %w", err)
+	}
+	return &template, nil
+}
+
+// Get returns a synced template by name and language
+func (s *TemplateService) Get(ctx context.Context, name, language string) (*models.MessageTemplate, error) {
+	var template models.MessageTemplate
+	err := s.collection.FindOne(ctx, bson.M{"name": name, "language": language}).Decode(&template)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch template: %w", err)
+	}
+	return &template, nil
+}
+
+// Validate checks that a template send matches the approved template definition
+func (s *TemplateService) Validate(ctx context.Context, msg models.TemplateMessage) error {
+	template, err := s.Get(ctx, msg.Name, msg.Language)
+	if err != nil {
+		return err
+	}
+
+	if template.Status != "APPROVED" {
+		return &TemplateValidationError{Template: msg.Name, Reason: fmt.Sprintf("template status is %s", template.Status)}
+	}
+
+	for _, component := range template.Components {
+		switch strings.ToUpper(component.Type) {
+		case "HEADER":
+			if err := validateHeader(template.Name, component, msg.Header); err != nil {
+				return err
+			}
+		case "BODY":
+			if want := countPlaceholders(component.Text); want != len(msg.BodyParams) {
+				return &TemplateValidationError{
+					Template: template.Name,
+					Reason:   fmt.Sprintf("body expects %d parameters, got %d", want, len(msg.BodyParams)),
+				}
+			}
+		case "BUTTONS":
+			if err := validateButtons(template.Name, component.Buttons, msg.Buttons); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Send validates a template message and sends it
+func (s *TemplateService) Send(ctx context.Context, to string, msg models.TemplateMessage) error {
+	if msg.Language == "" {
+		msg.Language = s.whatsappService.templateLanguage
+	}
+	if err := s.Validate(ctx, msg); err != nil {
+		return err
+	}
+	return s.whatsappService.SendTemplate(to, msg)
+}
+
+func validateHeader(name string, component models.TemplateComponent, header *models.TemplateHeaderParam) error {
+	format := strings.ToUpper(component.Format)
+	if format == "" || format == "TEXT" {
+		want := countPlaceholders(component.Text)
+		if want == 0 {
+			if header != nil {
+				return &TemplateValidationError{Template: name, Reason: "header takes no parameters"}
+			}
+			return nil
+		}
+		if header == nil || header.Text == "" {
+			return &TemplateValidationError{Template: name, Reason: "header text parameter is required"}
+		}
+		return nil
+	}
+
+	if format == "LOCATION" {
+		return nil
+	}
+
+	if header == nil || header.Link == "" {
+		return &TemplateValidationError{Template: name, Reason: fmt.Sprintf("header requires a %s link", strings.ToLower(format))}
+	}
+	if !strings.EqualFold(header.Type, format) {
+		return &TemplateValidationError{Template: name, Reason: fmt.Sprintf("header must be of type %s", strings.ToLower(format))}
+	}
+	return nil
+}
+
+func validateButtons(name string, buttons []models.TemplateButton, params []models.TemplateButtonParam) error {
+	given := make(map[int]models.TemplateButtonParam, len(params))
+	for _, p := range params {
+		if p.Index < 0 || p.Index >= len(buttons) {
+			return &TemplateValidationError{Template: name, Reason: fmt.Sprintf("button index %d out of range", p.Index)}
+		}
+		given[p.Index] = p
+	}
+
+	for i, button := range buttons {
+		if strings.ToUpper(button.Type) == "URL" && countPlaceholders(button.URL) > 0 {
+			if _, ok := given[i]; !ok {
+				return &TemplateValidationError{Template: name, Reason: fmt.Sprintf("button %d requires a URL parameter", i)}
+			}
+		}
+	}
+	return nil
+}
+
+// countPlaceholders returns the number of distinct {{n}} variables in text
+func countPlaceholders(text string) int {
+	seen := map[string]bool{}
+	for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		seen[match[1]] = true
+	}
+	return len(seen)
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"clinic-chatbot-backend/database"
+	"clinic-chatbot-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrWaitlistEntryNotFound is returned when an entry does not exist or is no
+// longer in the state an operation needs, e.g. an offer that has expired
+var ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+
+// WaitlistService keeps patients waiting for a slot and the slots offered to
+// them. Entries are served first come, first served.
+type WaitlistService struct {
+	collection *mongo.Collection
+}
+
+func NewWaitlistService() *WaitlistService {
+	return &WaitlistService{
+		collection: database.GetMongoDB().Collection("waitlist"),
+	}
+}
+
+// Join adds a patient to the waitlist. Joining again for the same doctor
+// updates the dates but keeps the patient's place.
+func (s *WaitlistService) Join(ctx context.Context, entry models.WaitlistEntry) (*models.WaitlistEntry, error) {
+	now := time.Now()
+	var saved models.WaitlistEntry
+	err := s.collection.FindOneAndUpdate(ctx,
+		bson.M{
+			"phone":         entry.Phone,
+			"department_id": entry.DepartmentID,
+			"doctor_id":     entry.DoctorID,
+			"status":        bson.M{"$in": []models.WaitlistStatus{models.WaitlistWaiting, models.WaitlistOffered}},
+		},
+		bson.M{
+			"$set": bson.M{
+				"locale":        entry.Locale,
+				"doctor_name":   entry.DoctorName,
+				"from_date":     entry.FromDate,
+				"to_date":       entry.ToDate,
+				"time_window":   entry.TimeWindow,
+				"patient_id":    entry.PatientID,
+				"patient_code":  entry.PatientCode,
+				"patient_name":  entry.PatientName,
+				"address":       entry.Address,
+				"phone_number":  entry.PhoneNumber,
+				"date_of_birth": entry.DateOfBirth,
+				"updated_at":    now,
+			},
+			"$setOnInsert": bson.M{
+				"status":     models.WaitlistWaiting,
+				"created_at": now,
+			},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join waitlist: %w", err)
+	}
+	return &saved, nil
+}
+
+// Waiting returns the entries waiting for an offer, oldest first
+func (s *WaitlistService) Waiting(ctx context.Context) ([]models.WaitlistEntry, error) {
+	return s.find(ctx, bson.M{"status": models.WaitlistWaiting},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+}
+
+// List returns entries, oldest first, optionally filtered by status
+func (s *WaitlistService) List(ctx context.Context, status models.WaitlistStatus, limit int) ([]models.WaitlistEntry, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	return s.find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(int64(limit)))
+}
+
+func (s *WaitlistService) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.WaitlistEntry, error) {
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waitlist: %w", err)
+	}
+
+	entries := []models.WaitlistEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode waitlist: %w", err)
+	}
+	return entries, nil
+}
+
+// Offer records a slot offered to a waiting entry
+func (s *WaitlistService) Offer(ctx context.Context, id primitive.ObjectID, offer models.WaitlistOffer) error {
+	res, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.WaitlistWaiting},
+		bson.M{"$set": bson.M{
+			"status":     models.WaitlistOffered,
+			"offer":      offer,
+			"updated_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record waitlist offer: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrWaitlistEntryNotFound
+	}
+	return nil
+}
+
+// ActiveOffer returns the live offer sent to a phone number
+func (s *WaitlistService) ActiveOffer(ctx context.Context, phone string) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := s.collection.FindOne(ctx,
+		bson.M{
+			"phone":            phone,
+			"status":           models.WaitlistOffered,
+			"offer.expires_at": bson.M{"$gt": time.Now()},
+		},
+		options.FindOne().SetSort(bson.D{{Key: "offer.offered_at", Value: -1}}),
+	).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrWaitlistEntryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up waitlist offer: %w", err)
+	}
+	return &entry, nil
+}
+
+// Claim takes a live offer for booking, so a second tap cannot book it twice.
+// Requeue puts the entry back when the booking fails.
+func (s *WaitlistService) Claim(ctx context.Context, id, phone string) (*models.WaitlistEntry, error) {
+	return s.transition(ctx, id,
+		bson.M{
+			"phone":            phone,
+			"status":           models.WaitlistOffered,
+			"offer.expires_at": bson.M{"$gt": time.Now()},
+		},
+		bson.M{"$set": bson.M{"status": models.WaitlistBooked}},
+	)
+}
+
+// Pass turns down the current offer; the patient keeps their place and the
+// slot is not offered to them again
+func (s *WaitlistService) Pass(ctx context.Context, id, phone string) (*models.WaitlistEntry, error) {
+	entry, err := s.transition(ctx, id,
+		bson.M{"phone": phone, "status": models.WaitlistOffered},
+		bson.M{"$set": bson.M{"status": models.WaitlistWaiting}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return entry, s.clearOffer(ctx, entry, true)
+}
+
+// Requeue puts a claimed entry back on the waitlist after a failed booking
+func (s *WaitlistService) Requeue(ctx context.Context, id primitive.ObjectID) error {
+	entry, err := s.transition(ctx, id.Hex(),
+		bson.M{"status": models.WaitlistBooked},
+		bson.M{"$set": bson.M{"status": models.WaitlistWaiting}},
+	)
+	if err != nil {
+		return err
+	}
+	return s.clearOffer(ctx, entry, false)
+}
+
+// Withdraw takes back an offer that could not be sent; the patient keeps
+// their place and the slot can be offered to them again
+func (s *WaitlistService) Withdraw(ctx context.Context, id primitive.ObjectID) error {
+	entry, err := s.transition(ctx, id.Hex(),
+		bson.M{"status": models.WaitlistOffered},
+		bson.M{"$set": bson.M{"status": models.WaitlistWaiting}},
+	)
+	if err != nil {
+		return err
+	}
+	return s.clearOffer(ctx, entry, false)
+}
+
+// Leave takes a patient off the waitlist
+func (s *WaitlistService) Leave(ctx context.Context, id, phone string) (*models.WaitlistEntry, error) {
+	return s.transition(ctx, id,
+		bson.M{
+			"phone":  phone,
+			"status": bson.M{"$in": []models.WaitlistStatus{models.WaitlistWaiting, models.WaitlistOffered}},
+		},
+		bson.M{"$set": bson.M{"status": models.WaitlistLeft}},
+	)
+}
+
+// LeaveAll takes every open entry of a phone off the waitlist, e.g. when the
+// patient opts out, and returns the entries that had a slot offered
+func (s *WaitlistService) LeaveAll(ctx context.Context, phone string) ([]models.WaitlistEntry, error) {
+	var offered []models.WaitlistEntry
+	for {
+		var entry models.WaitlistEntry
+		err := s.collection.FindOneAndUpdate(ctx,
+			bson.M{
+				"phone":  phone,
+				"status": bson.M{"$in": []models.WaitlistStatus{models.WaitlistWaiting, models.WaitlistOffered}},
+			},
+			bson.M{"$set": bson.M{"status": models.WaitlistLeft, "updated_at": time.Now()}},
+		).Decode(&entry)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return offered, nil
+		}
+		if err != nil {
+			return offered, fmt.Errorf("failed to leave waitlist: %w", err)
+		}
+		if entry.Status == models.WaitlistOffered {
+			offered = append(offered, entry)
+		}
+	}
+}
+
+// ExpireOffers puts entries whose offer ran out back on the waitlist and
+// returns them, and closes entries whose date range has passed
+func (s *WaitlistService) ExpireOffers(ctx context.Context, today string) ([]models.WaitlistEntry, error) {
+	now := time.Now()
+	if _, err := s.collection.UpdateMany(ctx,
+		bson.M{
+			"status":  bson.M{"$in": []models.WaitlistStatus{models.WaitlistWaiting, models.WaitlistOffered}},
+			"to_date": bson.M{"$lt": today},
+		},
+		bson.M{"$set": bson.M{"status": models.WaitlistExpired, "updated_at": now}},
+	); err != nil {
+		return nil, fmt.Errorf("failed to expire waitlist entries: %w", err)
+	}
+
+	// Claimed one at a time so two instances never notify the same patient
+	var expired []models.WaitlistEntry
+	for {
+		var entry models.WaitlistEntry
+		err := s.collection.FindOneAndUpdate(ctx,
+			bson.M{"status": models.WaitlistOffered, "offer.expires_at": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"status": models.WaitlistWaiting, "updated_at": now}},
+		).Decode(&entry)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return expired, nil
+		}
+		if err != nil {
+			return expired, fmt.Errorf("failed to expire waitlist offer: %w", err)
+		}
+		if err := s.clearOffer(ctx, &entry, true); err != nil {
+			return expired, err
+		}
+		expired = append(expired, entry)
+	}
+}
+
+func (s *WaitlistService) transition(ctx context.Context, id string, filter, update bson.M) (*models.WaitlistEntry, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrWaitlistEntryNotFound
+	}
+	filter["_id"] = oid
+	update["$set"].(bson.M)["updated_at"] = time.Now()
+
+	var entry models.WaitlistEntry
+	err = s.collection.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrWaitlistEntryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update waitlist entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// clearOffer drops an entry's offer, remembering the slot when the patient
+// passed on it
+func (s *WaitlistService) clearOffer(ctx context.Context, entry *models.WaitlistEntry, passed bool) error {
+	if entry.Offer == nil {
+		return nil
+	}
+	update := bson.M{"$unset": bson.M{"offer": ""}}
+	if passed {
+		update["$addToSet"] = bson.M{"passed": entry.Offer.SlotRef}
+	}
+	if _, err := s.collection.UpdateOne(ctx, bson.M{"_id": entry.ID}, update); err != nil {
+		return fmt.Errorf("failed to clear waitlist offer: %w", err)
+	}
+	if passed {
+		entry.Passed = append(entry.Passed, entry.Offer.SlotRef)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"clinic-chatbot-backend/notify"
+)
+
+// WhatsAppNotifier delivers notifications to staff on WhatsApp. With a
+// template configured every message goes out as that approved template, so
+// staff who haven't messaged the bot in the last 24 hours still receive it.
+type WhatsAppNotifier struct {
+	whatsappService *WhatsAppService
+	template        string // approved template with {{1}} subject and {{2}} text, empty for text messages
+}
+
+func NewWhatsAppNotifier(whatsappService *WhatsAppService, template string) *WhatsAppNotifier {
+	return &WhatsAppNotifier{
+		whatsappService: whatsappService,
+		template:        template,
+	}
+}
+
+// Channel implements notify.Notifier
+func (n *WhatsAppNotifier) Channel() string {
+	return notify.ChannelWhatsApp
+}
+
+// Notify implements notify.Notifier. The subject is sent as a bold first line,
+// or as the first template parameter.
+func (n *WhatsAppNotifier) Notify(ctx context.Context, to string, msg notify.Message) error {
+	// Staff numbers come from config as typed, possibly without a country code
+	to = n.whatsappService.NormalizePhone(to)
+	if n.template != "" {
+		return n.whatsappService.SendTemplateMessage(to, n.template, []string{
+			templateParam(msg.Subject),
+			templateParam(msg.Text),
+		})
+	}
+
+	text := msg.Text
+	if msg.Subject != "" {
+		text = "*" + msg.Subject + "*\n\n" + text
+	}
+	return n.whatsappService.SendTextMessage(to, text)
+}
+
+// templateParam flattens text for a template body parameter, which WhatsApp
+// rejects when it contains newlines, tabs or runs of spaces
+func templateParam(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	param := strings.Join(lines, " · ")
+	if param == "" {
+		param = "-"
+	}
+	return param
+}
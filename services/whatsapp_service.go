@@ -3,17 +3,39 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"clinic-chatbot-backend/i18n"
 	"clinic-chatbot-backend/models"
+	"clinic-chatbot-backend/phone"
+)
+
+// ErrOutsideServiceWindow is returned when a free-form message is sent to a
+// contact whose last inbound message is older than 24 hours
+var ErrOutsideServiceWindow = errors.New("24-hour customer service window is closed for this contact; only template messages can be sent")
+
+// ErrNotWhatsAppUser is returned when the recipient has no WhatsApp account
+var ErrNotWhatsAppUser = errors.New("recipient is not a WhatsApp user")
+
+// ErrReachabilityUnknown is returned by ValidatePhoneNumber when neither the
+// cache nor a contacts API can tell whether a number has WhatsApp
+var ErrReachabilityUnknown = errors.New("WhatsApp reachability unknown")
+
+// Meta error codes
+const (
+    reEngagementErrorCode  = 131047 // free-form message outside the service window
+    undeliverableErrorCode = 131026 // recipient cannot receive WhatsApp messages
 )
 
 type WhatsAppService struct {
@@ -25,6 +47,18 @@ type WhatsAppService struct {
     verifyToken     string
     httpClient      *http.Client
     
+    // 24-hour customer service window
+    contactService  *ContactService
+    windowTemplate  string // template used when the window is closed, empty to block
+    templateLanguage string
+    
+    // Phone numbers without a country code are read in this region
+    region          string
+    
+    // Reachability checks: optional contacts API and how long results are cached
+    contactsAPIURL  string
+    reachabilityTTL time.Duration
+    
     // Status tracking
     statusMu        sync.RWMutex
     lastMessageTime time.Time
@@ -32,7 +66,12 @@ type WhatsAppService struct {
     dailyCount      map[string]int
 }
 
-func NewWhatsAppService() *WhatsAppService {
+func NewWhatsAppService(contactService *ContactService, region string) *WhatsAppService {
+    reachabilityTTL, err := time.ParseDuration(getEnvOrDefault("WHATSAPP_REACHABILITY_TTL", "720h"))
+    if err != nil {
+        reachabilityTTL = 720 * time.Hour
+    }
+    
     return &WhatsAppService{
         apiURL:        "https://graph.facebook.com",
         apiVersion: "v18.0",
@@ -43,10 +82,74 @@ func NewWhatsAppService() *WhatsAppService {
         httpClient: &http.Client{
             Timeout: 30 * time.Second,
         },
+        contactService: contactService,
+        windowTemplate: os.Getenv("WHATSAPP_WINDOW_FALLBACK_TEMPLATE"),
+        templateLanguage: getEnvOrDefault("WHATSAPP_TEMPLATE_LANGUAGE", "en"),
+        region:          region,
+        contactsAPIURL:  os.Getenv("WHATSAPP_CONTACTS_API_URL"),
+        reachabilityTTL: reachabilityTTL,
         dailyCount: make(map[string]int),
     }
 }
 
+// IsWithinServiceWindow reports whether free-form messages can be sent to the
+// number, along with the time of the contact's last inbound message
+func (ws *WhatsAppService) IsWithinServiceWindow(to string) (bool, time.Time, error) {
+    if ws.contactService == nil {
+        return true, time.Time{}, nil
+    }
+    
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    
+    return ws.contactService.IsWithinServiceWindow(ctx, ws.CleanPhoneNumber(to))
+}
+
+// WindowFallbackTemplate returns the template used outside the service window
+func (ws *WhatsAppService) WindowFallbackTemplate() string {
+    return ws.windowTemplate
+}
+
+// sendFreeForm sends a free-form payload if the service window is open.
+// Otherwise the text is sent through the fallback template, or the send is
+// blocked with ErrOutsideServiceWindow when no template is configured.
+func (ws *WhatsAppService) sendFreeForm(to string, fallbackText string, payload interface{}) error {
+    _, err := ws.sendFreeFormWithID(to, fallbackText, payload)
+    return err
+}
+
+// sendFreeFormWithID is sendFreeForm returning the WhatsApp message ID
+func (ws *WhatsAppService) sendFreeFormWithID(to string, fallbackText string, payload interface{}) (string, error) {
+    open, lastInbound, err := ws.IsWithinServiceWindow(to)
+    if err != nil {
+        // Don't block replies on a lookup failure; Meta rejects the send if needed
+        log.Printf("Service window lookup failed for %s: %v", to, err)
+        return ws.post(to, payload)
+    }
+    if open {
+        return ws.post(to, payload)
+    }
+    
+    if ws.windowTemplate == "" {
+        log.Printf("Blocked free-form message to %s, last inbound at %v", to, lastInbound)
+        return "", ErrOutsideServiceWindow
+    }
+    
+    // The fallback template is expected to be approved in every supported
+    // language; pick the one the message was written in
+    language := ws.templateLanguage
+    if locale, ok := i18n.Detect(fallbackText); ok {
+        language = locale.TemplateLanguage()
+    }
+    
+    log.Printf("Service window closed for %s, sending template %s (%s) instead", to, ws.windowTemplate, language)
+    return ws.sendTemplate(to, models.TemplateMessage{
+        Name:       ws.windowTemplate,
+        Language:   language,
+        BodyParams: []string{fallbackText},
+    })
+}
+
 // GetVerifyToken returns the webhook verification token
 func (ws *WhatsAppService) GetVerifyToken() string {
     log.Println("Verify token: ", ws.verifyToken)
@@ -55,6 +158,13 @@ func (ws *WhatsAppService) GetVerifyToken() string {
 
 // SendTextMessage sends a simple text message
 func (ws *WhatsAppService) SendTextMessage(to string, message string) error {
+    _, err := ws.SendTextMessageWithID(to, message)
+    return err
+}
+
+// SendTextMessageWithID sends a text message and returns its WhatsApp
+// message ID, used to match later delivery status updates
+func (ws *WhatsAppService) SendTextMessageWithID(to string, message string) (string, error) {
     // Clean and validate phone number
     to = ws.CleanPhoneNumber(to)
     
@@ -68,7 +178,7 @@ func (ws *WhatsAppService) SendTextMessage(to string, message string) error {
         },
     }
     
-    return ws.sendMessage(payload)
+    return ws.sendFreeFormWithID(to, message, payload)
 }
 
 // SendInteractiveMessage sends an interactive message
@@ -85,19 +195,76 @@ func (ws *WhatsAppService) SendInteractiveMessage(to string, interactive *models
 
     log.Println("payload from whatsapp", payload)
     
-    return ws.sendMessage(payload)
+    fallbackText := ""
+    if interactive != nil && interactive.Body != nil {
+        fallbackText = interactive.Body.Text
+    }
+    
+    return ws.sendFreeForm(to, fallbackText, payload)
 }
 
-// SendTemplateMessage sends a template message
+// SendTemplateMessage sends a template message with body parameters in the
+// default template language
 func (ws *WhatsAppService) SendTemplateMessage(to string, templateName string, params []string) error {
+    return ws.SendTemplate(to, models.TemplateMessage{
+        Name:       templateName,
+        Language:   ws.templateLanguage,
+        BodyParams: params,
+    })
+}
+
+// SendTemplate sends a template message with header, body and button parameters
+func (ws *WhatsAppService) SendTemplate(to string, tmpl models.TemplateMessage) error {
+    _, err := ws.sendTemplate(to, tmpl)
+    return err
+}
+
+func (ws *WhatsAppService) sendTemplate(to string, tmpl models.TemplateMessage) (string, error) {
     to = ws.CleanPhoneNumber(to)
     
+    language := tmpl.Language
+    if language == "" {
+        language = ws.templateLanguage
+    }
+    
     // Build template components
-    components := []map[string]interface{}{
-        {
-            "type": "body",
-            "parameters": ws.buildTemplateParams(params),
-        },
+    components := []map[string]interface{}{}
+    
+    if tmpl.Header != nil {
+        components = append(components, map[string]interface{}{
+            "type":       "header",
+            "parameters": []map[string]interface{}{ws.buildHeaderParam(tmpl.Header)},
+        })
+    }
+    
+    if len(tmpl.BodyParams) > 0 {
+        components = append(components, map[string]interface{}{
+            "type":       "body",
+            "parameters": ws.buildTemplateParams(tmpl.BodyParams),
+        })
+    }
+    
+    for _, button := range tmpl.Buttons {
+        paramType := "text"
+        if button.SubType == "quick_reply" {
+            paramType = "payload"
+        }
+        components = append(components, map[string]interface{}{
+            "type":     "button",
+            "sub_type": button.SubType,
+            "index":    strconv.Itoa(button.Index),
+            "parameters": []map[string]interface{}{
+                {"type": paramType, paramType: button.Param},
+            },
+        })
+    }
+    
+    template := map[string]interface{}{
+        "name":     tmpl.Name,
+        "language": map[string]string{"code": language},
+    }
+    if len(components) > 0 {
+        template["components"] = components
     }
     
     payload := map[string]interface{}{
@@ -105,14 +272,29 @@ func (ws *WhatsAppService) SendTemplateMessage(to string, templateName string, p
         "recipient_type":    "individual",
         "to":                to,
         "type":              "template",
-        "template": map[string]interface{}{
-            "name":       templateName,
-            "language":   map[string]string{"code": "en"},
-            "components": components,
-        },
+        "template":          template,
     }
     
-    return ws.sendRequest(payload)
+    return ws.post(to, payload)
+}
+
+// buildHeaderParam converts a header parameter to WhatsApp format
+func (ws *WhatsAppService) buildHeaderParam(header *models.TemplateHeaderParam) map[string]interface{} {
+    if header.Type == "" || header.Type == "text" {
+        return map[string]interface{}{
+            "type": "text",
+            "text": header.Text,
+        }
+    }
+    
+    media := map[string]interface{}{"link": header.Link}
+    if header.Type == "document" && header.Filename != "" {
+        media["filename"] = header.Filename
+    }
+    return map[string]interface{}{
+        "type":      header.Type,
+        header.Type: media,
+    }
 }
 
 // buildTemplateParams converts string params to WhatsApp format
@@ -127,13 +309,140 @@ func (ws *WhatsAppService) buildTemplateParams(params []string) []map[string]int
     return templateParams
 }
 
+// ListTemplates fetches all message templates of the business account
+func (ws *WhatsAppService) ListTemplates() ([]models.MessageTemplate, error) {
+    if ws.businessID == "" {
+        return nil, fmt.Errorf("WHATSAPP_BUSINESS_ID is not configured")
+    }
+    
+    url := fmt.Sprintf("%s/%s/%s/message_templates?limit=100", ws.apiURL, ws.apiVersion, ws.businessID)
+    templates := []models.MessageTemplate{}
+    
+    for url != "" {
+        var page struct {
+            Data []struct {
+                ID         string                     `json:"id"`
+                Name       string                     `json:"name"`
+                Language   string                     `json:"language"`
+                Category   string                     `json:"category"`
+                Status     string                     `json:"status"`
+                Components []models.TemplateComponent `json:"components"`
+            } `json:"data"`
+            Paging struct {
+                Next string `json:"next"`
+            } `json:"paging"`
+        }
+        
+        if err := ws.graphRequest(http.MethodGet, url, nil, &page); err != nil {
+            return nil, err
+        }
+        
+        for _, t := range page.Data {
+            templates = append(templates, models.MessageTemplate{
+                TemplateID: t.ID,
+                Name:       t.Name,
+                Language:   t.Language,
+                Category:   t.Category,
+                Status:     t.Status,
+                Components: t.Components,
+            })
+        }
+        url = page.Paging.Next
+    }
+    
+    return templates, nil
+}
+
+// CreateTemplate submits a new message template for approval
+func (ws *WhatsAppService) CreateTemplate(req models.CreateTemplateRequest) (string, string, error) {
+    if ws.businessID == "" {
+        return "", "", fmt.Errorf("WHATSAPP_BUSINESS_ID is not configured")
+    }
+    
+    url := fmt.Sprintf("%s/%s/%s/message_templates", ws.apiURL, ws.apiVersion, ws.businessID)
+    
+    var resp struct {
+        ID     string `json:"id"`
+        Status string `json:"status"`
+    }
+    if err := ws.graphRequest(http.MethodPost, url, req, &resp); err != nil {
+        return "", "", err
+    }
+    
+    return resp.ID, resp.Status, nil
+}
+
+// graphRequest calls the Graph API and decodes the JSON response into target
+func (ws *WhatsAppService) graphRequest(method, url string, body interface{}, target interface{}) error {
+    var reqBody io.Reader
+    if body != nil {
+        jsonPayload, err := json.Marshal(body)
+        if err != nil {
+            return fmt.Errorf("failed to marshal payload: %w", err)
+        }
+        reqBody = bytes.NewBuffer(jsonPayload)
+    }
+    
+    req, err := http.NewRequest(method, url, reqBody)
+    if err != nil {
+        return fmt.Errorf("failed to create request: %w", err)
+    }
+    
+    req.Header.Set("Authorization", "Bearer "+ws.accessToken)
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    
+    resp, err := ws.httpClient.Do(req)
+    if err != nil {
+        return fmt.Errorf("failed to send request: %w", err)
+    }
+    defer resp.Body.Close()
+    
+    respBody, err := io.ReadAll(resp.Body)
+    if err != nil {
+        return fmt.Errorf("failed to read response: %w", err)
+    }
+    
+    if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+        log.Printf("Graph API error (%d): %s", resp.StatusCode, string(respBody))
+        return fmt.Errorf("WhatsApp API error: %s", string(respBody))
+    }
+    
+    if err := json.Unmarshal(respBody, target); err != nil {
+        return fmt.Errorf("failed to parse response: %w", err)
+    }
+    return nil
+}
+
 // sendMessage sends a message via WhatsApp API
 func (ws *WhatsAppService) sendMessage(message models.WhatsAppSendMessage) error {
     return ws.sendRequest(message)
 }
 
-// Update sendRequest with better error logging
+// post sends a message to one recipient, remembering numbers WhatsApp
+// rejects as not having an account
+func (ws *WhatsAppService) post(to string, payload interface{}) (string, error) {
+    id, err := ws.postMessage(payload)
+    if errors.Is(err, ErrNotWhatsAppUser) && ws.contactService != nil {
+        ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        if recordErr := ws.contactService.RecordWhatsAppStatus(ctx, to, models.WhatsAppInvalid); recordErr != nil {
+            log.Println(recordErr)
+        }
+    }
+    return id, err
+}
+
+// sendRequest posts a payload to the messages endpoint
 func (ws *WhatsAppService) sendRequest(payload interface{}) error {
+    _, err := ws.postMessage(payload)
+    return err
+}
+
+// postMessage posts a payload to the messages endpoint and returns the ID of
+// the sent message, if any
+func (ws *WhatsAppService) postMessage(payload interface{}) (string, error) {
     url := fmt.Sprintf("%s/%s/%s/messages", ws.apiURL, ws.apiVersion, ws.phoneNumberID)
     
     // Log the URL being called
@@ -142,7 +451,7 @@ func (ws *WhatsAppService) sendRequest(payload interface{}) error {
     jsonPayload, err := json.Marshal(payload)
     if err != nil {
         log.Printf("Failed to marshal payload: %v", err)
-        return fmt.Errorf("failed to marshal payload: %w", err)
+        return "", fmt.Errorf("failed to marshal payload: %w", err)
     }
     
     // Log the payload being sent
@@ -151,7 +460,7 @@ func (ws *WhatsAppService) sendRequest(payload interface{}) error {
     req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
     if err != nil {
         log.Printf("Failed to create request: %v", err)
-        return fmt.Errorf("failed to create request: %w", err)
+        return "", fmt.Errorf("failed to create request: %w", err)
     }
     
     // Log headers
@@ -162,14 +471,14 @@ func (ws *WhatsAppService) sendRequest(payload interface{}) error {
     resp, err := ws.httpClient.Do(req)
     if err != nil {
         log.Printf("Failed to send request: %v", err)
-        return fmt.Errorf("failed to send request: %w", err)
+        return "", fmt.Errorf("failed to send request: %w", err)
     }
     defer resp.Body.Close()
     
     body, err := io.ReadAll(resp.Body)
     if err != nil {
         log.Printf("Failed to read response: %v", err)
-        return fmt.Errorf("failed to read response: %w", err)
+        return "", fmt.Errorf("failed to read response: %w", err)
     }
     
     // Always log the response
@@ -183,20 +492,35 @@ func (ws *WhatsAppService) sendRequest(payload interface{}) error {
             
             // Check for specific error codes
             if errData, ok := errorResp["error"].(map[string]interface{}); ok {
-                if code, ok := errData["code"].(float64); ok {
-                    log.Printf("Error code: %v", code)
-                }
                 if message, ok := errData["message"].(string); ok {
                     log.Printf("Error message: %s", message)
                 }
+                if code, ok := errData["code"].(float64); ok {
+                    log.Printf("Error code: %v", code)
+                    switch int(code) {
+                    case reEngagementErrorCode:
+                        return "", fmt.Errorf("%w: %v", ErrOutsideServiceWindow, errData["message"])
+                    case undeliverableErrorCode:
+                        return "", fmt.Errorf("%w: %v", ErrNotWhatsAppUser, errData["message"])
+                    }
+                }
             }
-            return fmt.Errorf("WhatsApp API error: %v", errorResp)
+            return "", fmt.Errorf("WhatsApp API error: %v", errorResp)
         }
-        return fmt.Errorf("WhatsApp API error: %s", string(body))
+        return "", fmt.Errorf("WhatsApp API error: %s", string(body))
     }
     
     ws.updateMessageStatus()
-    return nil
+    
+    var result struct {
+        Messages []struct {
+            ID string `json:"id"`
+        } `json:"messages"`
+    }
+    if err := json.Unmarshal(body, &result); err == nil && len(result.Messages) > 0 {
+        return result.Messages[0].ID, nil
+    }
+    return "", nil
 }
 
 // sendRequest sends HTTP request to WhatsApp API
@@ -260,7 +584,7 @@ func (ws *WhatsAppService) SendMediaMessage(to string, mediaType string, mediaUR
         },
     }
     
-    return ws.sendRequest(payload)
+    return ws.sendFreeForm(to, caption, payload)
 }
 
 // SendLocationMessage sends a location
@@ -280,7 +604,7 @@ func (ws *WhatsAppService) SendLocationMessage(to string, lat, lng float64, name
         },
     }
     
-    return ws.sendRequest(payload)
+    return ws.sendFreeForm(to, fmt.Sprintf("%s, %s", name, address), payload)
 }
 
 // SendContactMessage sends a contact
@@ -295,25 +619,48 @@ func (ws *WhatsAppService) SendContactMessage(to string, contacts []map[string]i
         "contacts":          contacts,
     }
     
-    return ws.sendRequest(payload)
+    return ws.sendFreeForm(to, "", payload)
 }
 
-// CleanPhoneNumber cleans and validates phone number
-func (ws *WhatsAppService) CleanPhoneNumber(phone string) string {
-    // Remove all non-numeric characters
-    cleaned := strings.Map(func(r rune) rune {
+// CleanPhoneNumber normalizes a recipient to WhatsApp's form: E.164 digits
+// without the +. Bare digits are taken to be a WhatsApp ID, which already
+// carries its country code; anything else is a typed number and goes through
+// NormalizePhone.
+func (ws *WhatsAppService) CleanPhoneNumber(raw string) string {
+    if digits := phoneDigits(raw); digits == raw {
+        return digits
+    }
+    return ws.NormalizePhone(raw)
+}
+
+// NormalizePhone normalizes a number someone typed, reading it in the
+// default region when it has no country code. Unparseable input is returned
+// as its digits.
+func (ws *WhatsAppService) NormalizePhone(raw string) string {
+    if number, err := ws.ParsePhone(raw); err == nil {
+        return number.Digits()
+    }
+    return phoneDigits(raw)
+}
+
+// phoneDigits strips everything but digits
+func phoneDigits(raw string) string {
+    return strings.Map(func(r rune) rune {
         if r >= '0' && r <= '9' {
             return r
         }
         return -1
-    }, phone)
-    
-    // Add country code if missing (assuming US for example)
-    if len(cleaned) == 10 {
-        cleaned = "1" + cleaned
-    }
-    
-    return cleaned
+    }, raw)
+}
+
+// ParsePhone parses a number using the default region
+func (ws *WhatsAppService) ParsePhone(raw string) (phone.Number, error) {
+    return phone.Parse(raw, ws.region)
+}
+
+// Region returns the default region for phone numbers
+func (ws *WhatsAppService) Region() string {
+    return ws.region
 }
 
 // updateMessageStatus updates internal message tracking
@@ -384,9 +731,97 @@ func (ws *WhatsAppService) GetBusinessProfile() (map[string]interface{}, error)
     return profile, nil
 }
 
-// ValidatePhoneNumber checks if a phone number has WhatsApp
+// ValidatePhoneNumber checks if a phone number has WhatsApp. A number that
+// has messaged us does; otherwise a cached contacts API result or rejected
+// send is used, then the contacts API itself when configured. Returns
+// ErrReachabilityUnknown when none of these can tell.
 func (ws *WhatsAppService) ValidatePhoneNumber(phoneNumber string) (bool, error) {
-    // This would use the WhatsApp Business API to check if number has WhatsApp
-    // For now, return true as placeholder
-    return true, nil
+    number, err := ws.ParsePhone(phoneNumber)
+    if err != nil {
+        return false, err
+    }
+    to := number.Digits()
+    
+    ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+    defer cancel()
+    
+    if ws.contactService != nil {
+        contact, err := ws.contactService.Get(ctx, to)
+        if err != nil {
+            return false, err
+        }
+        if contact != nil && !contact.LastInboundAt.IsZero() {
+            return true, nil
+        }
+        if contact != nil && contact.WhatsAppCheckedAt != nil && time.Since(*contact.WhatsAppCheckedAt) < ws.reachabilityTTL {
+            return contact.WhatsAppStatus == models.WhatsAppValid, nil
+        }
+    }
+    
+    if ws.contactsAPIURL == "" {
+        return false, ErrReachabilityUnknown
+    }
+    
+    status, err := ws.checkContact(ctx, number.E164())
+    if err != nil {
+        return false, err
+    }
+    if ws.contactService != nil {
+        if err := ws.contactService.RecordWhatsAppStatus(ctx, to, status); err != nil {
+            log.Println(err)
+        }
+    }
+    return status == models.WhatsAppValid, nil
+}
+
+// checkContact asks the contacts endpoint (WhatsApp On-Premises API or a
+// provider exposing it) whether a number has WhatsApp
+func (ws *WhatsAppService) checkContact(ctx context.Context, e164 string) (string, error) {
+    payload, err := json.Marshal(map[string]interface{}{
+        "blocking": "wait",
+        "contacts": []string{e164},
+    })
+    if err != nil {
+        return "", err
+    }
+    
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, ws.contactsAPIURL, bytes.NewReader(payload))
+    if err != nil {
+        return "", err
+    }
+    req.Header.Set("Authorization", "Bearer "+ws.accessToken)
+    req.Header.Set("Content-Type", "application/json")
+    
+    resp, err := ws.httpClient.Do(req)
+    if err != nil {
+        return "", fmt.Errorf("contacts API request failed: %w", err)
+    }
+    defer resp.Body.Close()
+    
+    body, _ := io.ReadAll(resp.Body)
+    if resp.StatusCode != http.StatusOK {
+        return "", fmt.Errorf("contacts API error (status %d): %s", resp.StatusCode, string(body))
+    }
+    
+    var result struct {
+        Contacts []struct {
+            Input  string `json:"input"`
+            Status string `json:"status"`
+        } `json:"contacts"`
+    }
+    if err := json.Unmarshal(body, &result); err != nil {
+        return "", fmt.Errorf("failed to parse contacts API response: %w", err)
+    }
+    if len(result.Contacts) == 0 {
+        return "", fmt.Errorf("contacts API returned no result for %s", e164)
+    }
+    return result.Contacts[0].Status, nil
+}
+
+// getEnvOrDefault reads an environment variable with a fallback
+func getEnvOrDefault(key, defaultValue string) string {
+    if value := os.Getenv(key); value != "" {
+        return value
+    }
+    return defaultValue
 }
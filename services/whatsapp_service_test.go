@@ -0,0 +1,25 @@
+package services
+
+import "testing"
+
+func TestPhoneNormalization(t *testing.T) {
+	ws := &WhatsAppService{region: "IN"}
+
+	tests := []struct {
+		raw, clean, normalized string
+	}{
+		// A Singapore WhatsApp ID is ten digits, like an Indian mobile
+		{"6591234567", "6591234567", "916591234567"},
+		{"919876543210", "919876543210", "919876543210"},
+		{"98765 43210", "919876543210", "919876543210"},
+		{"+65 9123 4567", "6591234567", "6591234567"},
+	}
+	for _, tt := range tests {
+		if got := ws.CleanPhoneNumber(tt.raw); got != tt.clean {
+			t.Errorf("CleanPhoneNumber(%q) = %q, want %q", tt.raw, got, tt.clean)
+		}
+		if got := ws.NormalizePhone(tt.raw); got != tt.normalized {
+			t.Errorf("NormalizePhone(%q) = %q, want %q", tt.raw, got, tt.normalized)
+		}
+	}
+}
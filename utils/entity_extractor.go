@@ -0,0 +1,271 @@
+package utils
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"clinic-chatbot-backend/models"
+)
+
+// EntityExtractor pulls booking details out of a free-text message
+type EntityExtractor interface {
+	Extract(ctx context.Context, message string) (models.ExtractedEntities, error)
+}
+
+// departmentKeywords maps words patients use to a canonical department name
+var departmentKeywords = map[string]string{
+	"cardiology": "Cardiology", "cardiologist": "Cardiology", "cardio": "Cardiology", "heart": "Cardiology",
+	"dermatology": "Dermatology", "dermatologist": "Dermatology", "skin": "Dermatology",
+	"pediatrics": "Pediatrics", "paediatrics": "Pediatrics", "pediatrician": "Pediatrics", "child": "Pediatrics", "children": "Pediatrics",
+	"orthopedics": "Orthopedics", "orthopaedics": "Orthopedics", "ortho": "Orthopedics", "bone": "Orthopedics",
+	"general medicine": "General Medicine", "general physician": "General Medicine", "physician": "General Medicine",
+	"gynecology": "Gynecology", "gynaecology": "Gynecology", "gynecologist": "Gynecology", "gynaecologist": "Gynecology",
+	"ent": "ENT", "ear": "ENT", "nose": "ENT", "throat": "ENT",
+	"neurology": "Neurology", "neurologist": "Neurology",
+	"ophthalmology": "Ophthalmology", "eye": "Ophthalmology",
+	"dental": "Dental", "dentist": "Dental", "teeth": "Dental", "tooth": "Dental",
+}
+
+var symptomKeywords = []string{
+	"chest pain", "back pain", "stomach pain", "sore throat", "shortness of breath",
+	"fever", "cough", "cold", "headache", "vomiting", "nausea", "diarrhea", "diarrhoea",
+	"rash", "itching", "dizziness", "fatigue", "allergy", "pain", "swelling", "bleeding",
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+// monthNames matches English month names and their abbreviations; the first
+// three letters key months
+const monthNames = `(jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)`
+
+var (
+	isoDatePattern   = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	dayFirstPattern  = regexp.MustCompile(`\b(\d{1,2})[/.-](\d{1,2})[/.-](\d{4})\b`)
+	dayMonthPattern  = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?` + monthNames + `\b`)
+	monthDayPattern  = regexp.MustCompile(`(?i)\b` + monthNames + `\.?\s+(\d{1,2})(?:st|nd|rd|th)?\b`)
+	clockTimePattern = regexp.MustCompile(`(?i)\b(\d{1,2})(?::(\d{2}))?\s*(am|pm)\b`)
+	doctorPattern    = regexp.MustCompile(`(?i)\b(dr\.?|doctor)\s+([a-z][a-z.]*(?:\s+[a-z][a-z.]*)?)`)
+	namePattern      = regexp.MustCompile(`(?i:my name is|name is|i am|i'm|this is)\s+([A-Z][a-zA-Z]+(?:\s+[A-Z][a-zA-Z]+)?)`)
+)
+
+// nameStopWords end a doctor name ("Dr Rao tomorrow")
+var nameStopWords = map[string]bool{
+	"today": true, "tomorrow": true, "on": true, "at": true, "in": true, "for": true,
+	"morning": true, "afternoon": true, "evening": true, "next": true, "this": true,
+	"please": true, "and": true, "from": true, "to": true, "with": true,
+	"appointment": true, "consultation": true, "visit": true,
+}
+
+// RuleEntityExtractor extracts entities with keyword lists and patterns
+type RuleEntityExtractor struct {
+	location *time.Location
+	now      func() time.Time
+}
+
+// NewRuleEntityExtractor resolves relative dates ("tomorrow") in the given
+// clinic time zone
+func NewRuleEntityExtractor(location *time.Location) *RuleEntityExtractor {
+	return &RuleEntityExtractor{
+		location: location,
+		now:      time.Now,
+	}
+}
+
+// Extract implements EntityExtractor. It never returns an error.
+func (e *RuleEntityExtractor) Extract(ctx context.Context, message string) (models.ExtractedEntities, error) {
+	words := tokenize(message)
+
+	return models.ExtractedEntities{
+		Department:  extractDepartment(words),
+		DoctorName:  extractDoctor(message),
+		Date:        e.extractDate(message, words),
+		TimeWindow:  extractTimeWindow(message, words),
+		PatientName: extractPatientName(message),
+		Symptoms:    extractSymptoms(words),
+	}, nil
+}
+
+func extractDepartment(words []string) string {
+	for i := range words {
+		// Two-word names first ("general medicine")
+		if i+1 < len(words) {
+			if dept, ok := departmentKeywords[words[i]+" "+words[i+1]]; ok {
+				return dept
+			}
+		}
+		if dept, ok := departmentKeywords[words[i]]; ok {
+			return dept
+		}
+	}
+	return ""
+}
+
+func extractDoctor(message string) string {
+	m := doctorPattern.FindStringSubmatch(message)
+	if m == nil {
+		return ""
+	}
+	// "a doctor appointment" is not a name; after the full word only a
+	// capitalised name counts
+	if strings.EqualFold(m[1], "doctor") && !unicode.IsUpper([]rune(m[2])[0]) {
+		return ""
+	}
+
+	var name []string
+	for _, w := range strings.Fields(m[2]) {
+		if nameStopWords[strings.ToLower(w)] {
+			break
+		}
+		name = append(name, strings.Trim(w, "."))
+	}
+	return strings.Join(name, " ")
+}
+
+func extractPatientName(message string) string {
+	m := namePattern.FindStringSubmatch(message)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+func (e *RuleEntityExtractor) extractDate(message string, words []string) string {
+	today := e.now().In(e.location)
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, e.location)
+	format := func(t time.Time) string { return t.Format("2006-01-02") }
+
+	if m := isoDatePattern.FindStringSubmatch(message); m != nil {
+		if t, err := time.ParseInLocation("2006-01-02", m[0], e.location); err == nil {
+			return format(t)
+		}
+	}
+	if m := dayFirstPattern.FindStringSubmatch(message); m != nil {
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		year, _ := strconv.Atoi(m[3])
+		if t, ok := validDate(year, time.Month(month), day, e.location); ok {
+			return format(t)
+		}
+	}
+	if t, ok := e.dayAndMonth(message, today); ok {
+		return format(t)
+	}
+
+	for i, w := range words {
+		switch w {
+		case "today", "tonight":
+			return format(today)
+		case "tomorrow", "tmrw", "tmr":
+			if i >= 2 && words[i-2] == "day" && words[i-1] == "after" {
+				return format(today.AddDate(0, 0, 2))
+			}
+			return format(today.AddDate(0, 0, 1))
+		}
+		if day, ok := weekdays[w]; ok {
+			// The next such day, never today ("monday" said on a Monday)
+			diff := (int(day) - int(today.Weekday()) + 7) % 7
+			if diff == 0 {
+				diff = 7
+			}
+			return format(today.AddDate(0, 0, diff))
+		}
+	}
+	return ""
+}
+
+// dayAndMonth parses "5th March" or "March 5", rolling over to next year
+// when the date has already passed
+func (e *RuleEntityExtractor) dayAndMonth(message string, today time.Time) (time.Time, bool) {
+	var dayStr, monthStr string
+	if m := dayMonthPattern.FindStringSubmatch(message); m != nil {
+		dayStr, monthStr = m[1], m[2]
+	} else if m := monthDayPattern.FindStringSubmatch(message); m != nil {
+		dayStr, monthStr = m[2], m[1]
+	} else {
+		return time.Time{}, false
+	}
+
+	day, _ := strconv.Atoi(dayStr)
+	month := months[strings.ToLower(monthStr[:3])]
+	t, ok := validDate(today.Year(), month, day, e.location)
+	if ok && t.Before(today) {
+		t, ok = validDate(today.Year()+1, month, day, e.location)
+	}
+	return t, ok
+}
+
+// validDate rejects dates that time.Date would normalise, e.g. 31 February
+func validDate(year int, month time.Month, day int, loc *time.Location) (time.Time, bool) {
+	t := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	if t.Month() != month || t.Day() != day {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func extractTimeWindow(message string, words []string) string {
+	for _, w := range words {
+		switch w {
+		case "morning":
+			return models.TimeWindowMorning
+		case "afternoon", "noon":
+			return models.TimeWindowAfternoon
+		case "evening", "tonight", "night":
+			return models.TimeWindowEvening
+		}
+	}
+
+	// "at 5pm" → evening
+	if m := clockTimePattern.FindStringSubmatch(message); m != nil {
+		hour, _ := strconv.Atoi(m[1])
+		if strings.EqualFold(m[3], "pm") && hour < 12 {
+			hour += 12
+		}
+		return TimeWindowForHour(hour)
+	}
+	return ""
+}
+
+// TimeWindowForHour buckets an hour of the day (0-23) into a time window
+func TimeWindowForHour(hour int) string {
+	switch {
+	case hour < 12:
+		return models.TimeWindowMorning
+	case hour < 16:
+		return models.TimeWindowAfternoon
+	default:
+		return models.TimeWindowEvening
+	}
+}
+
+func extractSymptoms(words []string) []string {
+	var symptoms []string
+	covered := make(map[int]bool)
+	for _, symptom := range symptomKeywords {
+		for _, pos := range findPhrase(words, symptom) {
+			// "chest pain" already covers the "pain" in it
+			if covered[pos] || isNegated(words, pos) {
+				continue
+			}
+			for i := range strings.Fields(symptom) {
+				covered[pos+i] = true
+			}
+			symptoms = append(symptoms, symptom)
+			break
+		}
+	}
+	return symptoms
+}
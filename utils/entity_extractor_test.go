@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRuleEntityExtractorDates(t *testing.T) {
+	e := NewRuleEntityExtractor(time.UTC)
+	e.now = func() time.Time { return time.Date(2026, time.April, 10, 9, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"appointment on 5th May", "2026-05-05"},
+		{"can I come on the 3 of june", "2026-06-03"},
+		{"March 5 please", "2027-03-05"},
+		{"sept. 12th", "2026-09-12"},
+		{"2026-04-20", "2026-04-20"},
+		{"12/04/2026", "2026-04-12"},
+		{"tomorrow morning", "2026-04-11"},
+		// Words that only start like a month are not dates
+		{"maybe 3 of us", ""},
+		{"junk 5", ""},
+		{"I need 2 marbles", ""},
+		{"31 feb", ""},
+	}
+	for _, tt := range tests {
+		got, err := e.Extract(context.Background(), tt.message)
+		if err != nil {
+			t.Fatalf("Extract(%q): %v", tt.message, err)
+		}
+		if got.Date != tt.want {
+			t.Errorf("Extract(%q).Date = %q, want %q", tt.message, got.Date, tt.want)
+		}
+	}
+}
@@ -1,16 +1,50 @@
 package utils
 
 import (
+    "context"
+    "log"
     "strings"
+    "unicode"
     "clinic-chatbot-backend/models"
 )
 
-type IntentClassifier struct {
+// IntentResult is a classified intent with a confidence between 0 and 1
+type IntentResult struct {
+    Intent     models.MessageIntent
+    Confidence float64
+    Source     string // classifier that produced the result, e.g. "keyword", "llm"
+}
+
+// IntentClassifier maps a user message to an intent
+type IntentClassifier interface {
+    Classify(ctx context.Context, message string) (IntentResult, error)
+}
+
+// intentOrder breaks score ties deterministically
+var intentOrder = []models.MessageIntent{
+    models.IntentAppointment,
+    models.IntentMedicalQuery,
+    models.IntentClinicInfo,
+    models.IntentGreeting,
+}
+
+// negations cancel a keyword when they appear just before it ("no pain")
+var negations = map[string]bool{
+    "no": true, "not": true, "never": true, "without": true,
+    "don't": true, "dont": true, "didn't": true, "doesn't": true,
+    "haven't": true, "hasn't": true, "isn't": true,
+}
+
+// negationWindow is how many words before a keyword are checked for a negation
+const negationWindow = 2
+
+// KeywordClassifier scores intents by whole-word keyword matches
+type KeywordClassifier struct {
     patterns map[models.MessageIntent][]string
 }
 
-func NewIntentClassifier() *IntentClassifier {
-    return &IntentClassifier{
+func NewKeywordClassifier() *KeywordClassifier {
+    return &KeywordClassifier{
         patterns: map[models.MessageIntent][]string{
             models.IntentAppointment: {
                 "appointment", "book", "schedule", "doctor", "consultation",
@@ -24,11 +58,15 @@ func NewIntentClassifier() *IntentClassifier {
             models.IntentClinicInfo: {
                 "clinic", "location", "address", "hours", "timing",
                 "contact", "phone", "services", "specialization", "doctor list",
-                "facilities", "insurance", "payment",
+                "facilities", "insurance", "payment", "open", "opening",
+                "closed", "holiday",
             },
             models.IntentEmergency: {
                 "emergency", "urgent", "immediate", "critical", "severe",
                 "accident", "bleeding", "unconscious", "chest pain",
+                "can't breathe", "cant breathe", "cannot breathe",
+                "not breathing", "difficulty breathing", "trouble breathing",
+                "choking",
             },
             models.IntentGreeting: {
                 "hello", "hi", "hey", "good morning", "good evening",
@@ -38,42 +76,159 @@ func NewIntentClassifier() *IntentClassifier {
     }
 }
 
-func (ic *IntentClassifier) ClassifyIntent(message string) models.MessageIntent {
-    message = strings.ToLower(message)
-    
-    // Check for emergency keywords first
-    if ic.containsAnyKeyword(message, ic.patterns[models.IntentEmergency]) {
-        return models.IntentEmergency
+// Classify implements IntentClassifier. It never returns an error.
+func (kc *KeywordClassifier) Classify(ctx context.Context, message string) (IntentResult, error) {
+    return kc.classify(message), nil
+}
+
+// ClassifyIntent returns only the best intent for the message
+func (kc *KeywordClassifier) ClassifyIntent(message string) models.MessageIntent {
+    return kc.classify(message).Intent
+}
+
+func (kc *KeywordClassifier) classify(message string) IntentResult {
+    words := tokenize(message)
+
+    // Check for emergency keywords first. Negations are ignored here on
+    // purpose: missing an emergency is worse than a false alarm.
+    for _, keyword := range kc.patterns[models.IntentEmergency] {
+        if len(findPhrase(words, keyword)) > 0 {
+            return IntentResult{Intent: models.IntentEmergency, Confidence: 1, Source: "keyword"}
+        }
     }
-    
+
     // Score each intent
     scores := make(map[models.MessageIntent]int)
-    for intent, keywords := range ic.patterns {
-        for _, keyword := range keywords {
-            if strings.Contains(message, keyword) {
-                scores[intent]++
+    total := 0
+    for _, intent := range intentOrder {
+        for _, keyword := range kc.patterns[intent] {
+            for _, pos := range findPhrase(words, keyword) {
+                if !isNegated(words, pos) {
+                    scores[intent]++
+                    total++
+                }
             }
         }
     }
-    
+
     // Find intent with highest score
-    var maxIntent models.MessageIntent = models.IntentUnknown
+    result := IntentResult{Intent: models.IntentUnknown, Source: "keyword"}
     maxScore := 0
-    for intent, score := range scores {
-        if score > maxScore {
-            maxScore = score
-            maxIntent = intent
+    for _, intent := range intentOrder {
+        if scores[intent] > maxScore {
+            maxScore = scores[intent]
+            result.Intent = intent
+        }
+    }
+    if maxScore > 0 {
+        result.Confidence = float64(maxScore) / float64(total)
+    }
+
+    return result
+}
+
+// tokenize lowercases the message and splits it into words. Apostrophes stay
+// inside words so "don't" is kept whole.
+func tokenize(message string) []string {
+    return strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+        return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) && r != '\'' && r != '’'
+    })
+}
+
+// findPhrase returns the word positions where the (possibly multi-word)
+// keyword starts. Words match in the singular or plural.
+func findPhrase(words []string, keyword string) []int {
+    phrase := strings.Fields(keyword)
+    var positions []int
+    for i := 0; i+len(phrase) <= len(words); i++ {
+        match := true
+        for j, w := range phrase {
+            if !sameWord(normalizeApostrophe(words[i+j]), w) {
+                match = false
+                break
+            }
+        }
+        if match {
+            positions = append(positions, i)
         }
     }
-    
-    return maxIntent
+    return positions
 }
 
-func (ic *IntentClassifier) containsAnyKeyword(message string, keywords []string) bool {
-    for _, keyword := range keywords {
-        if strings.Contains(message, keyword) {
+// isNegated reports whether a negation appears shortly before the word at pos
+func isNegated(words []string, pos int) bool {
+    start := pos - negationWindow
+    if start < 0 {
+        start = 0
+    }
+    for _, w := range words[start:pos] {
+        if negations[normalizeApostrophe(w)] {
             return true
         }
     }
     return false
 }
+
+// sameWord matches a message word to a keyword word, ignoring a plural -s,
+// -es or -ies on either side ("symptoms", "services", "facility")
+func sameWord(a, b string) bool {
+    if a == b {
+        return true
+    }
+    if len(a) < len(b) {
+        a, b = b, a
+    }
+    // Short words are left alone: "hi" is not the singular of "his"
+    if len(b) < 3 {
+        return false
+    }
+    if a == b+"s" || a == b+"es" {
+        return true
+    }
+    return strings.HasSuffix(b, "y") && a == b[:len(b)-1]+"ies"
+}
+
+func normalizeApostrophe(word string) string {
+    return strings.ReplaceAll(word, "’", "'")
+}
+
+// FallbackClassifier uses a primary classifier (e.g. an LLM) and falls back
+// to a secondary one when the primary fails or is not confident enough. An
+// emergency found by the fallback always wins, however confident the primary.
+type FallbackClassifier struct {
+    primary       IntentClassifier
+    fallback      IntentClassifier
+    minConfidence float64
+}
+
+func NewFallbackClassifier(primary, fallback IntentClassifier, minConfidence float64) *FallbackClassifier {
+    return &FallbackClassifier{
+        primary:       primary,
+        fallback:      fallback,
+        minConfidence: minConfidence,
+    }
+}
+
+func (fc *FallbackClassifier) Classify(ctx context.Context, message string) (IntentResult, error) {
+    fallback, fallbackErr := fc.fallback.Classify(ctx, message)
+    if fallbackErr == nil && fallback.Intent == models.IntentEmergency {
+        return fallback, nil
+    }
+
+    result, err := fc.primary.Classify(ctx, message)
+    if err == nil && result.Confidence >= fc.minConfidence {
+        return result, nil
+    }
+
+    if err != nil {
+        log.Println("intent classifier unavailable, using fallback:", err)
+    }
+    if err == nil && (fallbackErr != nil || fallback.Intent == models.IntentUnknown) {
+        // Keep the low-confidence primary result rather than nothing
+        return result, nil
+    }
+    if fallbackErr != nil {
+        return IntentResult{}, fallbackErr
+    }
+    return fallback, nil
+}
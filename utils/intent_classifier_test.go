@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"clinic-chatbot-backend/models"
+)
+
+// fixedClassifier answers every message with the same result
+type fixedClassifier IntentResult
+
+func (f fixedClassifier) Classify(ctx context.Context, message string) (IntentResult, error) {
+	return IntentResult(f), nil
+}
+
+func TestFallbackClassifierKeepsKeywordEmergencies(t *testing.T) {
+	primary := fixedClassifier{Intent: models.IntentMedicalQuery, Confidence: 0.95, Source: "llm"}
+	fc := NewFallbackClassifier(primary, NewKeywordClassifier(), 0.6)
+
+	got, err := fc.Classify(context.Background(), "my father has chest pain since morning")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Intent != models.IntentEmergency || got.Source != "keyword" {
+		t.Errorf("Classify() = %+v, want the keyword emergency", got)
+	}
+
+	got, _ = fc.Classify(context.Background(), "what medicine helps a cold")
+	if got.Source != "llm" {
+		t.Errorf("Classify() = %+v, want the confident primary result", got)
+	}
+}
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"clinic-chatbot-backend/models"
+)
+
+// LabelledUtterance is one line of an intent dataset (JSON lines):
+//
+//	{"text": "I want to book an appointment", "intent": "appointment"}
+type LabelledUtterance struct {
+	Text   string               `json:"text"`
+	Intent models.MessageIntent `json:"intent"`
+}
+
+// IntentMetrics are the precision and recall of a single intent
+type IntentMetrics struct {
+	Precision float64
+	Recall    float64
+	Support   int // labelled examples of this intent
+}
+
+// IntentEvaluation is the result of running a classifier over a dataset
+type IntentEvaluation struct {
+	Total     int
+	Correct   int
+	Accuracy  float64
+	PerIntent map[models.MessageIntent]IntentMetrics
+	// Confusion[expected][predicted] counts examples
+	Confusion map[models.MessageIntent]map[models.MessageIntent]int
+	Misses    []Misclassification
+}
+
+// Misclassification is a dataset example the classifier got wrong
+type Misclassification struct {
+	Text      string
+	Expected  models.MessageIntent
+	Predicted models.MessageIntent
+}
+
+// LoadIntentDataset reads a JSON lines dataset. Blank lines and lines
+// starting with # are skipped.
+func LoadIntentDataset(path string) ([]LabelledUtterance, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var dataset []LabelledUtterance
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		var u LabelledUtterance
+		if err := json.Unmarshal([]byte(text), &u); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if u.Text == "" || u.Intent == "" {
+			return nil, fmt.Errorf("%s:%d: text and intent are required", path, line)
+		}
+		dataset = append(dataset, u)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return dataset, nil
+}
+
+// EvaluateIntentClassifier classifies every example and computes accuracy,
+// per-intent precision/recall and the confusion matrix
+func EvaluateIntentClassifier(ctx context.Context, classifier IntentClassifier, dataset []LabelledUtterance) (*IntentEvaluation, error) {
+	eval := &IntentEvaluation{
+		PerIntent: make(map[models.MessageIntent]IntentMetrics),
+		Confusion: make(map[models.MessageIntent]map[models.MessageIntent]int),
+	}
+
+	predictedCount := make(map[models.MessageIntent]int)
+	correctCount := make(map[models.MessageIntent]int)
+	supportCount := make(map[models.MessageIntent]int)
+
+	for _, u := range dataset {
+		result, err := classifier.Classify(ctx, u.Text)
+		if err != nil {
+			return nil, fmt.Errorf("classify %q: %w", u.Text, err)
+		}
+
+		if eval.Confusion[u.Intent] == nil {
+			eval.Confusion[u.Intent] = make(map[models.MessageIntent]int)
+		}
+		eval.Confusion[u.Intent][result.Intent]++
+		supportCount[u.Intent]++
+		predictedCount[result.Intent]++
+		eval.Total++
+
+		if result.Intent == u.Intent {
+			eval.Correct++
+			correctCount[u.Intent]++
+		} else {
+			eval.Misses = append(eval.Misses, Misclassification{Text: u.Text, Expected: u.Intent, Predicted: result.Intent})
+		}
+	}
+
+	if eval.Total > 0 {
+		eval.Accuracy = float64(eval.Correct) / float64(eval.Total)
+	}
+	for intent, support := range supportCount {
+		m := IntentMetrics{Support: support, Recall: float64(correctCount[intent]) / float64(support)}
+		if predictedCount[intent] > 0 {
+			m.Precision = float64(correctCount[intent]) / float64(predictedCount[intent])
+		}
+		eval.PerIntent[intent] = m
+	}
+	return eval, nil
+}
+
+// Report formats the evaluation as a plain-text table
+func (e *IntentEvaluation) Report() string {
+	intents := e.intents()
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "accuracy: %.3f (%d/%d)\n\n", e.Accuracy, e.Correct, e.Total)
+
+	fmt.Fprintf(&b, "%-15s %9s %9s %8s\n", "intent", "precision", "recall", "support")
+	for _, intent := range intents {
+		m := e.PerIntent[intent]
+		fmt.Fprintf(&b, "%-15s %9.3f %9.3f %8d\n", intent, m.Precision, m.Recall, m.Support)
+	}
+
+	b.WriteString("\nconfusion (rows: expected, columns: predicted)\n")
+	fmt.Fprintf(&b, "%-15s", "")
+	for _, predicted := range intents {
+		fmt.Fprintf(&b, " %6.6s", predicted)
+	}
+	b.WriteString("\n")
+	for _, expected := range intents {
+		fmt.Fprintf(&b, "%-15s", expected)
+		for _, predicted := range intents {
+			fmt.Fprintf(&b, " %6d", e.Confusion[expected][predicted])
+		}
+		b.WriteString("\n")
+	}
+
+	if len(e.Misses) > 0 {
+		b.WriteString("\nmisclassified\n")
+		for _, m := range e.Misses {
+			fmt.Fprintf(&b, "  %-15s -> %-15s %q\n", m.Expected, m.Predicted, m.Text)
+		}
+	}
+	return b.String()
+}
+
+// intents lists every intent seen as a label or a prediction, sorted
+func (e *IntentEvaluation) intents() []models.MessageIntent {
+	seen := make(map[models.MessageIntent]bool)
+	for expected, row := range e.Confusion {
+		seen[expected] = true
+		for predicted := range row {
+			seen[predicted] = true
+		}
+	}
+
+	intents := make([]models.MessageIntent, 0, len(seen))
+	for intent := range seen {
+		intents = append(intents, intent)
+	}
+	sort.Slice(intents, func(i, j int) bool { return intents[i] < intents[j] })
+	return intents
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"clinic-chatbot-backend/models"
+)
+
+// Regression thresholds for the keyword classifier on testdata/intents.jsonl.
+// Raise them when the keyword lists improve; never lower them to make a
+// change pass.
+const minKeywordAccuracy = 0.96
+
+var minKeywordRecall = map[models.MessageIntent]float64{
+	models.IntentAppointment:  1.0,
+	models.IntentMedicalQuery: 0.9,
+	models.IntentClinicInfo:   0.9,
+	models.IntentEmergency:    1.0,
+	models.IntentGreeting:     1.0,
+	models.IntentUnknown:      1.0,
+}
+
+func TestKeywordClassifierEvaluation(t *testing.T) {
+	dataset, err := LoadIntentDataset("testdata/intents.jsonl")
+	if err != nil {
+		t.Fatalf("load dataset: %v", err)
+	}
+
+	eval, err := EvaluateIntentClassifier(context.Background(), NewKeywordClassifier(), dataset)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	t.Logf("\n%s", eval.Report())
+
+	if eval.Accuracy < minKeywordAccuracy {
+		t.Errorf("accuracy %.3f is below the %.3f threshold", eval.Accuracy, minKeywordAccuracy)
+	}
+	for intent, min := range minKeywordRecall {
+		if recall := eval.PerIntent[intent].Recall; recall < min {
+			t.Errorf("%s recall %.3f is below the %.3f threshold", intent, recall, min)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MinNameSimilarity is the NameSimilarity above which two names are taken
+// to be the same person
+const MinNameSimilarity = 0.85
+
+// nameTitles are left out when comparing names
+var nameTitles = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "dr": true,
+	"master": true, "baby": true, "shri": true, "sri": true, "smt": true,
+}
+
+// NameSimilarity scores how alike two person names are, from 0 to 1. Case,
+// punctuation, titles and word order are ignored, an initial matches a name
+// starting with it and small typos only lower the score a little, so
+// "Rao, Anita", "Mrs. A Rao" and "anitha rao" all come close to "Anita Rao".
+func NameSimilarity(a, b string) float64 {
+	wordsA, wordsB := nameWords(a), nameWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	if len(wordsA) < len(wordsB) {
+		wordsA, wordsB = wordsB, wordsA
+	}
+
+	// Every word of the longer name is matched to its closest in the other
+	total := 0.0
+	for _, wa := range wordsA {
+		best := 0.0
+		for _, wb := range wordsB {
+			if s := wordSimilarity(wa, wb); s > best {
+				best = s
+			}
+		}
+		total += best
+	}
+	return total / float64(len(wordsA))
+}
+
+// SameName reports whether two names are likely the same person's
+func SameName(a, b string) bool {
+	return NameSimilarity(a, b) >= MinNameSimilarity
+}
+
+func nameWords(name string) [][]rune {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r)
+	})
+	words := make([][]rune, 0, len(fields))
+	for _, field := range fields {
+		if !nameTitles[field] {
+			words = append(words, []rune(field))
+		}
+	}
+	sort.Slice(words, func(i, j int) bool { return string(words[i]) < string(words[j]) })
+	return words
+}
+
+func wordSimilarity(a, b []rune) float64 {
+	if len(a) == 1 || len(b) == 1 {
+		if a[0] == b[0] {
+			return 1
+		}
+		return 0
+	}
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	return 1 - float64(editDistance(a, b))/float64(longest)
+}
+
+// editDistance is the Levenshtein distance between two words
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,30 @@
+package utils
+
+import "testing"
+
+func TestSameName(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Anita Rao", "anita rao", true},
+		{"Rao, Anita", "Anita Rao", true},
+		{"Mrs. Anita Rao", "Anita Rao", true},
+		{"A. Rao", "Anita Rao", true},
+		{"Anitha Rao", "Anita Rao", true},
+		{"Anita  Rao ", "ANITA RAO", true},
+		{"അനിത റാവു", "അനിത റാവു", true},
+		{"Anil Rao", "Anita Rao", false},
+		{"Anita", "Anita Rao", false},
+		{"Priya Nair", "Priya Menon", false},
+		{"", "Anita Rao", false},
+		{"Dr.", "Dr.", false},
+	}
+
+	for _, tt := range tests {
+		if got := SameName(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameName(%q, %q) = %v (similarity %.2f), want %v",
+				tt.a, tt.b, got, NameSimilarity(tt.a, tt.b), tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultSlotDuration is the slot length when neither HMS nor clinic config
+// give one
+const DefaultSlotDuration = 15 * time.Minute
+
+// SlotSession is a stretch of a doctor's day that is split into appointment
+// slots, e.g. a morning and an evening session. Zero durations fall back to
+// the generator's.
+type SlotSession struct {
+	Start    string // "15:04" or "15:04:05"
+	End      string
+	Duration time.Duration
+	Buffer   time.Duration
+}
+
+// ClockRange is a daily period such as a lunch break, in minutes since midnight
+type ClockRange struct {
+	start, end int
+}
+
+// ParseClockRange parses a "13:00-14:00" period
+func ParseClockRange(s string) (ClockRange, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return ClockRange{}, fmt.Errorf("invalid time range %q, want HH:MM-HH:MM", s)
+	}
+	start, err := clockMinutes(from)
+	if err != nil {
+		return ClockRange{}, err
+	}
+	end, err := clockMinutes(to)
+	if err != nil {
+		return ClockRange{}, err
+	}
+	if end <= start {
+		return ClockRange{}, fmt.Errorf("invalid time range %q, end is not after start", s)
+	}
+	return ClockRange{start: start, end: end}, nil
+}
+
+// Slot is an appointment slot in a doctor's day
+type Slot struct {
+	Start   time.Time
+	Token   int // 1-based position in the doctor's whole day, booked and past slots included
+	Session int // index of the session it belongs to
+}
+
+// SlotGenerator splits a doctor's sessions on a date into slots
+type SlotGenerator struct {
+	Duration time.Duration    // slot length; DefaultSlotDuration when zero
+	Buffer   time.Duration    // gap left after each slot
+	Breaks   []ClockRange     // no slot overlaps these
+	Location *time.Location   // clinic time zone; UTC when nil
+	Now      func() time.Time // time.Now when nil; slots that have started are left out
+}
+
+// Generate returns the slots on a YYYY-MM-DD date that have not started, in
+// order. A slot must fit inside its session. Times are built from the wall
+// clock, so a day with a DST change still gets slots at the usual times.
+// Malformed sessions are logged and skipped; it is an error only when every
+// session is malformed.
+func (g SlotGenerator) Generate(date string, sessions []SlotSession) ([]Slot, error) {
+	loc := g.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid appointment date: %w", err)
+	}
+	now := time.Now()
+	if g.Now != nil {
+		now = g.Now()
+	}
+
+	seen := make(map[int64]bool)
+	slots := []Slot{}
+	var sessionErr error
+	valid := 0
+	for i, session := range sessions {
+		start, end, length, err := g.sessionBounds(session)
+		if err != nil {
+			log.Printf("skipping session %d on %s: %v", i, date, err)
+			sessionErr = err
+			continue
+		}
+		valid++
+		step := length + int(firstDuration(session.Buffer, g.Buffer, 0)/time.Minute)
+
+		for m := start; m+length <= end; m += step {
+			// Slots resume when a break ends
+			if resume, ok := g.breakEnd(m, m+length); ok {
+				m = resume - step
+				continue
+			}
+			t := time.Date(day.Year(), day.Month(), day.Day(), 0, m, 0, 0, loc)
+			if seen[t.Unix()] {
+				continue
+			}
+			seen[t.Unix()] = true
+			slots = append(slots, Slot{Start: t, Session: i})
+		}
+	}
+
+	if valid == 0 && sessionErr != nil {
+		return nil, sessionErr
+	}
+
+	// Tokens count the whole day, so they stay the same as the day goes on
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+	upcoming := []Slot{}
+	for i := range slots {
+		slots[i].Token = i + 1
+		if !slots[i].Start.Before(now) {
+			upcoming = append(upcoming, slots[i])
+		}
+	}
+	return upcoming, nil
+}
+
+// sessionBounds returns a session's start and end in minutes since midnight
+// and its slot length in minutes
+func (g SlotGenerator) sessionBounds(session SlotSession) (start, end, length int, err error) {
+	if start, err = clockMinutes(session.Start); err != nil {
+		return
+	}
+	if end, err = clockMinutes(session.End); err != nil {
+		return
+	}
+	length = int(firstDuration(session.Duration, g.Duration, DefaultSlotDuration) / time.Minute)
+	if length <= 0 {
+		err = fmt.Errorf("slot duration must be at least a minute")
+	}
+	return
+}
+
+// breakEnd returns the end of a break overlapping the minutes [start, end)
+func (g SlotGenerator) breakEnd(start, end int) (int, bool) {
+	for _, b := range g.Breaks {
+		if start < b.end && b.start < end {
+			return b.end, true
+		}
+	}
+	return 0, false
+}
+
+// clockMinutes converts "15:04" or "15:04:05" to minutes since midnight
+func clockMinutes(clock string) (int, error) {
+	clock = strings.TrimSpace(clock)
+	t, err := time.Parse("15:04:05", clock)
+	if err != nil {
+		t, err = time.Parse("15:04", clock)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func firstDuration(durations ...time.Duration) time.Duration {
+	for _, d := range durations {
+		if d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlotGeneratorGenerate(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	at := func(clock string) func() time.Time {
+		return func() time.Time {
+			now, _ := time.ParseInLocation("2006-01-02 15:04", clock, kolkata)
+			return now
+		}
+	}
+	lunch, err := ParseClockRange("13:00-14:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	morning := []SlotSession{{Start: "10:00:00", End: "11:00:00"}}
+
+	tests := []struct {
+		name     string
+		gen      SlotGenerator
+		date     string
+		sessions []SlotSession
+		want     []string
+	}{
+		{
+			name:     "another day keeps every slot",
+			gen:      SlotGenerator{Now: at("2026-10-19 18:00")},
+			date:     "2026-10-20",
+			sessions: morning,
+			want:     []string{"10:00", "10:15", "10:30", "10:45"},
+		},
+		{
+			name:     "today before opening keeps every slot",
+			gen:      SlotGenerator{Now: at("2026-10-20 09:00")},
+			date:     "2026-10-20",
+			sessions: morning,
+			want:     []string{"10:00", "10:15", "10:30", "10:45"},
+		},
+		{
+			name:     "today leaves out slots that have started",
+			gen:      SlotGenerator{Now: at("2026-10-20 10:07")},
+			date:     "2026-10-20",
+			sessions: morning,
+			want:     []string{"10:15", "10:30", "10:45"},
+		},
+		{
+			name:     "today keeps a slot starting now",
+			gen:      SlotGenerator{Now: at("2026-10-20 10:30")},
+			date:     "2026-10-20",
+			sessions: morning,
+			want:     []string{"10:30", "10:45"},
+		},
+		{
+			name:     "today after the session has none",
+			gen:      SlotGenerator{Now: at("2026-10-20 11:00")},
+			date:     "2026-10-20",
+			sessions: morning,
+			want:     []string{},
+		},
+		{
+			name:     "past day has none",
+			gen:      SlotGenerator{Now: at("2026-10-21 08:00")},
+			date:     "2026-10-20",
+			sessions: morning,
+			want:     []string{},
+		},
+		{
+			name:     "slot length and buffer from config",
+			gen:      SlotGenerator{Duration: 20 * time.Minute, Buffer: 10 * time.Minute, Now: at("2026-10-19 18:00")},
+			date:     "2026-10-20",
+			sessions: morning,
+			want:     []string{"10:00", "10:30"},
+		},
+		{
+			name:     "session values override config",
+			gen:      SlotGenerator{Duration: 20 * time.Minute, Now: at("2026-10-19 18:00")},
+			date:     "2026-10-20",
+			sessions: []SlotSession{{Start: "10:00", End: "11:00", Duration: 30 * time.Minute}},
+			want:     []string{"10:00", "10:30"},
+		},
+		{
+			name:     "last slot must fit the session",
+			gen:      SlotGenerator{Duration: 25 * time.Minute, Now: at("2026-10-19 18:00")},
+			date:     "2026-10-20",
+			sessions: morning,
+			want:     []string{"10:00", "10:25"},
+		},
+		{
+			name: "several sessions in order without duplicates",
+			gen:  SlotGenerator{Duration: 30 * time.Minute, Now: at("2026-10-19 18:00")},
+			date: "2026-10-20",
+			sessions: []SlotSession{
+				{Start: "17:00", End: "18:00"},
+				{Start: "09:00", End: "10:00"},
+				{Start: "09:30", End: "10:00"},
+			},
+			want: []string{"09:00", "09:30", "17:00", "17:30"},
+		},
+		{
+			name:     "no slot overlaps a break",
+			gen:      SlotGenerator{Duration: 30 * time.Minute, Breaks: []ClockRange{lunch}, Now: at("2026-10-19 18:00")},
+			date:     "2026-10-20",
+			sessions: []SlotSession{{Start: "12:15", End: "14:30"}},
+			want:     []string{"12:15", "14:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.gen.Location = kolkata
+			slots, err := tt.gen.Generate(tt.date, tt.sessions)
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			got := make([]string, len(slots))
+			for i, s := range slots {
+				got[i] = s.Start.Format("15:04")
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSlotTokensCountTheWholeDay(t *testing.T) {
+	gen := SlotGenerator{
+		Duration: 30 * time.Minute,
+		Now:      func() time.Time { return time.Date(2026, 10, 20, 17, 10, 0, 0, time.UTC) },
+	}
+
+	// Sessions out of order; the morning ones have passed but keep their tokens
+	slots, err := gen.Generate("2026-10-20", []SlotSession{
+		{Start: "17:00", End: "18:00"},
+		{Start: "09:00", End: "10:00"},
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(slots) != 1 {
+		t.Fatalf("got %d slots, want 1", len(slots))
+	}
+	if got := slots[0]; got.Start.Format("15:04") != "17:30" || got.Token != 4 || got.Session != 0 {
+		t.Errorf("got %s token %d session %d, want 17:30 token 4 session 0",
+			got.Start.Format("15:04"), got.Token, got.Session)
+	}
+}
+
+func TestSlotGeneratorAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	gen := SlotGenerator{
+		Duration: 30 * time.Minute,
+		Location: newYork,
+		Now:      func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, newYork) },
+	}
+
+	// Clocks go from 02:00 to 03:00 on 2026-03-08; later slots keep their wall times
+	slots, err := gen.Generate("2026-03-08", []SlotSession{{Start: "00:00", End: "06:00"}})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for i := 1; i < len(slots); i++ {
+		if !slots[i].Start.After(slots[i-1].Start) {
+			t.Fatalf("slots not strictly increasing: %v then %v", slots[i-1].Start, slots[i].Start)
+		}
+	}
+	last := slots[len(slots)-1]
+	if got := last.Start.Format("15:04"); got != "05:30" {
+		t.Errorf("last slot %s, want 05:30", got)
+	}
+}
+
+func TestGenerateRejectsBadInput(t *testing.T) {
+	gen := SlotGenerator{Now: func() time.Time { return time.Time{} }}
+	if _, err := gen.Generate("20-10-2026", nil); err == nil {
+		t.Error("expected an error for a malformed date")
+	}
+	if _, err := gen.Generate("2026-10-20", []SlotSession{{Start: "10am", End: "11:00"}}); err == nil {
+		t.Error("expected an error when every session is malformed")
+	}
+	if _, err := ParseClockRange("14:00-13:00"); err == nil {
+		t.Error("expected an error for a break ending before it starts")
+	}
+}
+
+func TestGenerateSkipsMalformedSessions(t *testing.T) {
+	gen := SlotGenerator{Duration: 30 * time.Minute, Now: func() time.Time { return time.Time{} }}
+	slots, err := gen.Generate("2026-10-20", []SlotSession{
+		{Start: "10am", End: "11:00"},
+		{Start: "14:00", End: "15:00"},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(slots) != 2 || slots[0].Start.Format("15:04") != "14:00" || slots[0].Session != 1 {
+		t.Errorf("Generate() = %+v, want the two slots of the second session", slots)
+	}
+}